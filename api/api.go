@@ -0,0 +1,294 @@
+// Package api serves tasks and projects over a JSON REST API, so editor plugins and
+// dashboards can read and write the same database as the TUI. Every route but the
+// OpenAPI document needs a bearer token.
+//
+// Single tasks and projects are returned with an ETag, a hash of their JSON. Sending
+// it back in an If-Match header with a PATCH or DELETE makes the request fail with 412
+// Precondition Failed if the item has changed since, instead of overwriting the change.
+package api
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	_ "embed"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/dsrosen6/yata/config"
+	"github.com/dsrosen6/yata/dates"
+	"github.com/dsrosen6/yata/filter"
+	"github.com/dsrosen6/yata/models"
+)
+
+// BasePath prefixes every route.
+const BasePath = "/api/v1"
+
+const (
+	defaultLimit = 100
+	maxLimit     = 1000
+	maxBodyBytes = 1 << 20
+)
+
+//go:embed openapi.yaml
+var openAPI []byte
+
+type (
+	Server struct {
+		stores *models.AllRepos
+		cfg    *config.Config
+		token  string
+		dates  *dates.Parser
+	}
+
+	// handlerFunc is an http.HandlerFunc that returns its error, which is written as
+	// the response.
+	handlerFunc func(w http.ResponseWriter, r *http.Request) error
+
+	// apiError is an error with the status it should be returned with.
+	apiError struct {
+		status int
+		msg    string
+	}
+
+	// list is a page of a list, with the offset of the next page if there is one.
+	list[T any] struct {
+		Items      []T  `json:"items"`
+		Total      int  `json:"total"`
+		Offset     int  `json:"offset"`
+		Limit      int  `json:"limit"`
+		NextOffset *int `json:"next_offset"`
+	}
+
+	// optional is a field of a request body that can be left out, so a PATCH only changes
+	// the fields it includes. Set is true if the field was given, even as null.
+	optional[T any] struct {
+		Set   bool
+		Value T
+	}
+)
+
+// New returns a server for the stores that accepts requests with the token.
+func New(stores *models.AllRepos, cfg *config.Config, token string) *Server {
+	return &Server{
+		stores: stores,
+		cfg:    cfg,
+		token:  token,
+		dates:  dates.NewParser(),
+	}
+}
+
+// Handler returns the server's routes.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET "+BasePath+"/openapi.yaml", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/yaml")
+		_, _ = w.Write(openAPI)
+	})
+
+	routes := map[string]handlerFunc{
+		"GET /tasks":            s.listTasks,
+		"POST /tasks":           s.createTask,
+		"GET /tasks/{id}":       s.getTask,
+		"PATCH /tasks/{id}":     s.updateTask,
+		"DELETE /tasks/{id}":    s.deleteTask,
+		"GET /projects":         s.listProjects,
+		"POST /projects":        s.createProject,
+		"GET /projects/{id}":    s.getProject,
+		"PATCH /projects/{id}":  s.updateProject,
+		"DELETE /projects/{id}": s.deleteProject,
+	}
+	for pattern, h := range routes {
+		method, path, _ := strings.Cut(pattern, " ")
+		mux.Handle(method+" "+BasePath+path, s.handle(h))
+	}
+
+	return mux
+}
+
+// handle checks a request's token and writes any error the handler returns.
+func (s *Server) handle(h handlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !s.authorized(r) {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="yata"`)
+			writeError(w, http.StatusUnauthorized, "missing or invalid bearer token")
+			return
+		}
+
+		r.Body = http.MaxBytesReader(w, r.Body, maxBodyBytes)
+		if err := h(w, r); err != nil {
+			s.writeErr(w, r, err)
+		}
+	})
+}
+
+func (s *Server) authorized(r *http.Request) bool {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) == 1
+}
+
+func (s *Server) writeErr(w http.ResponseWriter, r *http.Request, err error) {
+	var (
+		ae *apiError
+		fe *filter.Error
+	)
+	switch {
+	case errors.As(err, &ae):
+		writeError(w, ae.status, ae.msg)
+	case errors.As(err, &fe):
+		writeError(w, http.StatusBadRequest, "filter "+fe.Error())
+	case errors.Is(err, models.ErrNotFound):
+		writeError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, models.ErrProjectNotFound), errors.Is(err, models.ErrEmptyProjectTitle):
+		writeError(w, http.StatusUnprocessableEntity, err.Error())
+	case errors.Is(err, models.ErrProjectCycle), errors.Is(err, models.ErrDependencyCycle):
+		writeError(w, http.StatusConflict, err.Error())
+	default:
+		slog.Error("api request failed", "method", r.Method, "path", r.URL.Path, "error", err)
+		writeError(w, http.StatusInternalServerError, "internal error")
+	}
+}
+
+func (e *apiError) Error() string {
+	return e.msg
+}
+
+func errorf(status int, format string, args ...any) error {
+	return &apiError{status: status, msg: fmt.Sprintf(format, args...)}
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		slog.Error("writing api response", "error", err)
+	}
+}
+
+// writeItem writes a single task or project with its ETag, or 304 Not Modified if the
+// client already has it.
+func writeItem(w http.ResponseWriter, r *http.Request, status int, v any) {
+	tag := etag(v)
+	w.Header().Set("ETag", tag)
+	if r.Method == http.MethodGet && matchesETag(r.Header.Get("If-None-Match"), tag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	writeJSON(w, status, v)
+}
+
+func (o *optional[T]) UnmarshalJSON(b []byte) error {
+	o.Set = true
+	return json.Unmarshal(b, &o.Value)
+}
+
+// decode reads a JSON request body into v, rejecting fields it doesn't know.
+func decode(r *http.Request, v any) error {
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return errorf(http.StatusBadRequest, "invalid request body: %v", err)
+	}
+
+	return nil
+}
+
+// etag is a strong ETag for a task or project, a hash of its JSON.
+func etag(v any) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		// the types passed in always marshal
+		panic(fmt.Sprintf("marshaling %T for etag: %v", v, err))
+	}
+
+	sum := sha256.Sum256(b)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// matchesETag reports whether an If-Match or If-None-Match header lists the ETag.
+func matchesETag(header, tag string) bool {
+	for t := range strings.SplitSeq(header, ",") {
+		t = strings.TrimPrefix(strings.TrimSpace(t), "W/")
+		if t == "*" || t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// checkIfMatch fails with 412 if the request has an If-Match header that doesn't match
+// the current version of what it's changing. Requests without one always go ahead.
+func checkIfMatch(r *http.Request, current any) error {
+	h := r.Header.Get("If-Match")
+	if h == "" || matchesETag(h, etag(current)) {
+		return nil
+	}
+
+	return errorf(http.StatusPreconditionFailed, "changed since it was fetched, the current ETag is %s", etag(current))
+}
+
+// pathID reads the {id} in a request's path.
+func pathID(r *http.Request) (int64, error) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		return 0, errorf(http.StatusBadRequest, "invalid ID %q", r.PathValue("id"))
+	}
+
+	return id, nil
+}
+
+// paginate returns the page of items asked for by the limit and offset query parameters.
+func paginate[T any](r *http.Request, items []T) (list[T], error) {
+	limit, err := queryInt(r, "limit", defaultLimit)
+	if err != nil {
+		return list[T]{}, err
+	}
+	if limit < 1 || limit > maxLimit {
+		return list[T]{}, errorf(http.StatusBadRequest, "limit must be between 1 and %d", maxLimit)
+	}
+
+	offset, err := queryInt(r, "offset", 0)
+	if err != nil {
+		return list[T]{}, err
+	}
+	if offset < 0 {
+		return list[T]{}, errorf(http.StatusBadRequest, "offset can't be negative")
+	}
+
+	l := list[T]{Items: []T{}, Total: len(items), Offset: offset, Limit: limit}
+	if offset < len(items) {
+		end := min(offset+limit, len(items))
+		l.Items = items[offset:end]
+		if end < len(items) {
+			l.NextOffset = &end
+		}
+	}
+
+	return l, nil
+}
+
+func queryInt(r *http.Request, name string, def int) (int, error) {
+	v := r.URL.Query().Get(name)
+	if v == "" {
+		return def, nil
+	}
+
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, errorf(http.StatusBadRequest, "%s must be a number", name)
+	}
+
+	return n, nil
+}
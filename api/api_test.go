@@ -0,0 +1,465 @@
+package api_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/dsrosen6/yata/api"
+	"github.com/dsrosen6/yata/config"
+	"github.com/dsrosen6/yata/sqlitedb"
+)
+
+const testToken = "test-token"
+
+type (
+	client struct {
+		t    *testing.T
+		base string
+	}
+
+	task struct {
+		ID         int64    `json:"id"`
+		Title      string   `json:"title"`
+		ProjectID  *int64   `json:"project_id"`
+		Project    string   `json:"project"`
+		ParentID   *int64   `json:"parent_id"`
+		Complete   bool     `json:"complete"`
+		Priority   string   `json:"priority"`
+		Tags       []string `json:"tags"`
+		Recurrence string   `json:"recurrence"`
+	}
+
+	project struct {
+		ID       int64  `json:"id"`
+		Title    string `json:"title"`
+		Path     string `json:"path"`
+		ParentID *int64 `json:"parent_id"`
+	}
+
+	page[T any] struct {
+		Items      []T  `json:"items"`
+		Total      int  `json:"total"`
+		Offset     int  `json:"offset"`
+		Limit      int  `json:"limit"`
+		NextOffset *int `json:"next_offset"`
+	}
+)
+
+// newClient serves the API over a fresh database in a temporary directory.
+func newClient(t *testing.T) *client {
+	t.Helper()
+	h, err := sqlitedb.NewHandler(os.DirFS("../migrations"), filepath.Join(t.TempDir(), "app.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = h.Close() })
+
+	stores, err := h.InitStores(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srv := httptest.NewServer(api.New(stores, &config.Config{}, testToken).Handler())
+	t.Cleanup(srv.Close)
+	return &client{t: t, base: srv.URL + api.BasePath}
+}
+
+// do sends a request with the test token, and headers given as name and value pairs.
+func (c *client) do(method, path, body string, headers ...string) (*http.Response, []byte) {
+	c.t.Helper()
+	var r io.Reader
+	if body != "" {
+		r = strings.NewReader(body)
+	}
+
+	req, err := http.NewRequest(method, c.base+path, r)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	return resp, b
+}
+
+// expect sends a request, fails the test if it doesn't get the status, and decodes the
+// response into v if it isn't nil.
+func (c *client) expect(status int, v any, method, path, body string, headers ...string) *http.Response {
+	c.t.Helper()
+	resp, b := c.do(method, path, body, headers...)
+	if resp.StatusCode != status {
+		c.t.Fatalf("%s %s = %d %s, want %d", method, path, resp.StatusCode, b, status)
+	}
+
+	if v != nil {
+		if err := json.Unmarshal(b, v); err != nil {
+			c.t.Fatalf("%s %s: decoding %s: %v", method, path, b, err)
+		}
+	}
+	return resp
+}
+
+func TestUnauthorized(t *testing.T) {
+	c := newClient(t)
+
+	for _, auth := range []string{"", "Bearer wrong", "Basic " + testToken, testToken} {
+		req, err := http.NewRequest(http.MethodGet, c.base+"/tasks", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if auth != "" {
+			req.Header.Set("Authorization", auth)
+		}
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = resp.Body.Close()
+
+		if resp.StatusCode != http.StatusUnauthorized {
+			t.Errorf("Authorization %q = %d, want 401", auth, resp.StatusCode)
+		}
+		if resp.Header.Get("WWW-Authenticate") == "" {
+			t.Errorf("Authorization %q: no WWW-Authenticate header", auth)
+		}
+	}
+
+	// the OpenAPI document is the one thing that doesn't need a token
+	resp, err := http.Get(c.base + "/openapi.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("GET /openapi.yaml = %d, want 200", resp.StatusCode)
+	}
+}
+
+func TestTaskCRUD(t *testing.T) {
+	c := newClient(t)
+
+	var created task
+	resp := c.expect(http.StatusCreated, &created, "POST", "/tasks",
+		`{"title": " buy milk ", "priority": "high", "tags": ["errand"]}`)
+	if created.Title != "buy milk" || created.Priority != "high" || len(created.Tags) != 1 || created.Tags[0] != "errand" {
+		t.Errorf("created %+v", created)
+	}
+	if want := fmt.Sprintf("%s/tasks/%d", api.BasePath, created.ID); resp.Header.Get("Location") != want {
+		t.Errorf("Location = %q, want %q", resp.Header.Get("Location"), want)
+	}
+
+	var sub task
+	c.expect(http.StatusCreated, &sub, "POST", "/tasks", fmt.Sprintf(`{"title": "oat milk", "parent_id": %d}`, created.ID))
+	if sub.ParentID == nil || *sub.ParentID != created.ID {
+		t.Errorf("subtask parent = %v, want %d", sub.ParentID, created.ID)
+	}
+
+	var got task
+	c.expect(http.StatusOK, &got, "GET", fmt.Sprintf("/tasks/%d", created.ID), "")
+	if got.Title != "buy milk" {
+		t.Errorf("got %+v", got)
+	}
+
+	var updated task
+	c.expect(http.StatusOK, &updated, "PATCH", fmt.Sprintf("/tasks/%d", created.ID), `{"title": "buy bread", "tags": []}`)
+	if updated.Title != "buy bread" || updated.Priority != "high" || len(updated.Tags) != 0 {
+		t.Errorf("updated %+v", updated)
+	}
+
+	c.expect(http.StatusNoContent, nil, "DELETE", fmt.Sprintf("/tasks/%d", created.ID), "")
+	c.expect(http.StatusNotFound, nil, "GET", fmt.Sprintf("/tasks/%d", created.ID), "")
+	c.expect(http.StatusNotFound, nil, "GET", fmt.Sprintf("/tasks/%d", sub.ID), "")
+	c.expect(http.StatusNotFound, nil, "PATCH", fmt.Sprintf("/tasks/%d", created.ID), `{"title": "x"}`)
+	c.expect(http.StatusNotFound, nil, "DELETE", fmt.Sprintf("/tasks/%d", created.ID), "")
+}
+
+func TestTaskBadRequests(t *testing.T) {
+	c := newClient(t)
+
+	var created task
+	c.expect(http.StatusCreated, &created, "POST", "/tasks", `{"title": "a"}`)
+	id := fmt.Sprintf("/tasks/%d", created.ID)
+
+	tests := []struct {
+		method, path, body string
+		status             int
+	}{
+		{"POST", "/tasks", `{}`, http.StatusBadRequest},
+		{"POST", "/tasks", `{"title": "  "}`, http.StatusBadRequest},
+		{"POST", "/tasks", `{"title": "a", "colour": "red"}`, http.StatusBadRequest},
+		{"POST", "/tasks", `{"title": "a", "due": "someday"}`, http.StatusBadRequest},
+		{"POST", "/tasks", `{"title": "a", "priority": "very"}`, http.StatusBadRequest},
+		{"POST", "/tasks", `{"title": "a", "project_id": 99}`, http.StatusUnprocessableEntity},
+		{"POST", "/tasks", `{"title": "a", "parent_id": 99}`, http.StatusUnprocessableEntity},
+		{"PATCH", id, `{"title": ""}`, http.StatusBadRequest},
+		{"PATCH", id, `{"parent_id": 1}`, http.StatusBadRequest},
+		{"PATCH", id, `not json`, http.StatusBadRequest},
+		{"GET", "/tasks/abc", ``, http.StatusBadRequest},
+		{"GET", "/tasks?filter=due%3E%3D", ``, http.StatusBadRequest},
+		{"GET", "/tasks?sort=colour", ``, http.StatusBadRequest},
+		{"GET", "/tasks?order=sideways", ``, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		if resp, b := c.do(tt.method, tt.path, tt.body); resp.StatusCode != tt.status {
+			t.Errorf("%s %s %s = %d %s, want %d", tt.method, tt.path, tt.body, resp.StatusCode, b, tt.status)
+		}
+	}
+}
+
+func TestCompleteRepeatingTask(t *testing.T) {
+	c := newClient(t)
+
+	var created task
+	c.expect(http.StatusCreated, &created, "POST", "/tasks",
+		`{"title": "water plants", "due": "2026-03-14", "recurrence": "every week", "tags": ["garden"]}`)
+
+	var done task
+	c.expect(http.StatusOK, &done, "PATCH", fmt.Sprintf("/tasks/%d", created.ID), `{"complete": true}`)
+	if !done.Complete || done.Recurrence != "" {
+		t.Errorf("completed %+v, want complete without a rule", done)
+	}
+
+	var open page[task]
+	c.expect(http.StatusOK, &open, "GET", "/tasks", "")
+	if len(open.Items) != 1 {
+		t.Fatalf("open tasks = %+v, want the next occurrence", open.Items)
+	}
+	if next := open.Items[0]; next.ID == created.ID || next.Recurrence == "" || len(next.Tags) != 1 || next.Tags[0] != "garden" {
+		t.Errorf("next occurrence = %+v", next)
+	}
+}
+
+func TestProjectCRUD(t *testing.T) {
+	c := newClient(t)
+
+	var work project
+	c.expect(http.StatusCreated, &work, "POST", "/projects", `{"title": "work"}`)
+
+	var clients project
+	c.expect(http.StatusCreated, &clients, "POST", "/projects", `{"title": "clients", "parent": "work"}`)
+	if clients.Path != "work/clients" || clients.ParentID == nil || *clients.ParentID != work.ID {
+		t.Errorf("created %+v", clients)
+	}
+
+	var tk task
+	c.expect(http.StatusCreated, &tk, "POST", "/tasks", `{"title": "invoice", "project": "work/clients"}`)
+	if tk.ProjectID == nil || *tk.ProjectID != clients.ID {
+		t.Errorf("task project = %v, want %d", tk.ProjectID, clients.ID)
+	}
+
+	var renamed project
+	c.expect(http.StatusOK, &renamed, "PATCH", fmt.Sprintf("/projects/%d", clients.ID), `{"title": "customers"}`)
+	if renamed.Path != "work/customers" {
+		t.Errorf("renamed path = %q", renamed.Path)
+	}
+
+	c.expect(http.StatusConflict, nil, "PATCH", fmt.Sprintf("/projects/%d", work.ID), fmt.Sprintf(`{"parent_id": %d}`, clients.ID))
+	c.expect(http.StatusBadRequest, nil, "POST", "/projects", `{"title": "a/b"}`)
+
+	c.expect(http.StatusNoContent, nil, "DELETE", fmt.Sprintf("/projects/%d", work.ID), "")
+	c.expect(http.StatusNotFound, nil, "GET", fmt.Sprintf("/projects/%d", work.ID), "")
+	c.expect(http.StatusNotFound, nil, "GET", fmt.Sprintf("/projects/%d", clients.ID), "")
+	c.expect(http.StatusNotFound, nil, "GET", fmt.Sprintf("/tasks/%d", tk.ID), "")
+
+	var all page[project]
+	c.expect(http.StatusOK, &all, "GET", "/projects", "")
+	if all.Total != 0 {
+		t.Errorf("projects left = %+v", all.Items)
+	}
+}
+
+func TestPagination(t *testing.T) {
+	c := newClient(t)
+	for i := range 5 {
+		c.expect(http.StatusCreated, nil, "POST", "/tasks", fmt.Sprintf(`{"title": "task %d"}`, i))
+	}
+
+	tests := []struct {
+		query      string
+		items      int
+		nextOffset *int
+	}{
+		{"", 5, nil},
+		{"?limit=2", 2, ptr(2)},
+		{"?limit=2&offset=2", 2, ptr(4)},
+		{"?limit=2&offset=4", 1, nil},
+		{"?offset=5", 0, nil},
+		{"?offset=50", 0, nil},
+		{"?limit=1000", 5, nil},
+	}
+
+	for _, tt := range tests {
+		var p page[task]
+		c.expect(http.StatusOK, &p, "GET", "/tasks"+tt.query, "")
+		if len(p.Items) != tt.items || p.Total != 5 {
+			t.Errorf("%s: %d items of %d, want %d of 5", tt.query, len(p.Items), p.Total, tt.items)
+		}
+		if (p.NextOffset == nil) != (tt.nextOffset == nil) || (p.NextOffset != nil && *p.NextOffset != *tt.nextOffset) {
+			t.Errorf("%s: next offset = %v, want %v", tt.query, deref(p.NextOffset), deref(tt.nextOffset))
+		}
+	}
+
+	for _, q := range []string{"?limit=0", "?limit=-1", "?limit=1001", "?limit=ten", "?offset=-1", "?offset=x"} {
+		for _, path := range []string{"/tasks", "/projects"} {
+			c.expect(http.StatusBadRequest, nil, "GET", path+q, "")
+		}
+	}
+}
+
+func TestIfMatch(t *testing.T) {
+	c := newClient(t)
+
+	var tk task
+	resp := c.expect(http.StatusCreated, &tk, "POST", "/tasks", `{"title": "a"}`)
+	path := fmt.Sprintf("/tasks/%d", tk.ID)
+	stale := resp.Header.Get("ETag")
+	if stale == "" {
+		t.Fatal("no ETag on the created task")
+	}
+
+	resp = c.expect(http.StatusOK, nil, "PATCH", path, `{"title": "b"}`, "If-Match", stale)
+	current := resp.Header.Get("ETag")
+	if current == stale {
+		t.Fatal("ETag didn't change with the task")
+	}
+
+	c.expect(http.StatusPreconditionFailed, nil, "PATCH", path, `{"title": "c"}`, "If-Match", stale)
+	c.expect(http.StatusPreconditionFailed, nil, "DELETE", path, "", "If-Match", stale)
+
+	var got task
+	c.expect(http.StatusOK, &got, "GET", path, "")
+	if got.Title != "b" {
+		t.Errorf("title = %q after a failed precondition, want b", got.Title)
+	}
+
+	c.expect(http.StatusOK, nil, "PATCH", path, `{"notes": "n"}`, "If-Match", `"other", `+current)
+	c.expect(http.StatusNoContent, nil, "DELETE", path, "", "If-Match", "*")
+
+	var p project
+	resp = c.expect(http.StatusCreated, &p, "POST", "/projects", `{"title": "p"}`)
+	ppath := fmt.Sprintf("/projects/%d", p.ID)
+	stale = resp.Header.Get("ETag")
+	c.expect(http.StatusOK, nil, "PATCH", ppath, `{"title": "q"}`, "If-Match", stale)
+	c.expect(http.StatusPreconditionFailed, nil, "PATCH", ppath, `{"title": "r"}`, "If-Match", stale)
+	c.expect(http.StatusPreconditionFailed, nil, "DELETE", ppath, "", "If-Match", stale)
+}
+
+// TestFailedChangeIsUndone checks a change that fails part way, like on a project that
+// doesn't exist, leaves nothing of itself behind.
+func TestFailedChangeIsUndone(t *testing.T) {
+	c := newClient(t)
+
+	var tk task
+	c.expect(http.StatusCreated, &tk, "POST", "/tasks", `{"title": "a", "tags": ["x"]}`)
+	path := fmt.Sprintf("/tasks/%d", tk.ID)
+
+	for _, body := range []string{
+		`{"title": "b", "complete": true, "tags": ["y"], "project_id": 99}`,
+		`{"title": "b", "complete": true, "tags": ["y"], "project": "missing"}`,
+		`{"title": "b", "project": "work/"}`,
+	} {
+		c.expect(http.StatusUnprocessableEntity, nil, "PATCH", path, body)
+	}
+
+	var got task
+	c.expect(http.StatusOK, &got, "GET", path, "")
+	if got.Title != "a" || got.Complete || len(got.Tags) != 1 || got.Tags[0] != "x" {
+		t.Errorf("task after failed changes = %+v, want it unchanged", got)
+	}
+
+	c.expect(http.StatusUnprocessableEntity, nil, "POST", "/tasks", `{"title": "c", "tags": ["z"], "project": "missing"}`)
+
+	var all page[task]
+	c.expect(http.StatusOK, &all, "GET", "/tasks?all=true", "")
+	if all.Total != 1 {
+		t.Errorf("tasks = %+v, want only the first", all.Items)
+	}
+}
+
+// TestIfMatchRace sends the same conditional change many times at once, and checks only
+// one of them is made.
+func TestIfMatchRace(t *testing.T) {
+	const requests = 8
+	c := newClient(t)
+
+	var tk task
+	tag := c.expect(http.StatusCreated, &tk, "POST", "/tasks", `{"title": "a"}`).Header.Get("ETag")
+	path := fmt.Sprintf("/tasks/%d", tk.ID)
+
+	statuses := make(chan int, requests)
+	var wg sync.WaitGroup
+	for i := range requests {
+		wg.Go(func() {
+			resp, _ := c.do("PATCH", path, fmt.Sprintf(`{"title": "b%d"}`, i), "If-Match", tag)
+			statuses <- resp.StatusCode
+		})
+	}
+	wg.Wait()
+	close(statuses)
+
+	counts := make(map[int]int)
+	for s := range statuses {
+		counts[s]++
+	}
+	if counts[http.StatusOK] != 1 || counts[http.StatusPreconditionFailed] != requests-1 {
+		t.Errorf("statuses = %v, want one 200 and the rest 412", counts)
+	}
+}
+
+func TestIfNoneMatch(t *testing.T) {
+	c := newClient(t)
+
+	var tk task
+	c.expect(http.StatusCreated, &tk, "POST", "/tasks", `{"title": "a"}`)
+	path := fmt.Sprintf("/tasks/%d", tk.ID)
+
+	tag := c.expect(http.StatusOK, nil, "GET", path, "").Header.Get("ETag")
+	resp, b := c.do("GET", path, "", "If-None-Match", tag)
+	if resp.StatusCode != http.StatusNotModified || len(b) != 0 {
+		t.Errorf("unchanged task = %d %q, want 304 with no body", resp.StatusCode, b)
+	}
+	if resp.Header.Get("ETag") != tag {
+		t.Errorf("304 ETag = %q, want %q", resp.Header.Get("ETag"), tag)
+	}
+
+	c.expect(http.StatusOK, nil, "PATCH", path, `{"title": "b"}`, "If-None-Match", tag)
+	c.expect(http.StatusOK, nil, "GET", path, "", "If-None-Match", tag)
+
+	var p project
+	c.expect(http.StatusCreated, &p, "POST", "/projects", `{"title": "p"}`)
+	ppath := fmt.Sprintf("/projects/%d", p.ID)
+	tag = c.expect(http.StatusOK, nil, "GET", ppath, "").Header.Get("ETag")
+	c.expect(http.StatusNotModified, nil, "GET", ppath, "", "If-None-Match", tag)
+}
+
+func ptr(n int) *int { return &n }
+
+func deref(n *int) any {
+	if n == nil {
+		return nil
+	}
+	return *n
+}
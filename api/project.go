@@ -0,0 +1,254 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dsrosen6/yata/models"
+)
+
+type (
+	projectJSON struct {
+		ID        int64     `json:"id"`
+		Title     string    `json:"title"`
+		Path      string    `json:"path"`
+		ParentID  *int64    `json:"parent_id"`
+		Position  float64   `json:"position"`
+		CreatedAt time.Time `json:"created_at"`
+		UpdatedAt time.Time `json:"updated_at"`
+	}
+
+	// projectInput is the body of a request creating or changing a project. The parent
+	// can be given by ID or by path.
+	projectInput struct {
+		Title    optional[string] `json:"title"`
+		ParentID optional[*int64] `json:"parent_id"`
+		Parent   optional[string] `json:"parent"`
+	}
+)
+
+func (s *Server) listProjects(w http.ResponseWriter, r *http.Request) error {
+	projects, err := s.stores.Projects.ListAll(r.Context())
+	if err != nil {
+		return fmt.Errorf("listing projects: %w", err)
+	}
+
+	page, err := paginate(r, projects)
+	if err != nil {
+		return err
+	}
+
+	out := list[projectJSON]{Items: make([]projectJSON, 0, len(page.Items)), Total: page.Total, Offset: page.Offset, Limit: page.Limit, NextOffset: page.NextOffset}
+	for _, p := range page.Items {
+		out.Items = append(out.Items, projectToJSON(projects, p))
+	}
+
+	writeJSON(w, http.StatusOK, out)
+	return nil
+}
+
+func (s *Server) getProject(w http.ResponseWriter, r *http.Request) error {
+	p, err := s.project(s.stores, r)
+	if err != nil {
+		return err
+	}
+
+	return s.writeProject(w, r, http.StatusOK, p.ID)
+}
+
+func (s *Server) createProject(w http.ResponseWriter, r *http.Request) error {
+	var in projectInput
+	if err := decode(r, &in); err != nil {
+		return err
+	}
+	if err := validateProjectTitle(in.Title, true); err != nil {
+		return err
+	}
+
+	ctx := r.Context()
+	var created *models.Project
+	err := s.stores.InTx(ctx, func(st *models.AllRepos) error {
+		parentID, err := s.inputParentID(ctx, st, in)
+		if err != nil {
+			return err
+		}
+
+		if created, err = st.Projects.Create(ctx, &models.Project{Title: strings.TrimSpace(in.Title.Value), ParentID: parentID}); err != nil {
+			return fmt.Errorf("creating project: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Location", fmt.Sprintf("%s/projects/%d", BasePath, created.ID))
+	return s.writeProject(w, r, http.StatusCreated, created.ID)
+}
+
+func (s *Server) updateProject(w http.ResponseWriter, r *http.Request) error {
+	var in projectInput
+	if err := decode(r, &in); err != nil {
+		return err
+	}
+	if err := validateProjectTitle(in.Title, false); err != nil {
+		return err
+	}
+
+	ctx := r.Context()
+	var id int64
+	err := s.stores.InTx(ctx, func(st *models.AllRepos) error {
+		p, err := s.project(st, r)
+		if err != nil {
+			return err
+		}
+		id = p.ID
+		if err := s.checkProjectIfMatch(ctx, st, r, p); err != nil {
+			return err
+		}
+
+		after := *p
+		if in.Title.Set {
+			after.Title = strings.TrimSpace(in.Title.Value)
+		}
+		if in.ParentID.Set || in.Parent.Set {
+			if after.ParentID, err = s.inputParentID(ctx, st, in); err != nil {
+				return err
+			}
+		}
+
+		if _, err := st.Projects.Update(ctx, &after); err != nil {
+			return fmt.Errorf("updating project %d: %w", p.ID, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.writeProject(w, r, http.StatusOK, id)
+}
+
+// deleteProject moves a project, its sub-projects and their tasks to the trash.
+func (s *Server) deleteProject(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	err := s.stores.InTx(ctx, func(st *models.AllRepos) error {
+		p, err := s.project(st, r)
+		if err != nil {
+			return err
+		}
+		if err := s.checkProjectIfMatch(ctx, st, r, p); err != nil {
+			return err
+		}
+
+		if err := st.Projects.Trash(ctx, p.ID); err != nil {
+			return fmt.Errorf("trashing project %d: %w", p.ID, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// project gets the project in the request's path. Trashed projects aren't found.
+func (s *Server) project(st *models.AllRepos, r *http.Request) (*models.Project, error) {
+	id, err := pathID(r)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := st.Projects.Get(r.Context(), id)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.DeletedAt != nil {
+		return nil, fmt.Errorf("project %d: %w", id, models.ErrNotFound)
+	}
+
+	return p, nil
+}
+
+// writeProject gets a project again, after any changes, and writes it.
+func (s *Server) writeProject(w http.ResponseWriter, r *http.Request, status int, id int64) error {
+	projects, err := s.stores.Projects.ListAll(r.Context())
+	if err != nil {
+		return fmt.Errorf("listing projects: %w", err)
+	}
+
+	p, err := s.stores.Projects.Get(r.Context(), id)
+	if err != nil {
+		return err
+	}
+
+	writeItem(w, r, status, projectToJSON(projects, p))
+	return nil
+}
+
+// checkProjectIfMatch checks the request's If-Match header against the project as read
+// with st, which should be the transaction making the change.
+func (s *Server) checkProjectIfMatch(ctx context.Context, st *models.AllRepos, r *http.Request, p *models.Project) error {
+	if r.Header.Get("If-Match") == "" {
+		return nil
+	}
+
+	projects, err := st.Projects.ListAll(ctx)
+	if err != nil {
+		return fmt.Errorf("listing projects: %w", err)
+	}
+
+	return checkIfMatch(r, projectToJSON(projects, p))
+}
+
+// inputParentID resolves the parent given by ID or path in a request body. Leaving both
+// out, a null ID or an empty path mean a top-level project.
+func (s *Server) inputParentID(ctx context.Context, st *models.AllRepos, in projectInput) (*int64, error) {
+	switch {
+	case in.ParentID.Set && in.Parent.Set:
+		return nil, errorf(http.StatusBadRequest, "give either parent_id or parent, not both")
+
+	case in.ParentID.Set && in.ParentID.Value != nil:
+		p, err := st.Projects.Get(ctx, *in.ParentID.Value)
+		if err != nil || p.DeletedAt != nil {
+			return nil, errorf(http.StatusUnprocessableEntity, "no project with ID %d", *in.ParentID.Value)
+		}
+		return &p.ID, nil
+
+	case in.Parent.Set:
+		return st.ResolveProjectPath(ctx, in.Parent.Value)
+	}
+
+	return nil, nil
+}
+
+func validateProjectTitle(title optional[string], required bool) error {
+	switch {
+	case !title.Set && !required:
+		return nil
+	case strings.TrimSpace(title.Value) == "":
+		return errorf(http.StatusBadRequest, "title is required")
+	case strings.Contains(title.Value, models.ProjectPathSep):
+		return errorf(http.StatusBadRequest, "project titles can't contain %q", models.ProjectPathSep)
+	}
+
+	return nil
+}
+
+func projectToJSON(projects []*models.Project, p *models.Project) projectJSON {
+	return projectJSON{
+		ID:        p.ID,
+		Title:     p.Title,
+		Path:      models.ProjectPath(projects, p.ID),
+		ParentID:  p.ParentID,
+		Position:  p.Position,
+		CreatedAt: p.CreatedAt,
+		UpdatedAt: p.UpdatedAt,
+	}
+}
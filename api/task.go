@@ -0,0 +1,468 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dsrosen6/yata/filter"
+	"github.com/dsrosen6/yata/models"
+)
+
+type (
+	taskJSON struct {
+		ID          int64      `json:"id"`
+		Title       string     `json:"title"`
+		ProjectID   *int64     `json:"project_id"`
+		Project     string     `json:"project"`
+		ParentID    *int64     `json:"parent_id"`
+		Complete    bool       `json:"complete"`
+		Due         *time.Time `json:"due"`
+		Priority    string     `json:"priority"`
+		Tags        []string   `json:"tags"`
+		Recurrence  string     `json:"recurrence"`
+		Notes       string     `json:"notes"`
+		Position    float64    `json:"position"`
+		CreatedAt   time.Time  `json:"created_at"`
+		UpdatedAt   time.Time  `json:"updated_at"`
+		CompletedAt *time.Time `json:"completed_at"`
+		ArchivedAt  *time.Time `json:"archived_at"`
+	}
+
+	// taskInput is the body of a request creating or changing a task. The project can be
+	// given by ID or by path, and the due date as a timestamp or a phrase like tomorrow.
+	taskInput struct {
+		Title      optional[string]   `json:"title"`
+		ProjectID  optional[*int64]   `json:"project_id"`
+		Project    optional[string]   `json:"project"`
+		ParentID   optional[*int64]   `json:"parent_id"`
+		Complete   optional[bool]     `json:"complete"`
+		Due        optional[*string]  `json:"due"`
+		Priority   optional[string]   `json:"priority"`
+		Tags       optional[[]string] `json:"tags"`
+		Recurrence optional[string]   `json:"recurrence"`
+		Notes      optional[string]   `json:"notes"`
+	}
+
+	// taskContext is what's needed to show tasks beyond the tasks themselves.
+	taskContext struct {
+		projects []*models.Project
+		tags     map[int64][]string
+	}
+)
+
+func (s *Server) listTasks(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	q := r.URL.Query()
+
+	params := models.SortParams{SortBy: models.SortByComplete}
+	if v := q.Get("sort"); v != "" {
+		sb, err := models.ParseSortBy(v)
+		if err != nil {
+			return errorf(http.StatusBadRequest, "%v", err)
+		}
+		params.SortBy = sb
+	}
+	switch strings.ToLower(q.Get("order")) {
+	case "", "asc":
+	case "desc":
+		params.SortOrder = models.SortOrderDesc
+	default:
+		return errorf(http.StatusBadRequest, "order must be asc or desc")
+	}
+
+	var expr filter.Expr
+	if v := q.Get("filter"); v != "" {
+		f, err := filter.Parse(v)
+		if err != nil {
+			return err
+		}
+		expr = f.Expr
+	}
+	if v := q.Get("project"); v != "" {
+		cond := filter.Cond{Field: filter.Project, Op: filter.Is, Value: v}
+		if expr == nil {
+			expr = cond
+		} else {
+			expr = filter.And{X: expr, Y: cond}
+		}
+	}
+
+	var (
+		tasks []*models.Task
+		err   error
+	)
+	if expr == nil {
+		tasks, err = s.stores.Tasks.ListAll(ctx)
+	} else {
+		tasks, err = s.stores.Tasks.Query(ctx, &filter.Filter{Expr: expr})
+	}
+	if err != nil {
+		return fmt.Errorf("listing tasks: %w", err)
+	}
+
+	if all, _ := strconv.ParseBool(q.Get("all")); !all {
+		tasks = slices.DeleteFunc(tasks, func(t *models.Task) bool { return t.Complete })
+	}
+	models.SortTasks(tasks, params)
+
+	tc, err := s.taskContext(ctx, s.stores)
+	if err != nil {
+		return err
+	}
+
+	page, err := paginate(r, tasks)
+	if err != nil {
+		return err
+	}
+
+	out := list[taskJSON]{Items: make([]taskJSON, 0, len(page.Items)), Total: page.Total, Offset: page.Offset, Limit: page.Limit, NextOffset: page.NextOffset}
+	for _, t := range page.Items {
+		out.Items = append(out.Items, tc.toJSON(t))
+	}
+
+	writeJSON(w, http.StatusOK, out)
+	return nil
+}
+
+func (s *Server) getTask(w http.ResponseWriter, r *http.Request) error {
+	t, err := s.task(s.stores, r)
+	if err != nil {
+		return err
+	}
+
+	return s.writeTask(w, r, http.StatusOK, t.ID)
+}
+
+func (s *Server) createTask(w http.ResponseWriter, r *http.Request) error {
+	var in taskInput
+	if err := decode(r, &in); err != nil {
+		return err
+	}
+	if !in.Title.Set || strings.TrimSpace(in.Title.Value) == "" {
+		return errorf(http.StatusBadRequest, "title is required")
+	}
+
+	input := &models.Task{}
+	if err := s.applyTaskInput(input, in); err != nil {
+		return err
+	}
+
+	ctx := r.Context()
+	var created *models.Task
+	err := s.stores.InTx(ctx, func(st *models.AllRepos) error {
+		t := *input
+		if in.ParentID.Set && in.ParentID.Value != nil {
+			if in.ProjectID.Set || in.Project.Set {
+				return errorf(http.StatusBadRequest, "subtasks are always in their parent's project")
+			}
+
+			parent, err := st.Tasks.Get(ctx, *in.ParentID.Value)
+			if err != nil || parent.DeletedAt != nil {
+				return errorf(http.StatusUnprocessableEntity, "no parent task with ID %d", *in.ParentID.Value)
+			}
+			t.ParentTaskID = &parent.ID
+			t.ProjectID = parent.ProjectID
+		} else {
+			projectID, err := s.inputProjectID(ctx, st, in.ProjectID, in.Project)
+			if err != nil {
+				return err
+			}
+			t.ProjectID = projectID
+		}
+
+		var err error
+		if created, err = st.Tasks.Create(ctx, &t); err != nil {
+			return fmt.Errorf("creating task: %w", err)
+		}
+
+		if in.Tags.Set {
+			return st.SetTaskTags(ctx, created.ID, in.Tags.Value)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Location", fmt.Sprintf("%s/tasks/%d", BasePath, created.ID))
+	return s.writeTask(w, r, http.StatusCreated, created.ID)
+}
+
+// updateTask changes the fields given in the request body. Completing a task works as it
+// does in the TUI, so a repeating task gets its next occurrence. The whole change is one
+// transaction, so a bad project or tag leaves the task as it was.
+func (s *Server) updateTask(w http.ResponseWriter, r *http.Request) error {
+	var in taskInput
+	if err := decode(r, &in); err != nil {
+		return err
+	}
+	if in.ParentID.Set {
+		return errorf(http.StatusBadRequest, "parent_id can only be set when creating a task")
+	}
+	if in.Title.Set && strings.TrimSpace(in.Title.Value) == "" {
+		return errorf(http.StatusBadRequest, "title can't be empty")
+	}
+
+	ctx := r.Context()
+	var id int64
+	err := s.stores.InTx(ctx, func(st *models.AllRepos) error {
+		t, err := s.task(st, r)
+		if err != nil {
+			return err
+		}
+		id = t.ID
+		if err := s.checkTaskIfMatch(ctx, st, r, t); err != nil {
+			return err
+		}
+
+		after := *t
+		if err := s.applyTaskInput(&after, in); err != nil {
+			return err
+		}
+		// completing is done separately below
+		after.Complete = t.Complete
+
+		move := in.ProjectID.Set || in.Project.Set
+		var projectID *int64
+		if move {
+			if projectID, err = s.inputProjectID(ctx, st, in.ProjectID, in.Project); err != nil {
+				return err
+			}
+		}
+
+		updated, err := st.Tasks.Update(ctx, &after)
+		if err != nil {
+			return fmt.Errorf("updating task %d: %w", t.ID, err)
+		}
+
+		switch {
+		case in.Complete.Set && in.Complete.Value && !t.Complete:
+			c, err := st.CompleteTask(ctx, updated, s.cfg.CascadeComplete)
+			if err != nil {
+				return err
+			}
+			updated = c.Task
+		case in.Complete.Set && !in.Complete.Value && t.Complete:
+			updated.Complete = false
+			if updated, err = st.Tasks.Update(ctx, updated); err != nil {
+				return fmt.Errorf("uncompleting task %d: %w", t.ID, err)
+			}
+		}
+
+		if move && !models.SameID(updated.ProjectID, projectID) {
+			if _, _, err := st.MoveTask(ctx, updated, projectID); err != nil {
+				return err
+			}
+		}
+
+		if in.Tags.Set {
+			return st.SetTaskTags(ctx, t.ID, in.Tags.Value)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.writeTask(w, r, http.StatusOK, id)
+}
+
+// deleteTask moves a task to the trash, where it can still be restored from the TUI.
+func (s *Server) deleteTask(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	err := s.stores.InTx(ctx, func(st *models.AllRepos) error {
+		t, err := s.task(st, r)
+		if err != nil {
+			return err
+		}
+		if err := s.checkTaskIfMatch(ctx, st, r, t); err != nil {
+			return err
+		}
+
+		if err := st.Tasks.Trash(ctx, t.ID); err != nil {
+			return fmt.Errorf("trashing task %d: %w", t.ID, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// task gets the task in the request's path. Trashed tasks aren't found.
+func (s *Server) task(st *models.AllRepos, r *http.Request) (*models.Task, error) {
+	id, err := pathID(r)
+	if err != nil {
+		return nil, err
+	}
+
+	t, err := st.Tasks.Get(r.Context(), id)
+	if err != nil {
+		return nil, err
+	}
+
+	if t.DeletedAt != nil {
+		return nil, fmt.Errorf("task %d: %w", id, models.ErrNotFound)
+	}
+
+	return t, nil
+}
+
+// writeTask gets a task again, after any changes, and writes it.
+func (s *Server) writeTask(w http.ResponseWriter, r *http.Request, status int, id int64) error {
+	t, err := s.stores.Tasks.Get(r.Context(), id)
+	if err != nil {
+		return err
+	}
+
+	tc, err := s.taskContext(r.Context(), s.stores)
+	if err != nil {
+		return err
+	}
+
+	writeItem(w, r, status, tc.toJSON(t))
+	return nil
+}
+
+// checkTaskIfMatch checks the request's If-Match header against the task as read with st,
+// which should be the transaction making the change.
+func (s *Server) checkTaskIfMatch(ctx context.Context, st *models.AllRepos, r *http.Request, t *models.Task) error {
+	if r.Header.Get("If-Match") == "" {
+		return nil
+	}
+
+	tc, err := s.taskContext(ctx, st)
+	if err != nil {
+		return err
+	}
+
+	return checkIfMatch(r, tc.toJSON(t))
+}
+
+// applyTaskInput sets the fields stored on the task itself. The project, parent and
+// tags are left to the caller.
+func (s *Server) applyTaskInput(t *models.Task, in taskInput) error {
+	if in.Title.Set {
+		t.Title = strings.TrimSpace(in.Title.Value)
+	}
+
+	if in.Complete.Set {
+		t.Complete = in.Complete.Value
+	}
+
+	if in.Due.Set {
+		t.DueAt = nil
+		if in.Due.Value != nil && strings.TrimSpace(*in.Due.Value) != "" {
+			due, err := s.dates.Parse(*in.Due.Value)
+			if err != nil {
+				return errorf(http.StatusBadRequest, "due %q: %v", *in.Due.Value, err)
+			}
+			t.DueAt = &due
+		}
+	}
+
+	if in.Priority.Set {
+		p, err := models.ParsePriority(in.Priority.Value)
+		if err != nil {
+			return errorf(http.StatusBadRequest, "%v", err)
+		}
+		t.Priority = p
+	}
+
+	if in.Recurrence.Set {
+		if err := t.SetRecurrence(in.Recurrence.Value); err != nil {
+			return errorf(http.StatusBadRequest, "recurrence %q: %v", in.Recurrence.Value, err)
+		}
+	}
+
+	if in.Notes.Set {
+		t.Notes = in.Notes.Value
+	}
+
+	return nil
+}
+
+// inputProjectID resolves the project given by ID or path in a request body. Leaving both
+// out, a null ID or an empty path mean no project.
+func (s *Server) inputProjectID(ctx context.Context, st *models.AllRepos, id optional[*int64], path optional[string]) (*int64, error) {
+	switch {
+	case id.Set && path.Set:
+		return nil, errorf(http.StatusBadRequest, "give either project_id or project, not both")
+
+	case id.Set && id.Value != nil:
+		p, err := st.Projects.Get(ctx, *id.Value)
+		if err != nil || p.DeletedAt != nil {
+			return nil, errorf(http.StatusUnprocessableEntity, "no project with ID %d", *id.Value)
+		}
+		return &p.ID, nil
+
+	case path.Set && !strings.EqualFold(strings.TrimSpace(path.Value), filter.None):
+		if s.cfg.QuickAddCreateProjects {
+			id, _, err := st.EnsureProjectPath(ctx, path.Value)
+			return id, err
+		}
+		return st.ResolveProjectPath(ctx, path.Value)
+	}
+
+	return nil, nil
+}
+
+func (s *Server) taskContext(ctx context.Context, st *models.AllRepos) (*taskContext, error) {
+	projects, err := st.Projects.ListAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing projects: %w", err)
+	}
+
+	tags, err := st.Tags.ListAllByTaskID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing tags: %w", err)
+	}
+
+	tc := &taskContext{projects: projects, tags: make(map[int64][]string, len(tags))}
+	for id, ts := range tags {
+		for _, t := range ts {
+			tc.tags[id] = append(tc.tags[id], t.Name)
+		}
+	}
+
+	return tc, nil
+}
+
+func (tc *taskContext) toJSON(t *models.Task) taskJSON {
+	tags := tc.tags[t.ID]
+	if tags == nil {
+		tags = []string{}
+	}
+
+	var project string
+	if t.ProjectID != nil {
+		project = models.ProjectPath(tc.projects, *t.ProjectID)
+	}
+
+	return taskJSON{
+		ID:          t.ID,
+		Title:       t.Title,
+		ProjectID:   t.ProjectID,
+		Project:     project,
+		ParentID:    t.ParentTaskID,
+		Complete:    t.Complete,
+		Due:         t.DueAt,
+		Priority:    t.Priority.String(),
+		Tags:        tags,
+		Recurrence:  t.Recurrence,
+		Notes:       t.Notes,
+		Position:    t.Position,
+		CreatedAt:   t.CreatedAt,
+		UpdatedAt:   t.UpdatedAt,
+		CompletedAt: t.CompletedAt,
+		ArchivedAt:  t.ArchivedAt,
+	}
+}
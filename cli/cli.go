@@ -0,0 +1,197 @@
+// Package cli runs yata's subcommands, like "yata add" and "yata ls", for scripting
+// without opening the TUI. Each command reads and writes the same stores as the TUI and
+// can print JSON instead of text with --json.
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/dsrosen6/yata/config"
+	"github.com/dsrosen6/yata/dates"
+	"github.com/dsrosen6/yata/models"
+)
+
+// Exit codes returned by ExitCode.
+const (
+	ExitOK    = 0
+	ExitError = 1
+	ExitUsage = 2
+)
+
+type (
+	// runner holds what every command needs.
+	runner struct {
+		ctx    context.Context
+		cfg    *config.Config
+		stores *models.AllRepos
+		out    io.Writer
+		errOut io.Writer
+		dates  *dates.Parser
+		cmd    command
+	}
+
+	command struct {
+		name    string
+		args    string
+		summary string
+		run     func(r *runner, args []string) error
+	}
+
+	// usageError is a mistake in how a command was called, like an unknown flag or a
+	// missing argument.
+	usageError struct{ msg string }
+)
+
+var commands = []command{
+	{name: "add", args: "[flags] title...", summary: "create a task, with quick-add syntax like #project @due !priority +tag", run: (*runner).add},
+	{name: "ls", args: "[flags] [filter...]", summary: "list tasks, optionally matching a filter like due<=fri", run: (*runner).ls},
+	{name: "done", args: "id...", summary: "complete tasks", run: (*runner).done},
+	{name: "edit", args: "[flags] id", summary: "change a task's title, project, due date, priority, tags or repeat rule", run: (*runner).edit},
+	{name: "mv", args: "id... project", summary: "move tasks to a project path like work/clients, or none", run: (*runner).mv},
+	{name: "rm", args: "id...", summary: "move tasks to the trash", run: (*runner).rm},
+	{name: "projects", args: "[flags]", summary: "list projects", run: (*runner).projects},
+	{name: "serve", args: "[flags]", summary: "serve tasks and projects over a JSON REST API", run: (*runner).serve},
+}
+
+func (e *usageError) Error() string {
+	return e.msg
+}
+
+func usagef(format string, args ...any) error {
+	return &usageError{msg: fmt.Sprintf(format, args...)}
+}
+
+// Run runs the subcommand named by args[0], the first argument after the program name,
+// writing its output to out and any warnings to errOut.
+func Run(ctx context.Context, cfg *config.Config, stores *models.AllRepos, args []string, out, errOut io.Writer) error {
+	r := &runner{
+		ctx:    ctx,
+		cfg:    cfg,
+		stores: stores,
+		out:    out,
+		errOut: errOut,
+		dates:  dates.NewParser(),
+	}
+
+	if len(args) == 0 {
+		printUsage(errOut)
+		return usagef("no command given")
+	}
+
+	switch args[0] {
+	case "help", "-h", "-help", "--help":
+		printUsage(out)
+		return nil
+	}
+
+	c, ok := findCommand(args[0])
+	if !ok {
+		printUsage(errOut)
+		return usagef("unknown command %q", args[0])
+	}
+
+	r.cmd = c
+	err := c.run(r, args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		return nil
+	}
+	return err
+}
+
+// ExitCode returns the exit code for an error returned by Run.
+func ExitCode(err error) int {
+	var ue *usageError
+	switch {
+	case err == nil:
+		return ExitOK
+	case errors.As(err, &ue):
+		return ExitUsage
+	default:
+		return ExitError
+	}
+}
+
+func findCommand(name string) (command, bool) {
+	for _, c := range commands {
+		if c.name == name {
+			return c, true
+		}
+	}
+	return command{}, false
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "usage: yata [command] [flags] [args]")
+	fmt.Fprintln(w, "\nWith no command, yata opens the TUI. Commands:")
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, c := range commands {
+		fmt.Fprintf(tw, "  %s\t%s\n", c.name, c.summary)
+	}
+	_ = tw.Flush()
+
+	fmt.Fprintln(w, "\nRun yata [command] -h for a command's flags. Every command takes --json.")
+}
+
+// newFlagSet returns the flag set for the command being run, with the --json flag every
+// command has.
+func (r *runner) newFlagSet(jsonOut *bool) *flag.FlagSet {
+	c := r.cmd
+	fs := flag.NewFlagSet(c.name, flag.ContinueOnError)
+	fs.BoolVar(jsonOut, "json", false, "print JSON instead of text")
+
+	// parse errors are returned rather than printed, so they're only shown once
+	fs.SetOutput(io.Discard)
+	fs.Usage = func() {
+		fmt.Fprintf(r.errOut, "usage: yata %s %s\n\n%s\n\n", c.name, c.args, c.summary)
+		fs.SetOutput(r.errOut)
+		fs.PrintDefaults()
+		fs.SetOutput(io.Discard)
+	}
+
+	return fs
+}
+
+// parseArgs parses flags wherever they are among the arguments, so "yata add buy milk
+// -p home" works. Everything after -- is taken as it is.
+func parseArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return nil, err
+			}
+			return nil, &usageError{msg: err.Error()}
+		}
+
+		rest := fs.Args()
+		if len(rest) == 0 {
+			return positional, nil
+		}
+
+		if consumed := len(args) - len(rest); consumed > 0 && args[consumed-1] == "--" {
+			return append(positional, rest...), nil
+		}
+
+		positional = append(positional, rest[0])
+		args = rest[1:]
+	}
+}
+
+// splitList splits a comma-separated flag value, like a list of tags.
+func splitList(s string) []string {
+	var items []string
+	for item := range strings.SplitSeq(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
@@ -0,0 +1,235 @@
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dsrosen6/yata/cli"
+	"github.com/dsrosen6/yata/config"
+	"github.com/dsrosen6/yata/models"
+	"github.com/dsrosen6/yata/sqlitedb"
+)
+
+type (
+	// env runs commands against a fresh database in a temporary directory.
+	env struct {
+		t      *testing.T
+		cfg    *config.Config
+		stores *models.AllRepos
+	}
+
+	task struct {
+		ID       int64    `json:"id"`
+		Title    string   `json:"title"`
+		Project  string   `json:"project"`
+		ParentID *int64   `json:"parent_id"`
+		Complete bool     `json:"complete"`
+		Priority string   `json:"priority"`
+		Tags     []string `json:"tags"`
+	}
+)
+
+func newEnv(t *testing.T) *env {
+	t.Helper()
+	h, err := sqlitedb.NewHandler(os.DirFS("../migrations"), filepath.Join(t.TempDir(), "app.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = h.Close() })
+
+	stores, err := h.InitStores(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &env{t: t, cfg: &config.Config{}, stores: stores}
+}
+
+// run runs a command, given as it would be typed after "yata", and returns its exit code
+// and what it wrote to stdout and stderr.
+func (e *env) run(line string) (code int, out, errOut string) {
+	e.t.Helper()
+	var o, eo bytes.Buffer
+	err := cli.Run(context.Background(), e.cfg, e.stores, strings.Fields(line), &o, &eo)
+	return cli.ExitCode(err), o.String(), eo.String()
+}
+
+// expect runs a command and fails the test if it doesn't exit with code.
+func (e *env) expect(code int, line string) (out, errOut string) {
+	e.t.Helper()
+	got, out, errOut := e.run(line)
+	if got != code {
+		e.t.Fatalf("yata %s = exit %d, want %d\nstdout: %s\nstderr: %s", line, got, code, out, errOut)
+	}
+	return out, errOut
+}
+
+// task gets a task with --json, by editing nothing but its notes.
+func (e *env) task(id string) task {
+	e.t.Helper()
+	out, _ := e.expect(cli.ExitOK, "edit --json -notes x "+id)
+
+	var t task
+	if err := json.Unmarshal([]byte(out), &t); err != nil {
+		e.t.Fatalf("decoding %s: %v", out, err)
+	}
+	return t
+}
+
+func TestExitCodes(t *testing.T) {
+	e := newEnv(t)
+	e.expect(cli.ExitOK, "add a")
+
+	tests := []struct {
+		line string
+		code int
+	}{
+		{"", cli.ExitUsage},
+		{"help", cli.ExitOK},
+		{"frobnicate", cli.ExitUsage},
+		{"add -h", cli.ExitOK},
+		{"add", cli.ExitUsage},
+		{"add #work !high", cli.ExitUsage},
+		{"add a -colour red", cli.ExitUsage},
+		{"add a -d someday", cli.ExitUsage},
+		{"add a -P very", cli.ExitUsage},
+		{"add a -r sometimes", cli.ExitUsage},
+		{"add a -p missing", cli.ExitError},
+		{"add a -parent 99", cli.ExitError},
+		{"ls due<=", cli.ExitUsage},
+		{"ls due:someday", cli.ExitUsage},
+		{"ls -sort colour", cli.ExitUsage},
+		{"done", cli.ExitUsage},
+		{"done abc", cli.ExitUsage},
+		{"done 99", cli.ExitError},
+		{"edit 1", cli.ExitUsage},
+		{"edit 1 2 -title b", cli.ExitUsage},
+		{"edit 1 -title", cli.ExitUsage},
+		{"mv 1", cli.ExitUsage},
+		{"mv 1 missing", cli.ExitError},
+		{"rm", cli.ExitUsage},
+		{"projects extra", cli.ExitUsage},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			if code, out, errOut := e.run(tt.line); code != tt.code {
+				t.Errorf("yata %s = exit %d, want %d\nstdout: %s\nstderr: %s", tt.line, code, tt.code, out, errOut)
+			}
+		})
+	}
+}
+
+func TestAdd(t *testing.T) {
+	e := newEnv(t)
+	e.cfg.QuickAddCreateProjects = true
+
+	out, errOut := e.expect(cli.ExitOK, "add buy milk #home/errands !high +food @2026-03-14 @later -t dairy")
+	if out != "created task 1: buy milk\n" {
+		t.Errorf("stdout = %q", out)
+	}
+	if errOut != "ignored @later\n" {
+		t.Errorf("stderr = %q, want the second due date ignored", errOut)
+	}
+
+	got := e.task("1")
+	if got.Project != "home/errands" || got.Priority != "high" || strings.Join(got.Tags, ",") != "dairy,food" {
+		t.Errorf("created %+v", got)
+	}
+
+	// flags can go anywhere, and take priority over quick-add syntax
+	e.expect(cli.ExitOK, "add -P low oat milk !high -p none #home -- -p")
+	if got := e.task("2"); got.Title != "oat milk -p" || got.Priority != "low" || got.Project != "" {
+		t.Errorf("created %+v", got)
+	}
+
+	e.expect(cli.ExitOK, "add --json -parent 1 compare prices")
+	if got := e.task("3"); got.ParentID == nil || *got.ParentID != 1 || got.Project != "home/errands" {
+		t.Errorf("subtask %+v, want it under task 1 in its project", got)
+	}
+	e.expect(cli.ExitUsage, "add -parent 1 other -p home")
+}
+
+func TestAddFailureLeavesNothing(t *testing.T) {
+	e := newEnv(t)
+	e.cfg.QuickAddCreateProjects = true
+
+	e.expect(cli.ExitError, "add a #work/")
+	out, _ := e.expect(cli.ExitOK, "projects")
+	if out != "" {
+		t.Errorf("projects = %q, want none", out)
+	}
+
+	out, _ = e.expect(cli.ExitOK, "ls -a")
+	if out != "" {
+		t.Errorf("tasks = %q, want none", out)
+	}
+}
+
+func TestEdit(t *testing.T) {
+	e := newEnv(t)
+	e.cfg.QuickAddCreateProjects = true
+	e.expect(cli.ExitOK, "add a #work +old")
+	e.expect(cli.ExitOK, "add b #home")
+
+	out, _ := e.expect(cli.ExitOK, "edit 1 -title renamed -p home -t x,y -P urgent")
+	if out != "updated task 1: renamed\n" {
+		t.Errorf("stdout = %q", out)
+	}
+	if got := e.task("1"); got.Project != "home" || strings.Join(got.Tags, ",") != "x,y" || got.Priority != "urgent" {
+		t.Errorf("edited %+v", got)
+	}
+
+	// a bad project undoes the rest of the change
+	e.expect(cli.ExitError, "edit 1 -title again -t z -p missing")
+	if got := e.task("1"); got.Title != "renamed" || got.Project != "home" || strings.Join(got.Tags, ",") != "x,y" {
+		t.Errorf("after a failed edit %+v, want it unchanged", got)
+	}
+}
+
+func TestLs(t *testing.T) {
+	e := newEnv(t)
+	e.cfg.QuickAddCreateProjects = true
+	e.expect(cli.ExitOK, "add buy milk #home !high +food @2026-03-14")
+	e.expect(cli.ExitOK, "add invoice #work/clients")
+	e.expect(cli.ExitOK, "add call Sam")
+	e.expect(cli.ExitOK, "done 3")
+
+	tests := []struct {
+		line string
+		want string
+	}{
+		{"ls", "1  [ ] buy milk  #home @2026-03-14 !high +food\n2  [ ] invoice   #work/clients\n"},
+		{"ls -a", "1  [ ] buy milk  #home @2026-03-14 !high +food\n2  [ ] invoice   #work/clients\n3  [x] call Sam\n"},
+		{"ls -p work", "2  [ ] invoice  #work/clients\n"},
+		{"ls tag:food or project:clients", "1  [ ] buy milk  #home @2026-03-14 !high +food\n2  [ ] invoice   #work/clients\n"},
+		{"ls -a done", "3  [x] call Sam\n"},
+		{"ls -p home project:work", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			if out, _ := e.expect(cli.ExitOK, tt.line); out != tt.want {
+				t.Errorf("yata %s =\n%s\nwant\n%s", tt.line, out, tt.want)
+			}
+		})
+	}
+
+	out, _ := e.expect(cli.ExitOK, "ls --json -p work")
+	var tasks []task
+	if err := json.Unmarshal([]byte(out), &tasks); err != nil {
+		t.Fatalf("decoding %s: %v", out, err)
+	}
+	if len(tasks) != 1 || tasks[0].Title != "invoice" || tasks[0].Project != "work/clients" || tasks[0].Tags == nil {
+		t.Errorf("ls --json = %+v", tasks)
+	}
+
+	out, _ = e.expect(cli.ExitOK, "projects")
+	if want := "1  home\n2  work\n3  work/clients\n"; out != want {
+		t.Errorf("projects =\n%s\nwant\n%s", out, want)
+	}
+}
@@ -0,0 +1,189 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/dsrosen6/yata/dates"
+	"github.com/dsrosen6/yata/models"
+)
+
+type (
+	taskJSON struct {
+		ID          int64      `json:"id"`
+		Title       string     `json:"title"`
+		ProjectID   *int64     `json:"project_id"`
+		Project     string     `json:"project"`
+		ParentID    *int64     `json:"parent_id"`
+		Complete    bool       `json:"complete"`
+		Due         *time.Time `json:"due"`
+		Priority    string     `json:"priority"`
+		Tags        []string   `json:"tags"`
+		Recurrence  string     `json:"recurrence"`
+		Notes       string     `json:"notes"`
+		CreatedAt   time.Time  `json:"created_at"`
+		UpdatedAt   time.Time  `json:"updated_at"`
+		CompletedAt *time.Time `json:"completed_at"`
+	}
+
+	projectJSON struct {
+		ID       int64  `json:"id"`
+		Title    string `json:"title"`
+		Path     string `json:"path"`
+		ParentID *int64 `json:"parent_id"`
+	}
+
+	// taskContext is what's needed to show tasks beyond the tasks themselves.
+	taskContext struct {
+		projects []*models.Project
+		tags     map[int64][]string
+	}
+)
+
+func (r *runner) taskContext() (*taskContext, error) {
+	projects, err := r.stores.Projects.ListAll(r.ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing projects: %w", err)
+	}
+
+	tags, err := r.stores.Tags.ListAllByTaskID(r.ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing tags: %w", err)
+	}
+
+	tc := &taskContext{projects: projects, tags: make(map[int64][]string, len(tags))}
+	for id, ts := range tags {
+		for _, t := range ts {
+			tc.tags[id] = append(tc.tags[id], t.Name)
+		}
+	}
+
+	return tc, nil
+}
+
+func (tc *taskContext) projectPath(t *models.Task) string {
+	if t.ProjectID == nil {
+		return ""
+	}
+	return models.ProjectPath(tc.projects, *t.ProjectID)
+}
+
+func (tc *taskContext) toJSON(t *models.Task) taskJSON {
+	tags := tc.tags[t.ID]
+	if tags == nil {
+		tags = []string{}
+	}
+
+	return taskJSON{
+		ID:          t.ID,
+		Title:       t.Title,
+		ProjectID:   t.ProjectID,
+		Project:     tc.projectPath(t),
+		ParentID:    t.ParentTaskID,
+		Complete:    t.Complete,
+		Due:         t.DueAt,
+		Priority:    t.Priority.String(),
+		Tags:        tags,
+		Recurrence:  t.Recurrence,
+		Notes:       t.Notes,
+		CreatedAt:   t.CreatedAt,
+		UpdatedAt:   t.UpdatedAt,
+		CompletedAt: t.CompletedAt,
+	}
+}
+
+// printTasks writes tasks as a JSON array, or as a table with a row per task.
+func (r *runner) printTasks(tasks []*models.Task, jsonOut bool) error {
+	tc, err := r.taskContext()
+	if err != nil {
+		return err
+	}
+
+	if jsonOut {
+		out := make([]taskJSON, 0, len(tasks))
+		for _, t := range tasks {
+			out = append(out, tc.toJSON(t))
+		}
+		return r.printJSON(out)
+	}
+
+	buf, tw := newTable()
+	for _, t := range tasks {
+		check := "[ ]"
+		if t.Complete {
+			check = "[x]"
+		}
+
+		var meta []string
+		if p := tc.projectPath(t); p != "" {
+			meta = append(meta, "#"+p)
+		}
+		if t.DueAt != nil {
+			meta = append(meta, "@"+formatDue(*t.DueAt))
+		}
+		if t.Priority != models.PriorityNone {
+			meta = append(meta, "!"+t.Priority.String())
+		}
+		for _, tag := range tc.tags[t.ID] {
+			meta = append(meta, "+"+tag)
+		}
+
+		fmt.Fprintf(tw, "%d\t%s %s\t%s\n", t.ID, check, t.Title, strings.Join(meta, " "))
+	}
+
+	return r.flushTable(tw, buf)
+}
+
+// printTask writes a single task, as a JSON object or a line of text.
+func (r *runner) printTask(t *models.Task, jsonOut bool, verb string) error {
+	if !jsonOut {
+		_, err := fmt.Fprintf(r.out, "%s task %d: %s\n", verb, t.ID, t.Title)
+		return err
+	}
+
+	tc, err := r.taskContext()
+	if err != nil {
+		return err
+	}
+	return r.printJSON(tc.toJSON(t))
+}
+
+// newTable returns a writer for lining up columns, which writes to the returned buffer
+// so the padding after the last column can be trimmed by flushTable.
+func newTable() (*bytes.Buffer, *tabwriter.Writer) {
+	buf := &bytes.Buffer{}
+	return buf, tabwriter.NewWriter(buf, 0, 0, 2, ' ', 0)
+}
+
+func (r *runner) flushTable(tw *tabwriter.Writer, buf *bytes.Buffer) error {
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	for line := range strings.Lines(buf.String()) {
+		if _, err := fmt.Fprintln(r.out, strings.TrimRight(line, " \n")); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *runner) printJSON(v any) error {
+	enc := json.NewEncoder(r.out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// formatDue formats a due date in local time, leaving the time off dates without one.
+func formatDue(t time.Time) string {
+	t = t.In(time.Local)
+	if dates.HasTime(t) {
+		return t.Format("2006-01-02 15:04")
+	}
+	return t.Format("2006-01-02")
+}
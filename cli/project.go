@@ -0,0 +1,63 @@
+package cli
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/dsrosen6/yata/models"
+)
+
+func (r *runner) projects(args []string) error {
+	var jsonOut bool
+	fs := r.newFlagSet(&jsonOut)
+	args, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) > 0 {
+		return usagef("projects takes no arguments")
+	}
+
+	projects, err := r.stores.Projects.ListAll(r.ctx)
+	if err != nil {
+		return fmt.Errorf("listing projects: %w", err)
+	}
+
+	out := make([]projectJSON, 0, len(projects))
+	for _, p := range projects {
+		out = append(out, projectJSON{
+			ID:       p.ID,
+			Title:    p.Title,
+			Path:     models.ProjectPath(projects, p.ID),
+			ParentID: p.ParentID,
+		})
+	}
+	slices.SortFunc(out, func(a, b projectJSON) int { return strings.Compare(a.Path, b.Path) })
+
+	if jsonOut {
+		return r.printJSON(out)
+	}
+
+	tw := tabwriter.NewWriter(r.out, 0, 0, 2, ' ', 0)
+	for _, p := range out {
+		fmt.Fprintf(tw, "%d\t%s\n", p.ID, p.Path)
+	}
+	return tw.Flush()
+}
+
+// projectID resolves a project path from an argument with st, creating the projects along
+// it if create is set. An empty path or "none" means no project.
+func (r *runner) projectID(st *models.AllRepos, path string, create bool) (*int64, error) {
+	if strings.EqualFold(strings.TrimSpace(path), noProject) {
+		return nil, nil
+	}
+
+	if create {
+		id, _, err := st.EnsureProjectPath(r.ctx, path)
+		return id, err
+	}
+
+	return st.ResolveProjectPath(r.ctx, path)
+}
@@ -0,0 +1,165 @@
+package cli
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/dsrosen6/yata/api"
+)
+
+// tokenEnv is the environment variable the API token can be set with, which is taken
+// over the config file's.
+const tokenEnv = "YATA_TOKEN"
+
+func (r *runner) serve(args []string) error {
+	var (
+		jsonOut bool
+		addr    string
+		socket  string
+		token   string
+	)
+	flags := r.newFlagSet(&jsonOut)
+	flags.StringVar(&addr, "addr", r.cfg.ServeAddr, "address to listen on")
+	flags.StringVar(&socket, "socket", r.cfg.ServeSocket, "Unix socket to listen on instead of an address")
+	flags.StringVar(&token, "token", "", "bearer token requests need, instead of $"+tokenEnv+" or the config file's")
+
+	args, err := parseArgs(flags, args)
+	if err != nil {
+		return err
+	}
+	if len(args) > 0 {
+		return usagef("serve takes no arguments")
+	}
+
+	generated := false
+	for _, t := range []string{token, os.Getenv(tokenEnv), r.cfg.ServeToken} {
+		if t != "" {
+			token = t
+			break
+		}
+	}
+	if token == "" {
+		if token, err = newToken(); err != nil {
+			return err
+		}
+		generated = true
+	}
+
+	l, where, err := listen(addr, socket)
+	if err != nil {
+		return err
+	}
+
+	srv := &http.Server{
+		Handler:           api.New(r.stores, r.cfg, token).Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	if jsonOut {
+		info := map[string]string{"url": where + api.BasePath}
+		if generated {
+			info["token"] = token
+		}
+		if err := r.printJSON(info); err != nil {
+			return err
+		}
+	} else {
+		fmt.Fprintf(r.out, "serving the API on %s%s\n", where, api.BasePath)
+		if generated {
+			fmt.Fprintf(r.out, "no token is configured, so requests need this one: %s\n", token)
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(r.ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	errc := make(chan error, 1)
+	go func() { errc <- srv.Serve(l) }()
+
+	select {
+	case err := <-errc:
+		return fmt.Errorf("serving: %w", err)
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("shutting down: %w", err)
+	}
+
+	return nil
+}
+
+// listen listens on the Unix socket if one is given, or the address otherwise. It
+// returns where it's listening, as the start of a URL.
+func listen(addr, socket string) (net.Listener, string, error) {
+	if socket == "" {
+		l, err := net.Listen("tcp", addr)
+		if err != nil {
+			return nil, "", fmt.Errorf("listening on %s: %w", addr, err)
+		}
+		return l, "http://" + l.Addr().String(), nil
+	}
+
+	// a socket left behind by a server that didn't shut down cleanly would stop this one
+	// from starting, but anything else at the path is left alone
+	if fi, err := os.Lstat(socket); err == nil && fi.Mode().Type() == fs.ModeSocket {
+		if err := removeStaleSocket(socket); err != nil {
+			return nil, "", err
+		}
+	} else if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, "", fmt.Errorf("checking socket path %s: %w", socket, err)
+	}
+
+	l, err := net.Listen("unix", socket)
+	if err != nil {
+		return nil, "", fmt.Errorf("listening on %s: %w", socket, err)
+	}
+
+	// the token is the real protection, but there's no reason for anyone else to connect
+	if err := os.Chmod(socket, 0o600); err != nil {
+		_ = l.Close()
+		return nil, "", fmt.Errorf("setting socket permissions: %w", err)
+	}
+
+	return l, "unix:" + socket, nil
+}
+
+// removeStaleSocket removes the socket if nothing is listening on it anymore. A server
+// that's still running is left alone, and so is one that can't be reached for any
+// reason other than the connection being refused.
+func removeStaleSocket(socket string) error {
+	c, err := net.DialTimeout("unix", socket, time.Second)
+	if err == nil {
+		_ = c.Close()
+		return fmt.Errorf("another server is already listening on %s", socket)
+	}
+	if !errors.Is(err, syscall.ECONNREFUSED) {
+		return fmt.Errorf("checking old socket %s: %w", socket, err)
+	}
+
+	if err := os.Remove(socket); err != nil {
+		return fmt.Errorf("removing old socket %s: %w", socket, err)
+	}
+	return nil
+}
+
+func newToken() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generating token: %w", err)
+	}
+
+	return hex.EncodeToString(b), nil
+}
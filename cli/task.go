@@ -0,0 +1,515 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dsrosen6/yata/filter"
+	"github.com/dsrosen6/yata/models"
+	"github.com/dsrosen6/yata/quickadd"
+)
+
+// noProject is the project path for tasks without a project, as in the filter language.
+const noProject = filter.None
+
+// taskFlags are the task fields that add and edit can set.
+type taskFlags struct {
+	title    string
+	project  string
+	due      string
+	priority string
+	tags     string
+	repeat   string
+	notes    string
+}
+
+// shorthands maps the one-letter flags to the fields they set.
+var shorthands = map[string]string{
+	"p": "project",
+	"d": "due",
+	"P": "priority",
+	"t": "tags",
+	"r": "repeat",
+}
+
+func (f *taskFlags) register(fs *flag.FlagSet, withTitle bool) {
+	if withTitle {
+		fs.StringVar(&f.title, "title", "", "new title")
+	}
+	fs.StringVar(&f.project, "project", "", `project path, like work/clients, or "none"`)
+	fs.StringVar(&f.project, "p", "", "shorthand for -project")
+	fs.StringVar(&f.due, "due", "", `due date, like tomorrow or "next fri 5pm", or "none"`)
+	fs.StringVar(&f.due, "d", "", "shorthand for -due")
+	fs.StringVar(&f.priority, "priority", "", "priority: none, low, medium, high or urgent")
+	fs.StringVar(&f.priority, "P", "", "shorthand for -priority")
+	fs.StringVar(&f.tags, "tags", "", "comma-separated tags")
+	fs.StringVar(&f.tags, "t", "", "shorthand for -tags")
+	fs.StringVar(&f.repeat, "repeat", "", `repeat rule, like "every 2 weeks", or "none"`)
+	fs.StringVar(&f.repeat, "r", "", "shorthand for -repeat")
+	fs.StringVar(&f.notes, "notes", "", "notes")
+}
+
+// setFlags returns the fields set on the command line, by their long flag names.
+func setFlags(fs *flag.FlagSet) map[string]bool {
+	set := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) {
+		if long, ok := shorthands[f.Name]; ok {
+			set[long] = true
+			return
+		}
+		set[f.Name] = true
+	})
+
+	return set
+}
+
+// apply sets the task's title, due date, priority, repeat rule and notes from the flags
+// that were set. The project and tags are left to the caller, since they're stored apart
+// from the task.
+func (f *taskFlags) apply(r *runner, t *models.Task, set map[string]bool) error {
+	if set["title"] {
+		if strings.TrimSpace(f.title) == "" {
+			return usagef("the title can't be empty")
+		}
+		t.Title = strings.TrimSpace(f.title)
+	}
+
+	if set["due"] {
+		due, err := r.parseDue(f.due)
+		if err != nil {
+			return err
+		}
+		t.DueAt = due
+	}
+
+	if set["priority"] {
+		p, err := models.ParsePriority(f.priority)
+		if err != nil {
+			return usagef("%v", err)
+		}
+		t.Priority = p
+	}
+
+	if set["repeat"] {
+		if err := setRepeat(t, f.repeat); err != nil {
+			return err
+		}
+	}
+
+	if set["notes"] {
+		t.Notes = f.notes
+	}
+
+	return nil
+}
+
+func (r *runner) add(args []string) error {
+	var (
+		jsonOut bool
+		f       taskFlags
+		parent  int64
+	)
+	fs := r.newFlagSet(&jsonOut)
+	f.register(fs, false)
+	fs.Int64Var(&parent, "parent", 0, "ID of the task to add this as a subtask of")
+
+	args, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	qa := quickadd.Parse(strings.Join(args, " "), r.dates)
+	t := qa.Task
+	if strings.TrimSpace(t.Title) == "" {
+		return usagef("a title is needed")
+	}
+	if len(qa.Unresolved) > 0 {
+		fmt.Fprintln(r.errOut, "ignored", strings.Join(qa.Unresolved, " "))
+	}
+
+	// flags take priority over quick-add syntax in the title
+	set := setFlags(fs)
+	if qa.Priority != "" && !set["priority"] {
+		f.priority = qa.Priority
+		set["priority"] = true
+	}
+	if err := f.apply(r, t, set); err != nil {
+		return err
+	}
+
+	project := qa.Project
+	if set["project"] {
+		project = f.project
+	}
+
+	if parent != 0 {
+		p, err := r.task(strconv.FormatInt(parent, 10))
+		if err != nil {
+			return err
+		}
+		if project != "" {
+			return usagef("subtasks are always in their parent's project")
+		}
+		t.ParentTaskID = &p.ID
+		t.ProjectID = p.ProjectID
+	}
+
+	// any projects are created with the task, so a failed create doesn't leave them behind
+	var created *models.Task
+	err = r.stores.InTx(r.ctx, func(st *models.AllRepos) error {
+		var err error
+		if parent == 0 {
+			if t.ProjectID, err = r.projectID(st, project, r.cfg.QuickAddCreateProjects); err != nil {
+				return err
+			}
+		}
+
+		if created, err = st.Tasks.Create(r.ctx, t); err != nil {
+			return fmt.Errorf("creating task: %w", err)
+		}
+
+		for _, tag := range append(qa.Tags, splitList(f.tags)...) {
+			if err := st.Tags.AddToTask(r.ctx, created.ID, tag); err != nil {
+				return fmt.Errorf("adding tag %q: %w", tag, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return r.printTask(created, jsonOut, "created")
+}
+
+func (r *runner) ls(args []string) error {
+	var (
+		jsonOut bool
+		project string
+		all     bool
+		sortBy  string
+	)
+	fs := r.newFlagSet(&jsonOut)
+	fs.StringVar(&project, "project", "", `only list tasks in this project or its sub-projects, or "none"`)
+	fs.StringVar(&project, "p", "", "shorthand for -project")
+	fs.BoolVar(&all, "all", false, "include completed tasks")
+	fs.BoolVar(&all, "a", false, "shorthand for -all")
+	fs.StringVar(&sortBy, "sort", models.SortByComplete.Name(), "sort by "+sortNames())
+
+	args, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	params := models.SortParams{}
+	if params.SortBy, err = models.ParseSortBy(sortBy); err != nil {
+		return usagef("%v", err)
+	}
+
+	var exprs []string
+	if len(args) > 0 {
+		// parsed on its own first, so errors point at the right column
+		src := strings.Join(args, " ")
+		if _, err := filter.Parse(src); err != nil {
+			return usagef("filter %s", err)
+		}
+		exprs = append(exprs, "("+src+")")
+	}
+	if project != "" {
+		exprs = append(exprs, "project:"+quoteFilterValue(project))
+	}
+
+	var tasks []*models.Task
+	if len(exprs) == 0 {
+		tasks, err = r.stores.Tasks.ListAll(r.ctx)
+	} else {
+		var f *filter.Filter
+		if f, err = filter.Parse(strings.Join(exprs, " and ")); err != nil {
+			return usagef("filter %s", err)
+		}
+		tasks, err = r.stores.Tasks.Query(r.ctx, f)
+	}
+
+	var fe *filter.Error
+	if errors.As(err, &fe) {
+		return usagef("filter %s", err)
+	}
+	if err != nil {
+		return fmt.Errorf("listing tasks: %w", err)
+	}
+
+	if !all {
+		tasks = slices.DeleteFunc(tasks, func(t *models.Task) bool { return t.Complete })
+	}
+	models.SortTasks(tasks, params)
+
+	return r.printTasks(tasks, jsonOut)
+}
+
+func (r *runner) done(args []string) error {
+	var jsonOut bool
+	fs := r.newFlagSet(&jsonOut)
+	args, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	tasks, err := r.tasks(args)
+	if err != nil {
+		return err
+	}
+
+	var completed []*models.Task
+	for _, t := range tasks {
+		if t.Complete {
+			fmt.Fprintf(r.errOut, "task %d is already complete\n", t.ID)
+			continue
+		}
+
+		c, err := r.stores.CompleteTask(r.ctx, t, r.cfg.CascadeComplete)
+		if err != nil {
+			return err
+		}
+		completed = append(completed, c.Task)
+
+		if !jsonOut {
+			fmt.Fprintf(r.out, "completed task %d: %s\n", t.ID, t.Title)
+			if c.Next != nil {
+				fmt.Fprintf(r.out, "next occurrence is task %d, due %s\n", c.Next.ID, formatDue(*c.Next.DueAt))
+			}
+		}
+	}
+
+	if jsonOut {
+		return r.printTasks(completed, true)
+	}
+	return nil
+}
+
+func (r *runner) edit(args []string) error {
+	var (
+		jsonOut bool
+		f       taskFlags
+	)
+	fs := r.newFlagSet(&jsonOut)
+	f.register(fs, true)
+
+	args, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) != 1 {
+		return usagef("edit takes one task ID")
+	}
+
+	t, err := r.task(args[0])
+	if err != nil {
+		return err
+	}
+
+	set := setFlags(fs)
+	delete(set, "json")
+	if len(set) == 0 {
+		return usagef("nothing to change, see yata edit -h")
+	}
+
+	after := *t
+	if err := f.apply(r, &after, set); err != nil {
+		return err
+	}
+
+	var updated *models.Task
+	err = r.stores.InTx(r.ctx, func(st *models.AllRepos) error {
+		var (
+			projectID *int64
+			err       error
+		)
+		if set["project"] {
+			if projectID, err = r.projectID(st, f.project, false); err != nil {
+				return err
+			}
+		}
+
+		if updated, err = st.Tasks.Update(r.ctx, &after); err != nil {
+			return fmt.Errorf("updating task %d: %w", t.ID, err)
+		}
+
+		if set["tags"] {
+			if err := st.SetTaskTags(r.ctx, t.ID, splitList(f.tags)); err != nil {
+				return err
+			}
+		}
+
+		if set["project"] && !models.SameID(updated.ProjectID, projectID) {
+			if updated, _, err = st.MoveTask(r.ctx, updated, projectID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return r.printTask(updated, jsonOut, "updated")
+}
+
+func (r *runner) mv(args []string) error {
+	var jsonOut bool
+	fs := r.newFlagSet(&jsonOut)
+	args, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) < 2 {
+		return usagef("mv takes one or more task IDs and a project")
+	}
+
+	tasks, err := r.tasks(args[:len(args)-1])
+	if err != nil {
+		return err
+	}
+
+	projectID, err := r.projectID(r.stores, args[len(args)-1], false)
+	if err != nil {
+		return err
+	}
+
+	moved := make([]*models.Task, 0, len(tasks))
+	for _, t := range tasks {
+		if models.SameID(t.ProjectID, projectID) && t.ParentTaskID == nil {
+			fmt.Fprintf(r.errOut, "task %d is already there\n", t.ID)
+			continue
+		}
+
+		m, _, err := r.stores.MoveTask(r.ctx, t, projectID)
+		if err != nil {
+			return err
+		}
+		moved = append(moved, m)
+
+		if !jsonOut {
+			fmt.Fprintf(r.out, "moved task %d: %s\n", m.ID, m.Title)
+		}
+	}
+
+	if jsonOut {
+		return r.printTasks(moved, true)
+	}
+	return nil
+}
+
+func (r *runner) rm(args []string) error {
+	var jsonOut bool
+	fs := r.newFlagSet(&jsonOut)
+	args, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	tasks, err := r.tasks(args)
+	if err != nil {
+		return err
+	}
+
+	for _, t := range tasks {
+		if err := r.stores.Tasks.Trash(r.ctx, t.ID); err != nil {
+			return fmt.Errorf("trashing task %d: %w", t.ID, err)
+		}
+
+		if !jsonOut {
+			fmt.Fprintf(r.out, "trashed task %d: %s\n", t.ID, t.Title)
+		}
+	}
+
+	if jsonOut {
+		return r.printTasks(tasks, true)
+	}
+	return nil
+}
+
+// task gets the task with an ID given as an argument, which must not be in the trash.
+func (r *runner) task(arg string) (*models.Task, error) {
+	id, err := strconv.ParseInt(arg, 10, 64)
+	if err != nil {
+		return nil, usagef("%q isn't a task ID", arg)
+	}
+
+	t, err := r.stores.Tasks.Get(r.ctx, id)
+	if errors.Is(err, models.ErrNotFound) {
+		return nil, fmt.Errorf("no task with ID %d", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting task %d: %w", id, err)
+	}
+
+	if t.DeletedAt != nil {
+		return nil, fmt.Errorf("task %d is in the trash", id)
+	}
+
+	return t, nil
+}
+
+// tasks gets the tasks with the IDs given as arguments. They're all looked up before
+// anything is changed, so a bad ID leaves every task alone.
+func (r *runner) tasks(args []string) ([]*models.Task, error) {
+	if len(args) == 0 {
+		return nil, usagef("%s takes one or more task IDs", r.cmd.name)
+	}
+
+	tasks := make([]*models.Task, 0, len(args))
+	for _, a := range args {
+		t, err := r.task(a)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, t)
+	}
+
+	return tasks, nil
+}
+
+func (r *runner) parseDue(s string) (*time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" || strings.EqualFold(s, filter.None) {
+		return nil, nil
+	}
+
+	t, err := r.dates.Parse(s)
+	if err != nil {
+		return nil, usagef("due date %q: %v", s, err)
+	}
+
+	return &t, nil
+}
+
+// setRepeat sets a task's repeat rule from a flag, where "none" clears it.
+func setRepeat(t *models.Task, s string) error {
+	if strings.EqualFold(strings.TrimSpace(s), filter.None) {
+		s = ""
+	}
+
+	if err := t.SetRecurrence(s); err != nil {
+		return usagef("repeat rule %q: %v", s, err)
+	}
+	return nil
+}
+
+func sortNames() string {
+	names := make([]string, len(models.SortBys))
+	for i, sb := range models.SortBys {
+		names[i] = sb.Name()
+	}
+
+	return strings.Join(names, ", ")
+}
+
+// quoteFilterValue quotes a value for use in a filter expression.
+func quoteFilterValue(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
@@ -26,13 +26,65 @@ type ConfigIn struct {
 		BorderType    *string `json:"border_type"`
 	} `json:"unfocused"`
 
-	ErrorTextColor *uint `json:"error_text_color"`
+	Due struct {
+		OverdueColor  *uint `json:"overdue_color"`
+		TodayColor    *uint `json:"today_color"`
+		UpcomingColor *uint `json:"upcoming_color"`
+	} `json:"due"`
+
+	QuickAdd struct {
+		CreateProjects *bool `json:"create_projects"`
+	} `json:"quick_add"`
+
+	Trash struct {
+		AutoPurgeDays *int `json:"auto_purge_days"`
+	} `json:"trash"`
+
+	Archive struct {
+		AfterDays *int `json:"after_days"`
+	} `json:"archive"`
+
+	Serve struct {
+		Addr   *string `json:"addr"`
+		Socket *string `json:"socket"`
+		Token  *string `json:"token"`
+	} `json:"serve"`
+
+	ErrorTextColor  *uint `json:"error_text_color"`
+	TagColor        *uint `json:"tag_color"`
+	CascadeComplete *bool `json:"cascade_complete"`
 }
 
 type Config struct {
 	Focused        FocusedOpts
 	Unfocused      UnfocusedOpts
+	Due            DueOpts
 	ErrorTextColor lipgloss.ANSIColor
+	TagColor       lipgloss.ANSIColor
+
+	// CascadeComplete completes all of a task's subtasks when it is completed.
+	CascadeComplete bool
+
+	// QuickAddCreateProjects creates projects named in quick-add entries that don't exist
+	// yet, instead of rejecting the entry.
+	QuickAddCreateProjects bool
+
+	// TrashAutoPurgeDays is how long trashed items are kept before they're deleted for
+	// good at startup. Zero keeps them until the trash is emptied by hand.
+	TrashAutoPurgeDays int
+
+	// ArchiveAfterDays is how long a task has to have been complete before archiving
+	// moves it out of the task lists. Zero archives every completed task.
+	ArchiveAfterDays int
+
+	// ServeAddr is the address yata serve listens on, unless ServeSocket is set, in
+	// which case it listens on that Unix socket instead.
+	ServeAddr   string
+	ServeSocket string
+
+	// ServeToken is the bearer token API requests need. If it's empty, yata serve makes
+	// one up each time it starts.
+	ServeToken string
 }
 
 type FocusedOpts struct {
@@ -49,10 +101,24 @@ type UnfocusedOpts struct {
 	BorderType    lipgloss.Border
 }
 
+// DueOpts are the colors used for due date labels in the task list.
+type DueOpts struct {
+	OverdueColor  lipgloss.ANSIColor
+	TodayColor    lipgloss.ANSIColor
+	UpcomingColor lipgloss.ANSIColor
+}
+
 var (
 	defaultFocusedColor   = lipgloss.ANSIColor(4) // blue
 	defaultUnfocusedColor = lipgloss.ANSIColor(7) // white
 	defaultErrorColor     = lipgloss.ANSIColor(1) // red
+	defaultTodayColor     = lipgloss.ANSIColor(3) // yellow
+	defaultUpcomingColor  = lipgloss.ANSIColor(2) // green
+	defaultTagColor       = lipgloss.ANSIColor(6) // cyan
+
+	defaultTrashAutoPurgeDays = 30
+	defaultArchiveAfterDays   = 7
+	defaultServeAddr          = "127.0.0.1:7737"
 
 	defaultConfig = Config{
 		Focused: FocusedOpts{
@@ -67,7 +133,16 @@ var (
 			BoxTitleColor: defaultUnfocusedColor,
 			BorderType:    lipgloss.NormalBorder(),
 		},
-		ErrorTextColor: defaultErrorColor,
+		Due: DueOpts{
+			OverdueColor:  defaultErrorColor,
+			TodayColor:    defaultTodayColor,
+			UpcomingColor: defaultUpcomingColor,
+		},
+		ErrorTextColor:     defaultErrorColor,
+		TagColor:           defaultTagColor,
+		TrashAutoPurgeDays: defaultTrashAutoPurgeDays,
+		ArchiveAfterDays:   defaultArchiveAfterDays,
+		ServeAddr:          defaultServeAddr,
 	}
 )
 
@@ -108,7 +183,20 @@ func configInToConfig(in *ConfigIn) *Config {
 			BoxTitleColor: uintPtrToColor(in.Unfocused.BoxTitleColor, dc.Unfocused.BoxTitleColor),
 			BorderType:    strPtrToBorder(in.Unfocused.BorderType, dc.Unfocused.BorderType),
 		},
-		ErrorTextColor: uintPtrToColor(in.ErrorTextColor, dc.ErrorTextColor),
+		Due: DueOpts{
+			OverdueColor:  uintPtrToColor(in.Due.OverdueColor, dc.Due.OverdueColor),
+			TodayColor:    uintPtrToColor(in.Due.TodayColor, dc.Due.TodayColor),
+			UpcomingColor: uintPtrToColor(in.Due.UpcomingColor, dc.Due.UpcomingColor),
+		},
+		ErrorTextColor:         uintPtrToColor(in.ErrorTextColor, dc.ErrorTextColor),
+		TagColor:               uintPtrToColor(in.TagColor, dc.TagColor),
+		CascadeComplete:        boolPtrToBool(in.CascadeComplete, dc.CascadeComplete),
+		QuickAddCreateProjects: boolPtrToBool(in.QuickAdd.CreateProjects, dc.QuickAddCreateProjects),
+		TrashAutoPurgeDays:     intPtrToDays(in.Trash.AutoPurgeDays, dc.TrashAutoPurgeDays),
+		ArchiveAfterDays:       intPtrToDays(in.Archive.AfterDays, dc.ArchiveAfterDays),
+		ServeAddr:              strPtrToStr(in.Serve.Addr, dc.ServeAddr),
+		ServeSocket:            strPtrToStr(in.Serve.Socket, dc.ServeSocket),
+		ServeToken:             strPtrToStr(in.Serve.Token, dc.ServeToken),
 	}
 }
 
@@ -141,6 +229,30 @@ func uintPtrToColor(i *uint, defColor lipgloss.ANSIColor) lipgloss.ANSIColor {
 	return defColor
 }
 
+func boolPtrToBool(b *bool, defBool bool) bool {
+	if b != nil {
+		return *b
+	}
+
+	return defBool
+}
+
+func intPtrToDays(i *int, defDays int) int {
+	if i != nil && *i >= 0 {
+		return *i
+	}
+
+	return defDays
+}
+
+func strPtrToStr(s *string, defStr string) string {
+	if s != nil && strings.TrimSpace(*s) != "" {
+		return strings.TrimSpace(*s)
+	}
+
+	return defStr
+}
+
 func strPtrToBorder(s *string, defBorder lipgloss.Border) lipgloss.Border {
 	if s == nil {
 		return defBorder
@@ -0,0 +1,358 @@
+// Package dates parses human-friendly date phrases like "tomorrow", "next fri 5pm",
+// "in 2 weeks" or "eom" into times relative to a clock.
+package dates
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrUnrecognized is returned when a phrase can't be parsed as a date.
+var ErrUnrecognized = errors.New("unrecognized date")
+
+// Parser resolves date phrases relative to the time returned by Now, in the time zone Loc.
+type Parser struct {
+	Now func() time.Time
+	Loc *time.Location
+}
+
+// NewParser returns a parser that uses the system clock and local time zone.
+func NewParser() *Parser {
+	return &Parser{
+		Now: time.Now,
+		Loc: time.Local,
+	}
+}
+
+type day struct {
+	year  int
+	month time.Month
+	day   int
+}
+
+var (
+	absoluteLayouts = []string{
+		time.RFC3339,
+		"2006-01-02T15:04:05",
+		"2006-01-02T15:04",
+		"2006-01-02 15:04:05",
+		"2006-01-02 15:04",
+		"2006-01-02",
+		"2006/01/02",
+	}
+
+	timeRe       = regexp.MustCompile(`^(\d{1,2})(?::(\d{2}))?\s*(am|pm)$`)
+	clockRe      = regexp.MustCompile(`^(\d{1,2}):(\d{2})$`)
+	relativeRe   = regexp.MustCompile(`^(?:in\s+|\+)(\d+)\s*(d|days?|w|wks?|weeks?|m|mos?|months?|y|yrs?|years?)$`)
+	monthDayRe   = regexp.MustCompile(`^([a-z]+)\s+(\d{1,2})(?:st|nd|rd|th)?(?:,?\s+(\d{4}))?$`)
+	dayMonthRe   = regexp.MustCompile(`^(\d{1,2})(?:st|nd|rd|th)?\s+([a-z]+)(?:,?\s+(\d{4}))?$`)
+	whitespaceRe = regexp.MustCompile(`\s+`)
+)
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "sunday": time.Sunday,
+	"mon": time.Monday, "monday": time.Monday,
+	"tue": time.Tuesday, "tues": time.Tuesday, "tuesday": time.Tuesday,
+	"wed": time.Wednesday, "wednesday": time.Wednesday,
+	"thu": time.Thursday, "thur": time.Thursday, "thurs": time.Thursday, "thursday": time.Thursday,
+	"fri": time.Friday, "friday": time.Friday,
+	"sat": time.Saturday, "saturday": time.Saturday,
+}
+
+var months = map[string]time.Month{
+	"jan": time.January, "january": time.January,
+	"feb": time.February, "february": time.February,
+	"mar": time.March, "march": time.March,
+	"apr": time.April, "april": time.April,
+	"may": time.May,
+	"jun": time.June, "june": time.June,
+	"jul": time.July, "july": time.July,
+	"aug": time.August, "august": time.August,
+	"sep": time.September, "sept": time.September, "september": time.September,
+	"oct": time.October, "october": time.October,
+	"nov": time.November, "november": time.November,
+	"dec": time.December, "december": time.December,
+}
+
+// Parse resolves a phrase to a time. Phrases are made of an optional date part followed
+// by an optional time part, such as "tomorrow", "fri 5pm" or "in 3 days at 09:30".
+//
+// Supported date parts:
+//   - ISO dates and date-times, like 2025-03-14 or 2025-03-14 15:04
+//   - today, tomorrow (tmr, tom), yesterday
+//   - weekday names (mon, friday). A bare weekday or "next <weekday>" is the next one
+//     after today, while "this <weekday>" can also be today.
+//   - relative offsets like "in 2 weeks", "in 3d" or "+1m"
+//   - next week, next month, next year
+//   - eod, eow (the coming Sunday), eom (last day of the month), eoy
+//   - month and day, like "jan 5", "5 jan" or "march 3rd 2027". Without a year, the
+//     next occurrence on or after today is used.
+//
+// Supported time parts are 5pm, 5:30pm, 17:00, noon and midnight, optionally preceded by
+// "at". A time on its own is for today. Without a time part, the result is midnight at
+// the start of the day, except for eod which is the last minute of the day.
+func (p *Parser) Parse(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, fmt.Errorf("%w: empty input", ErrUnrecognized)
+	}
+
+	for _, layout := range absoluteLayouts {
+		if t, err := time.ParseInLocation(layout, s, p.location()); err == nil {
+			return t, nil
+		}
+	}
+
+	phrase := whitespaceRe.ReplaceAllString(strings.ToLower(s), " ")
+	datePart, hour, minute, hasTime := splitTime(phrase)
+
+	now := p.now()
+	d, ok := p.parseDay(datePart, now)
+	if !ok {
+		return time.Time{}, fmt.Errorf("%w: %q", ErrUnrecognized, s)
+	}
+
+	if !hasTime && datePart == "eod" {
+		hour, minute = 23, 59
+	}
+
+	return time.Date(d.year, d.month, d.day, hour, minute, 0, 0, p.location()), nil
+}
+
+// HasTime reports whether a parsed time has a time of day, rather than just being the
+// start of a day.
+func HasTime(t time.Time) bool {
+	return t.Hour() != 0 || t.Minute() != 0 || t.Second() != 0
+}
+
+func (p *Parser) now() time.Time {
+	if p.Now == nil {
+		return time.Now().In(p.location())
+	}
+	return p.Now().In(p.location())
+}
+
+func (p *Parser) location() *time.Location {
+	if p.Loc == nil {
+		return time.Local
+	}
+	return p.Loc
+}
+
+// splitTime removes a trailing time of day from the phrase, if there is one.
+func splitTime(phrase string) (rest string, hour, minute int, ok bool) {
+	words := strings.Split(phrase, " ")
+
+	// times like "5 pm" are split over two words, so try the last two words joined first
+	for n := min(2, len(words)); n >= 1; n-- {
+		last := strings.Join(words[len(words)-n:], "")
+		h, m, found := parseClock(last)
+		if !found {
+			continue
+		}
+
+		rest := words[:len(words)-n]
+		if len(rest) > 0 && rest[len(rest)-1] == "at" {
+			rest = rest[:len(rest)-1]
+		}
+		return strings.Join(rest, " "), h, m, true
+	}
+
+	return phrase, 0, 0, false
+}
+
+func parseClock(s string) (hour, minute int, ok bool) {
+	switch s {
+	case "noon":
+		return 12, 0, true
+	case "midnight":
+		return 0, 0, true
+	}
+
+	if m := timeRe.FindStringSubmatch(s); m != nil {
+		h, _ := strconv.Atoi(m[1])
+		mins := 0
+		if m[2] != "" {
+			mins, _ = strconv.Atoi(m[2])
+		}
+
+		if h < 1 || h > 12 || mins > 59 {
+			return 0, 0, false
+		}
+
+		if m[3] == "pm" && h != 12 {
+			h += 12
+		} else if m[3] == "am" && h == 12 {
+			h = 0
+		}
+		return h, mins, true
+	}
+
+	if m := clockRe.FindStringSubmatch(s); m != nil {
+		h, _ := strconv.Atoi(m[1])
+		mins, _ := strconv.Atoi(m[2])
+		if h > 23 || mins > 59 {
+			return 0, 0, false
+		}
+		return h, mins, true
+	}
+
+	return 0, 0, false
+}
+
+func (p *Parser) parseDay(s string, now time.Time) (day, bool) {
+	today := dayOf(now)
+
+	switch s {
+	case "", "today", "tod", "eod":
+		return today, true
+	case "tomorrow", "tmr", "tom":
+		return today.add(0, 0, 1, p.location()), true
+	case "yesterday":
+		return today.add(0, 0, -1, p.location()), true
+	case "next week":
+		return today.add(0, 0, 7, p.location()), true
+	case "next month":
+		return addMonthsClamped(today, 1, p.location()), true
+	case "next year":
+		return addMonthsClamped(today, 12, p.location()), true
+	case "eow":
+		return nextWeekday(today, time.Sunday, true, p.location()), true
+	case "eom":
+		return lastOfMonth(today.year, today.month, p.location()), true
+	case "eoy":
+		return day{year: today.year, month: time.December, day: 31}, true
+	}
+
+	if wd, ok := weekdays[s]; ok {
+		return nextWeekday(today, wd, false, p.location()), true
+	}
+
+	if first, rest, ok := strings.Cut(s, " "); ok {
+		if wd, ok := weekdays[rest]; ok {
+			switch first {
+			case "next":
+				return nextWeekday(today, wd, false, p.location()), true
+			case "this":
+				return nextWeekday(today, wd, true, p.location()), true
+			}
+		}
+	}
+
+	if m := relativeRe.FindStringSubmatch(s); m != nil {
+		n, err := strconv.Atoi(m[1])
+		if err != nil {
+			return day{}, false
+		}
+
+		switch m[2][0] {
+		case 'd':
+			return today.add(0, 0, n, p.location()), true
+		case 'w':
+			return today.add(0, 0, 7*n, p.location()), true
+		case 'm':
+			return addMonthsClamped(today, n, p.location()), true
+		case 'y':
+			return addMonthsClamped(today, 12*n, p.location()), true
+		}
+	}
+
+	if m := monthDayRe.FindStringSubmatch(s); m != nil {
+		return p.monthDay(m[1], m[2], m[3], today)
+	}
+
+	if m := dayMonthRe.FindStringSubmatch(s); m != nil {
+		return p.monthDay(m[2], m[1], m[3], today)
+	}
+
+	return day{}, false
+}
+
+// monthDay resolves a month name and day number. Without a year, it's the next
+// occurrence on or after today.
+func (p *Parser) monthDay(monthStr, dayStr, yearStr string, today day) (day, bool) {
+	month, ok := months[monthStr]
+	if !ok {
+		return day{}, false
+	}
+
+	d, err := strconv.Atoi(dayStr)
+	if err != nil {
+		return day{}, false
+	}
+
+	year := today.year
+	if yearStr != "" {
+		if year, err = strconv.Atoi(yearStr); err != nil {
+			return day{}, false
+		}
+	}
+
+	if d < 1 || d > lastOfMonth(year, month, p.location()).day {
+		return day{}, false
+	}
+
+	res := day{year: year, month: month, day: d}
+	if yearStr == "" && res.before(today) {
+		res.year++
+		if d > lastOfMonth(res.year, month, p.location()).day {
+			// Feb 29 that's already passed this year, and next year isn't a leap year
+			return day{}, false
+		}
+	}
+
+	return res, true
+}
+
+func dayOf(t time.Time) day {
+	y, m, d := t.Date()
+	return day{year: y, month: m, day: d}
+}
+
+// add adds calendar years, months and days. Going through time.Date at noon keeps the
+// result on the right day even across DST changes.
+func (d day) add(years, months, days int, loc *time.Location) day {
+	return dayOf(time.Date(d.year+years, d.month+time.Month(months), d.day+days, 12, 0, 0, 0, loc))
+}
+
+func (d day) before(o day) bool {
+	if d.year != o.year {
+		return d.year < o.year
+	}
+	if d.month != o.month {
+		return d.month < o.month
+	}
+	return d.day < o.day
+}
+
+func (d day) weekday(loc *time.Location) time.Weekday {
+	return time.Date(d.year, d.month, d.day, 12, 0, 0, 0, loc).Weekday()
+}
+
+// nextWeekday returns the next day that falls on wd. If includeToday is false, the result
+// is always 1-7 days ahead, otherwise it's 0-6 days ahead.
+func nextWeekday(from day, wd time.Weekday, includeToday bool, loc *time.Location) day {
+	diff := (int(wd) - int(from.weekday(loc)) + 7) % 7
+	if diff == 0 && !includeToday {
+		diff = 7
+	}
+	return from.add(0, 0, diff, loc)
+}
+
+func lastOfMonth(year int, month time.Month, loc *time.Location) day {
+	// day 0 of the next month normalizes to the last day of this month
+	return dayOf(time.Date(year, month+1, 0, 12, 0, 0, 0, loc))
+}
+
+// addMonthsClamped adds months without overflowing into the following month, so
+// Jan 31 plus one month is the last day of February rather than early March.
+func addMonthsClamped(d day, n int, loc *time.Location) day {
+	first := day{year: d.year, month: d.month, day: 1}.add(0, n, 0, loc)
+	last := lastOfMonth(first.year, first.month, loc)
+	first.day = min(d.day, last.day)
+	return first
+}
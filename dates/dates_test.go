@@ -0,0 +1,204 @@
+package dates
+
+import (
+	"errors"
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+func newYork(t testing.TB) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return loc
+}
+
+// parserAt returns a parser whose clock is stopped at the given wall clock time in New York.
+func parserAt(loc *time.Location, y int, m time.Month, d, h, min int) *Parser {
+	now := time.Date(y, m, d, h, min, 0, 0, loc)
+	return &Parser{Now: func() time.Time { return now }, Loc: loc}
+}
+
+func TestParse(t *testing.T) {
+	ny := newYork(t)
+	at := func(y int, m time.Month, d, h, min int) time.Time {
+		return time.Date(y, m, d, h, min, 0, 0, ny)
+	}
+
+	// Wednesday
+	wed := parserAt(ny, 2026, 3, 4, 10, 30)
+
+	tests := []struct {
+		name string
+		p    *Parser
+		in   string
+		want time.Time
+	}{
+		{"today", wed, "today", at(2026, 3, 4, 0, 0)},
+		{"tomorrow", wed, "tomorrow", at(2026, 3, 5, 0, 0)},
+		{"tmr", wed, "TMR", at(2026, 3, 5, 0, 0)},
+		{"yesterday", wed, "yesterday", at(2026, 3, 3, 0, 0)},
+
+		{"weekday later this week", wed, "fri", at(2026, 3, 6, 0, 0)},
+		{"weekday next week", wed, "monday", at(2026, 3, 9, 0, 0)},
+		{"bare weekday is never today", wed, "wed", at(2026, 3, 11, 0, 0)},
+		{"next weekday is never today", wed, "next wed", at(2026, 3, 11, 0, 0)},
+		{"next weekday", wed, "next thurs", at(2026, 3, 5, 0, 0)},
+		{"this weekday can be today", wed, "this wed", at(2026, 3, 4, 0, 0)},
+		{"this weekday", wed, "this sun", at(2026, 3, 8, 0, 0)},
+
+		{"in days", wed, "in 3 days", at(2026, 3, 7, 0, 0)},
+		{"in days over a month", wed, "in 30 days", at(2026, 4, 3, 0, 0)},
+		{"plus weeks", wed, "+2w", at(2026, 3, 18, 0, 0)},
+		{"in short days", wed, "in 3d", at(2026, 3, 7, 0, 0)},
+		{"in months", wed, "in 1 month", at(2026, 4, 4, 0, 0)},
+		{"in years", wed, "in 2 yrs", at(2028, 3, 4, 0, 0)},
+		{"next week", wed, "next week", at(2026, 3, 11, 0, 0)},
+		{"next month", wed, "next month", at(2026, 4, 4, 0, 0)},
+		{"next year", wed, "next year", at(2027, 3, 4, 0, 0)},
+
+		{"eod", wed, "eod", at(2026, 3, 4, 23, 59)},
+		{"eod with a time", wed, "eod 6pm", at(2026, 3, 4, 18, 0)},
+		{"eow", wed, "eow", at(2026, 3, 8, 0, 0)},
+		{"eom", wed, "eom", at(2026, 3, 31, 0, 0)},
+		{"eoy", wed, "eoy", at(2026, 12, 31, 0, 0)},
+
+		{"time alone is today", wed, "5pm", at(2026, 3, 4, 17, 0)},
+		{"time with minutes", wed, "fri 5:30pm", at(2026, 3, 6, 17, 30)},
+		{"split time", wed, "tomorrow at 5 pm", at(2026, 3, 5, 17, 0)},
+		{"24 hour time", wed, "in 3 days at 09:30", at(2026, 3, 7, 9, 30)},
+		{"noon", wed, "tomorrow noon", at(2026, 3, 5, 12, 0)},
+		{"midnight", wed, "midnight", at(2026, 3, 4, 0, 0)},
+		{"12am", wed, "12am", at(2026, 3, 4, 0, 0)},
+		{"12pm", wed, "12pm", at(2026, 3, 4, 12, 0)},
+
+		{"month day later this year", wed, "mar 10", at(2026, 3, 10, 0, 0)},
+		{"month day today", wed, "4 mar", at(2026, 3, 4, 0, 0)},
+		{"month day rolls over to next year", wed, "mar 1", at(2027, 3, 1, 0, 0)},
+		{"month day in january", wed, "jan 5", at(2027, 1, 5, 0, 0)},
+		{"month day with a year", wed, "march 3rd 2027", at(2027, 3, 3, 0, 0)},
+		{"day month with a year", wed, "3rd march, 2025", at(2025, 3, 3, 0, 0)},
+		{"month day with a time", wed, "dec 25 9am", at(2026, 12, 25, 9, 0)},
+		{"leap day with a year", wed, "feb 29 2028", at(2028, 2, 29, 0, 0)},
+
+		{"next month from the 31st", parserAt(ny, 2026, 1, 31, 8, 0), "next month", at(2026, 2, 28, 0, 0)},
+		{"in a month from the 31st", parserAt(ny, 2026, 1, 31, 8, 0), "in 1m", at(2026, 2, 28, 0, 0)},
+		{"in months from the 31st", parserAt(ny, 2026, 3, 31, 8, 0), "in 3 months", at(2026, 6, 30, 0, 0)},
+		{"next month into a leap february", parserAt(ny, 2024, 1, 30, 8, 0), "next month", at(2024, 2, 29, 0, 0)},
+		{"next year from a leap day", parserAt(ny, 2024, 2, 29, 8, 0), "next year", at(2025, 2, 28, 0, 0)},
+		{"eom in a leap february", parserAt(ny, 2024, 2, 10, 8, 0), "eom", at(2024, 2, 29, 0, 0)},
+		{"eom in february", parserAt(ny, 2026, 2, 10, 8, 0), "eom", at(2026, 2, 28, 0, 0)},
+		{"tomorrow at the month end", parserAt(ny, 2026, 4, 30, 8, 0), "tomorrow", at(2026, 5, 1, 0, 0)},
+		{"tomorrow at the year end", parserAt(ny, 2026, 12, 31, 23, 59), "tomorrow", at(2027, 1, 1, 0, 0)},
+
+		{"tomorrow is spring forward", parserAt(ny, 2026, 3, 7, 22, 0), "tomorrow", at(2026, 3, 8, 0, 0)},
+		{"time on spring forward", parserAt(ny, 2026, 3, 7, 22, 0), "tomorrow 9am", at(2026, 3, 8, 9, 0)},
+		{"today on spring forward", parserAt(ny, 2026, 3, 8, 23, 30), "today", at(2026, 3, 8, 0, 0)},
+		{"next week over spring forward", parserAt(ny, 2026, 3, 5, 23, 30), "next week", at(2026, 3, 12, 0, 0)},
+		{"tomorrow is fall back", parserAt(ny, 2026, 10, 31, 23, 0), "tomorrow 5pm", at(2026, 11, 1, 17, 0)},
+		{"today on fall back", parserAt(ny, 2026, 11, 1, 1, 30), "today", at(2026, 11, 1, 0, 0)},
+		{"eod on fall back", parserAt(ny, 2026, 11, 1, 1, 30), "eod", at(2026, 11, 1, 23, 59)},
+		{"in days over fall back", parserAt(ny, 2026, 10, 31, 23, 30), "in 2 days", at(2026, 11, 2, 0, 0)},
+
+		{"iso date", wed, "2025-03-14", at(2025, 3, 14, 0, 0)},
+		{"iso date with slashes", wed, "2025/03/14", at(2025, 3, 14, 0, 0)},
+		{"iso date and time", wed, "2025-03-14 15:04", at(2025, 3, 14, 15, 4)},
+		{"iso date and time with seconds", wed, "2025-03-14 15:04:05", time.Date(2025, 3, 14, 15, 4, 5, 0, ny)},
+		{"iso date and time with a T", wed, "2025-03-14T15:04", at(2025, 3, 14, 15, 4)},
+		{"rfc 3339", wed, "2025-03-14T15:04:05Z", time.Date(2025, 3, 14, 15, 4, 5, 0, time.UTC)},
+		{"rfc 3339 with an offset", wed, "2025-03-14T15:04:05-07:00", time.Date(2025, 3, 14, 22, 4, 5, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.p.Parse(tt.in)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.in, err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("Parse(%q) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseUnrecognized(t *testing.T) {
+	ny := newYork(t)
+	p := parserAt(ny, 2026, 3, 4, 10, 30)
+
+	tests := []string{
+		"",
+		"   ",
+		"someday",
+		"next blursday",
+		"this week",
+		"in x days",
+		"in 3 fortnights",
+		"feb 30",
+		"31 apr 2026",
+		"feb 29",
+		"smarch 3",
+		"13pm",
+		"0am",
+		"25:00",
+		"12:60",
+		"tomorrow at",
+		"2025-13-01",
+	}
+
+	for _, in := range tests {
+		t.Run(in, func(t *testing.T) {
+			got, err := p.Parse(in)
+			if !errors.Is(err, ErrUnrecognized) {
+				t.Errorf("Parse(%q) = %v, %v, want ErrUnrecognized", in, got, err)
+			}
+		})
+	}
+}
+
+func TestParsePassedLeapDay(t *testing.T) {
+	// Feb 29 has passed and next year has none
+	p := parserAt(newYork(t), 2024, 3, 4, 10, 30)
+	if got, err := p.Parse("feb 29"); !errors.Is(err, ErrUnrecognized) {
+		t.Errorf("Parse(feb 29) = %v, %v, want ErrUnrecognized", got, err)
+	}
+}
+
+func TestHasTime(t *testing.T) {
+	p := parserAt(time.UTC, 2026, 3, 4, 10, 30)
+	for in, want := range map[string]bool{"tomorrow": false, "tomorrow 9am": true, "midnight": false, "eod": true} {
+		got, err := p.Parse(in)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", in, err)
+		}
+		if HasTime(got) != want {
+			t.Errorf("HasTime(Parse(%q)) = %v, want %v", in, !want, want)
+		}
+	}
+}
+
+func FuzzParse(f *testing.F) {
+	for _, s := range []string{
+		"today", "next fri 5pm", "in 2 weeks", "+1m", "eom", "jan 5", "5 jan 2027", "march 3rd",
+		"2025-03-14", "2025-03-14T15:04:05Z", "at 5 pm", "noon", "17:00", "in 99999999999999999999 days",
+	} {
+		f.Add(s)
+	}
+
+	p := parserAt(newYork(f), 2026, 3, 4, 10, 30)
+	f.Fuzz(func(t *testing.T, s string) {
+		got, err := p.Parse(s)
+		if err != nil {
+			if !errors.Is(err, ErrUnrecognized) {
+				t.Errorf("Parse(%q) returned %v, want ErrUnrecognized", s, err)
+			}
+			return
+		}
+		if got.IsZero() {
+			t.Errorf("Parse(%q) returned the zero time without an error", s)
+		}
+	})
+}
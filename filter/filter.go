@@ -0,0 +1,296 @@
+// Package filter parses filter expressions for task lists, like
+// "project:work and (due<=+3d or priority>=high) and not done and tag:waiting".
+//
+// An expression is made of conditions joined with and, or and not, and grouped with
+// parentheses. Conditions side by side with nothing between them are joined with and,
+// which binds tighter than or. A condition is either a field compared to a value, like
+// due<=fri or tag:waiting, or a bare word. The words done, blocked and recurring match
+// tasks that are complete, waiting on another task or repeating, and any other bare
+// word matches tasks with it in their title. Values with spaces or operator characters
+// in them can be quoted, like title:"buy milk" or due<="fri 5pm".
+//
+// The fields are project (a title, a path like work/clients, or none), tag, title, due (a
+// date phrase, none or any) and priority. Project and tag take :, = and !=, as does title,
+// where : means the title contains the value. Due and priority can also be compared with
+// <, <=, > and >=.
+//
+// Parsing only checks an expression's structure. Values like dates and priorities are
+// interpreted when it's compiled into a query.
+package filter
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Field is what a condition tests.
+type Field string
+
+const (
+	Project   Field = "project"
+	Tag       Field = "tag"
+	Due       Field = "due"
+	Priority  Field = "priority"
+	Title     Field = "title"
+	Done      Field = "done"
+	Blocked   Field = "blocked"
+	Recurring Field = "recurring"
+)
+
+// Op compares a field to a value. Is, written with a colon, is a loose match: it tests
+// whether the title contains the value, and is the same as Eq for the other fields.
+type Op string
+
+const (
+	Is Op = ":"
+	Eq Op = "="
+	Ne Op = "!="
+	Lt Op = "<"
+	Le Op = "<="
+	Gt Op = ">"
+	Ge Op = ">="
+)
+
+// None and Any are the values for project:none, due:none and due:any, which match tasks
+// without a project or due date, or with any due date.
+const (
+	None = "none"
+	Any  = "any"
+)
+
+var (
+	equalityOps = []Op{Is, Eq, Ne}
+	allOps      = []Op{Is, Eq, Ne, Lt, Le, Gt, Ge}
+
+	// fieldOps are the operators each field with a value can be used with. Fields that
+	// aren't here are flags, which are used on their own.
+	fieldOps = map[Field][]Op{
+		Project:  equalityOps,
+		Tag:      equalityOps,
+		Title:    equalityOps,
+		Due:      allOps,
+		Priority: allOps,
+	}
+	flags = []Field{Done, Blocked, Recurring}
+)
+
+type (
+	// Filter is a parsed filter expression.
+	Filter struct {
+		Expr Expr
+		src  string
+	}
+
+	// Expr is a node in a parsed expression: an And, Or, Not or Cond.
+	Expr interface {
+		// Pos is the column the expression starts at, counting from 1.
+		Pos() int
+		expr()
+	}
+
+	And struct{ X, Y Expr }
+	Or  struct{ X, Y Expr }
+	Not struct {
+		X      Expr
+		NotPos int
+	}
+
+	// Cond is a single condition. Op and Value are empty for a flag, like done.
+	Cond struct {
+		Field    Field
+		Op       Op
+		Value    string
+		FieldPos int
+		ValuePos int
+	}
+
+	// Error is a problem with an expression at a column, counting from 1.
+	Error struct {
+		Pos int
+		Msg string
+	}
+)
+
+func (e And) Pos() int  { return e.X.Pos() }
+func (e Or) Pos() int   { return e.X.Pos() }
+func (e Not) Pos() int  { return e.NotPos }
+func (e Cond) Pos() int { return e.FieldPos }
+
+func (And) expr()  {}
+func (Or) expr()   {}
+func (Not) expr()  {}
+func (Cond) expr() {}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("column %d: %s", e.Pos, e.Msg)
+}
+
+// Errorf creates an Error, for problems found with an expression after it's parsed.
+func Errorf(pos int, format string, args ...any) *Error {
+	return &Error{Pos: pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+// String returns the expression as it was written.
+func (f *Filter) String() string {
+	return f.src
+}
+
+// Parse parses an expression. Any error is an *Error with the column of the problem.
+func Parse(s string) (*Filter, error) {
+	toks, err := lex(s)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{toks: toks}
+	if p.peek().kind == tokEOF {
+		return nil, Errorf(1, "empty filter")
+	}
+
+	e, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if t := p.peek(); t.kind != tokEOF {
+		if t.kind == tokRParen {
+			return nil, Errorf(t.pos, "unexpected )")
+		}
+		return nil, Errorf(t.pos, "unexpected %q", t.text)
+	}
+
+	return &Filter{Expr: e, src: strings.TrimSpace(s)}, nil
+}
+
+type parser struct {
+	toks []token
+	i    int
+}
+
+func (p *parser) peek() token {
+	return p.toks[p.i]
+}
+
+func (p *parser) next() token {
+	t := p.toks[p.i]
+	if t.kind != tokEOF {
+		p.i++
+	}
+	return t
+}
+
+func (p *parser) parseOr() (Expr, error) {
+	x, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek().isKeyword("or") {
+		p.next()
+		y, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		x = Or{X: x, Y: y}
+	}
+
+	return x, nil
+}
+
+func (p *parser) parseAnd() (Expr, error) {
+	x, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		t := p.peek()
+		switch {
+		case t.isKeyword("and"):
+			p.next()
+		case t.kind == tokWord && !t.isKeyword("or"), t.kind == tokString, t.kind == tokLParen:
+			// conditions side by side are joined with and
+		default:
+			return x, nil
+		}
+
+		y, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		x = And{X: x, Y: y}
+	}
+}
+
+func (p *parser) parseUnary() (Expr, error) {
+	t := p.peek()
+	switch {
+	case t.isKeyword("not"):
+		p.next()
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return Not{X: x, NotPos: t.pos}, nil
+
+	case t.kind == tokLParen:
+		p.next()
+		x, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+
+		if r := p.next(); r.kind != tokRParen {
+			return nil, Errorf(r.pos, "missing ) to close the ( at column %d", t.pos)
+		}
+		return x, nil
+	}
+
+	return p.parseCond()
+}
+
+func (p *parser) parseCond() (Expr, error) {
+	t := p.next()
+	switch {
+	case t.kind == tokEOF:
+		return nil, Errorf(t.pos, "expected a condition")
+	case t.kind == tokRParen:
+		return nil, Errorf(t.pos, "unexpected )")
+	case t.kind == tokOp:
+		return nil, Errorf(t.pos, "expected a field before %s", t.text)
+	case t.isKeyword("and"), t.isKeyword("or"):
+		return nil, Errorf(t.pos, "expected a condition before %q", t.text)
+	}
+
+	if p.peek().kind != tokOp {
+		if f := Field(strings.ToLower(t.text)); t.kind == tokWord && slices.Contains(flags, f) {
+			return Cond{Field: f, FieldPos: t.pos}, nil
+		}
+		return Cond{Field: Title, Op: Is, Value: t.text, FieldPos: t.pos, ValuePos: t.pos}, nil
+	}
+
+	field := Field(strings.ToLower(t.text))
+	ops, ok := fieldOps[field]
+	if t.kind != tokWord || !ok {
+		if slices.Contains(flags, field) {
+			return nil, Errorf(t.pos, "%s is used on its own, like \"%s\" or \"not %s\"", field, field, field)
+		}
+		return nil, Errorf(t.pos, "unknown field %q", t.text)
+	}
+
+	op := p.next()
+	if !slices.Contains(ops, Op(op.text)) {
+		return nil, Errorf(op.pos, "%s can't be used with %s", op.text, field)
+	}
+
+	v := p.next()
+	if (v.kind != tokWord && v.kind != tokString) || v.isKeyword("and") || v.isKeyword("or") || v.isKeyword("not") {
+		return nil, Errorf(v.pos, "expected a value after %s%s", field, op.text)
+	}
+	if strings.TrimSpace(v.text) == "" {
+		return nil, Errorf(v.pos, "empty value for %s", field)
+	}
+
+	return Cond{Field: field, Op: Op(op.text), Value: v.text, FieldPos: t.pos, ValuePos: v.pos}, nil
+}
@@ -0,0 +1,163 @@
+package filter
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// show writes an expression with its structure spelled out, like (and tag:a (not done)).
+func show(e Expr) string {
+	switch e := e.(type) {
+	case And:
+		return fmt.Sprintf("(and %s %s)", show(e.X), show(e.Y))
+	case Or:
+		return fmt.Sprintf("(or %s %s)", show(e.X), show(e.Y))
+	case Not:
+		return fmt.Sprintf("(not %s)", show(e.X))
+	case Cond:
+		if e.Op == "" {
+			return string(e.Field)
+		}
+		return fmt.Sprintf("%s%s%q", e.Field, e.Op, e.Value)
+	}
+	return fmt.Sprintf("%T", e)
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"project:work", `project:"work"`},
+		{"PROJECT:work/clients", `project:"work/clients"`},
+		{"tag=waiting", `tag="waiting"`},
+		{"tag!=waiting", `tag!="waiting"`},
+		{"due<=+3d", `due<="+3d"`},
+		{"due<fri due>mon due>=1 due=today", `(and (and (and due<"fri" due>"mon") due>="1") due="today")`},
+		{"priority>=high", `priority>="high"`},
+		{`title:"buy milk"`, `title:"buy milk"`},
+		{`due<="fri 5pm"`, `due<="fri 5pm"`},
+		{`title:"say \"hi\""`, `title:"say \"hi\""`},
+		{`title:"a\\b"`, `title:"a\\b"`},
+
+		{"milk", `title:"milk"`},
+		{`"and"`, `title:"and"`},
+		{"done", "done"},
+		{"DONE", "done"},
+		{"blocked recurring", "(and blocked recurring)"},
+
+		{"a and b", `(and title:"a" title:"b")`},
+		{"a b", `(and title:"a" title:"b")`},
+		{"a or b", `(or title:"a" title:"b")`},
+		{"a OR b Or c", `(or (or title:"a" title:"b") title:"c")`},
+		{"a or b c", `(or title:"a" (and title:"b" title:"c"))`},
+		{"a b or c", `(or (and title:"a" title:"b") title:"c")`},
+		{"a and b or c and d", `(or (and title:"a" title:"b") (and title:"c" title:"d"))`},
+		{"(a or b) c", `(and (or title:"a" title:"b") title:"c")`},
+		{"a (b or c)", `(and title:"a" (or title:"b" title:"c"))`},
+		{"((a))", `title:"a"`},
+
+		{"not done", "(not done)"},
+		{"not not done", "(not (not done))"},
+		{"not a b", `(and (not title:"a") title:"b")`},
+		{"not (a or b)", `(not (or title:"a" title:"b"))`},
+		{"not a or b", `(or (not title:"a") title:"b")`},
+
+		{
+			"project:work and (due<=+3d or priority>=high) and not done and tag:waiting",
+			`(and (and (and project:"work" (or due<="+3d" priority>="high")) (not done)) tag:"waiting")`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			f, err := Parse(tt.in)
+			if err != nil {
+				t.Fatalf("Parse(%q) = %v", tt.in, err)
+			}
+			if got := show(f.Expr); got != tt.want {
+				t.Errorf("Parse(%q) = %s, want %s", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseKeepsSource(t *testing.T) {
+	f, err := Parse("  tag:a  or b ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.String() != "tag:a  or b" {
+		t.Errorf("String() = %q, want %q", f.String(), "tag:a  or b")
+	}
+}
+
+func TestParsePositions(t *testing.T) {
+	// positions count runes, so the é before the condition is one column
+	f, err := Parse(`é or not  due<="fri 5pm"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	or := f.Expr.(Or)
+	if or.Pos() != 1 {
+		t.Errorf("or at column %d, want 1", or.Pos())
+	}
+
+	not := or.Y.(Not)
+	if not.Pos() != 6 {
+		t.Errorf("not at column %d, want 6", not.Pos())
+	}
+
+	due := not.X.(Cond)
+	if due.FieldPos != 11 || due.ValuePos != 16 {
+		t.Errorf("due at columns %d and %d, want 11 and 16", due.FieldPos, due.ValuePos)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		in  string
+		pos int
+		msg string
+	}{
+		{"", 1, "empty filter"},
+		{"   ", 1, "empty filter"},
+		{`title:"milk`, 7, "unterminated quote"},
+		{"a ! b", 3, "did you mean != or not"},
+		{"a)", 2, "unexpected )"},
+		{"(a", 3, "missing ) to close the ( at column 1"},
+		{"(a b", 5, "missing ) to close the ( at column 1"},
+		{"()", 2, "unexpected )"},
+		{"a and", 6, "expected a condition"},
+		{"not", 4, "expected a condition"},
+		{"a or or b", 6, `expected a condition before "or"`},
+		{"and a", 1, `expected a condition before "and"`},
+		{":work", 1, "expected a field before :"},
+		{"colour:red", 1, `unknown field "colour"`},
+		{`"project":work`, 1, `unknown field "project"`},
+		{"done:yes", 1, "done is used on its own"},
+		{"project<work", 8, "< can't be used with project"},
+		{"tag>=a", 4, ">= can't be used with tag"},
+		{"tag:", 5, "expected a value after tag:"},
+		{"tag:and", 5, "expected a value after tag:"},
+		{"due<=(fri)", 6, "expected a value after due<="},
+		{`title:""`, 7, "empty value for title"},
+		{"é colour:red", 3, `unknown field "colour"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			_, err := Parse(tt.in)
+			var fe *Error
+			if !errors.As(err, &fe) {
+				t.Fatalf("Parse(%q) = %v, want an *Error", tt.in, err)
+			}
+			if fe.Pos != tt.pos || !strings.Contains(fe.Msg, tt.msg) {
+				t.Errorf("Parse(%q) = %v, want column %d: ...%s...", tt.in, err, tt.pos, tt.msg)
+			}
+		})
+	}
+}
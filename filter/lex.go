@@ -0,0 +1,94 @@
+package filter
+
+import (
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokWord
+	tokString
+	tokOp
+	tokLParen
+	tokRParen
+)
+
+// token is a piece of an expression. For a string, text is its value without the quotes.
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func (t token) isKeyword(k string) bool {
+	return t.kind == tokWord && strings.EqualFold(t.text, k)
+}
+
+// lex splits an expression into tokens, ending with an EOF token. Positions are counted
+// in runes, so they line up with what was typed.
+func lex(s string) ([]token, error) {
+	rs := []rune(s)
+
+	var toks []token
+	for i := 0; i < len(rs); {
+		r, pos := rs[i], i+1
+		switch {
+		case unicode.IsSpace(r):
+			i++
+
+		case r == '(':
+			toks = append(toks, token{kind: tokLParen, text: "(", pos: pos})
+			i++
+
+		case r == ')':
+			toks = append(toks, token{kind: tokRParen, text: ")", pos: pos})
+			i++
+
+		case r == '"':
+			var b strings.Builder
+			j := i + 1
+			for ; j < len(rs) && rs[j] != '"'; j++ {
+				if rs[j] == '\\' && j+1 < len(rs) {
+					j++
+				}
+				b.WriteRune(rs[j])
+			}
+			if j >= len(rs) {
+				return nil, Errorf(pos, "unterminated quote")
+			}
+
+			toks = append(toks, token{kind: tokString, text: b.String(), pos: pos})
+			i = j + 1
+
+		case isOpRune(r):
+			op := string(r)
+			if i+1 < len(rs) && rs[i+1] == '=' && r != ':' && r != '=' {
+				op += "="
+			}
+			if op == "!" {
+				return nil, Errorf(pos, "unexpected !, did you mean != or not?")
+			}
+
+			toks = append(toks, token{kind: tokOp, text: op, pos: pos})
+			i += len(op)
+
+		default:
+			j := i
+			for j < len(rs) && !unicode.IsSpace(rs[j]) && !isOpRune(rs[j]) && !strings.ContainsRune(`()"`, rs[j]) {
+				j++
+			}
+
+			toks = append(toks, token{kind: tokWord, text: string(rs[i:j]), pos: pos})
+			i = j
+		}
+	}
+
+	return append(toks, token{kind: tokEOF, pos: len(rs) + 1}), nil
+}
+
+func isOpRune(r rune) bool {
+	return strings.ContainsRune(":=!<>", r)
+}
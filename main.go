@@ -2,12 +2,15 @@ package main
 
 import (
 	"context"
-	_ "embed"
+	"embed"
 	"fmt"
+	"io/fs"
 	"log/slog"
 	"os"
 	"path/filepath"
+	"time"
 
+	"github.com/dsrosen6/yata/cli"
 	"github.com/dsrosen6/yata/config"
 	"github.com/dsrosen6/yata/logging"
 	"github.com/dsrosen6/yata/sqlitedb"
@@ -15,16 +18,18 @@ import (
 	_ "modernc.org/sqlite"
 )
 
-//go:embed schema.sql
-var schema string
+//go:embed migrations/*.sql
+var migrations embed.FS
 
 func main() {
-	if err := run(); err != nil {
-		fmt.Println("Error:", err)
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(cli.ExitCode(err))
 	}
 }
 
-func run() error {
+// run opens the TUI, or runs a subcommand if any arguments are given.
+func run(args []string) error {
 	ctx := context.Background()
 	cfg, err := config.GetConfig()
 	if err != nil {
@@ -43,12 +48,27 @@ func run() error {
 	}
 
 	debug := os.Getenv("YATA_DEBUG") == "true"
-	if err := logging.Init("./yata.log", debug); err != nil {
-		fmt.Println("Error initiating logger:", err)
+	if len(args) > 0 {
+		// subcommands are run from scripts and hooks, so they log warnings to stderr
+		// rather than leaving a log file in whatever directory they're run from
+		lvl := slog.LevelWarn
+		if debug {
+			lvl = slog.LevelDebug
+		}
+		slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: lvl})))
+	} else {
+		if err := logging.Init("./yata.log", debug); err != nil {
+			fmt.Println("Error initiating logger:", err)
+		}
+		slog.SetDefault(logging.Logger)
+	}
+
+	mfs, err := fs.Sub(migrations, "migrations")
+	if err != nil {
+		return fmt.Errorf("reading embedded migrations: %w", err)
 	}
-	slog.SetDefault(logging.Logger)
 
-	d, err := sqlitedb.NewHandler(schema, filepath.Join(dir, "app.db"))
+	d, err := sqlitedb.NewHandler(mfs, filepath.Join(dir, "app.db"))
 	if err != nil {
 		return fmt.Errorf("initializing sqlite handler: %w", err)
 	}
@@ -64,5 +84,19 @@ func run() error {
 		return fmt.Errorf("initializing repositories: %w", err)
 	}
 
+	if len(args) > 0 {
+		return cli.Run(ctx, cfg, stores, args, os.Stdout, os.Stderr)
+	}
+
+	if days := cfg.TrashAutoPurgeDays; days > 0 {
+		n, err := stores.PurgeTrash(ctx, time.Now().AddDate(0, 0, -days))
+		if err != nil {
+			return fmt.Errorf("purging old trash: %w", err)
+		}
+		if n > 0 {
+			slog.Info("purged old trash", "items", n, "older_than_days", days)
+		}
+	}
+
 	return tui.Run(cfg, stores)
 }
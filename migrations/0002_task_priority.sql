@@ -0,0 +1 @@
+ALTER TABLE task ADD COLUMN priority INTEGER NOT NULL DEFAULT 0;
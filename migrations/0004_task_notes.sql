@@ -0,0 +1 @@
+ALTER TABLE task ADD COLUMN notes TEXT NOT NULL DEFAULT '';
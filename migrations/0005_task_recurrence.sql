@@ -0,0 +1 @@
+ALTER TABLE task ADD COLUMN recurrence TEXT NOT NULL DEFAULT '';
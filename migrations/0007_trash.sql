@@ -0,0 +1,5 @@
+ALTER TABLE task ADD COLUMN deleted_at DATETIME;
+ALTER TABLE project ADD COLUMN deleted_at DATETIME;
+
+CREATE INDEX task_deleted_at_idx ON task(deleted_at);
+CREATE INDEX project_deleted_at_idx ON project(deleted_at);
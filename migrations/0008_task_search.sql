@@ -0,0 +1,21 @@
+CREATE VIRTUAL TABLE task_fts USING fts5(
+    title,
+    content = 'task',
+    content_rowid = 'id',
+    tokenize = 'unicode61 remove_diacritics 2'
+);
+
+INSERT INTO task_fts (task_fts) VALUES ('rebuild');
+
+CREATE TRIGGER task_fts_after_insert AFTER INSERT ON task BEGIN
+    INSERT INTO task_fts (rowid, title) VALUES (new.id, new.title);
+END;
+
+CREATE TRIGGER task_fts_after_delete AFTER DELETE ON task BEGIN
+    INSERT INTO task_fts (task_fts, rowid, title) VALUES ('delete', old.id, old.title);
+END;
+
+CREATE TRIGGER task_fts_after_update AFTER UPDATE OF title ON task BEGIN
+    INSERT INTO task_fts (task_fts, rowid, title) VALUES ('delete', old.id, old.title);
+    INSERT INTO task_fts (rowid, title) VALUES (new.id, new.title);
+END;
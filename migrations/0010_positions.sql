@@ -0,0 +1,8 @@
+-- position orders tasks and projects among their siblings. It's a real number so an item
+-- can be moved between two others by taking a value between theirs, without renumbering.
+ALTER TABLE task ADD COLUMN position REAL NOT NULL DEFAULT 0;
+ALTER TABLE project ADD COLUMN position REAL NOT NULL DEFAULT 0;
+
+-- existing items keep the order they were created in
+UPDATE task SET position = id;
+UPDATE project SET position = id;
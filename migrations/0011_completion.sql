@@ -0,0 +1,8 @@
+ALTER TABLE task ADD COLUMN completed_at DATETIME;
+ALTER TABLE task ADD COLUMN archived_at DATETIME;
+
+-- the last update is the best guess at when tasks that are already complete were finished
+UPDATE task SET completed_at = updated_at WHERE complete = 1;
+
+CREATE INDEX task_completed_at_idx ON task(completed_at);
+CREATE INDEX task_archived_at_idx ON task(archived_at);
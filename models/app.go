@@ -1,6 +1,14 @@
 package models
 
-import "context"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrNotFound is returned when getting a task or project that doesn't exist.
+var ErrNotFound = errors.New("not found")
 
 type StoreHandler interface {
 	CreateRepos(ctx context.Context) (*AllRepos, error)
@@ -10,4 +18,55 @@ type StoreHandler interface {
 type AllRepos struct {
 	Tasks    TaskRepo
 	Projects ProjectRepo
+	Tags     TagRepo
+	Views    ViewRepo
+
+	// Changes notices changes made to the database by other processes, like the CLI or
+	// another yata window. It's nil if the store can't tell.
+	Changes ChangeWatcher
+
+	// Tx groups changes into one transaction. It's nil if the store can't, in which case
+	// they're made one at a time.
+	Tx Transactor
+}
+
+// Transactor runs changes that belong together so they're made all at once or not at all.
+type Transactor interface {
+	// InTx calls fn with repos whose changes are part of one transaction, committing it
+	// if fn returns nil and rolling it back otherwise. Calls made within fn join it.
+	InTx(ctx context.Context, fn func(r *AllRepos) error) error
+}
+
+// ChangeWatcher reports when the database has been changed from elsewhere.
+type ChangeWatcher interface {
+	// DataVersion returns a number that changes whenever another process commits a
+	// change. Only comparing it to an earlier value means anything.
+	DataVersion(ctx context.Context) (int64, error)
+}
+
+// InTx calls fn with repos whose changes are made in one transaction, if the store
+// supports them, or with r itself if it doesn't.
+func (r *AllRepos) InTx(ctx context.Context, fn func(r *AllRepos) error) error {
+	if r.Tx == nil {
+		return fn(r)
+	}
+
+	return r.Tx.InTx(ctx, fn)
+}
+
+// PurgeTrash permanently deletes the tasks and projects trashed before the given time,
+// returning how many were removed. Subtasks deleted along with their parent aren't counted.
+func (r *AllRepos) PurgeTrash(ctx context.Context, before time.Time) (int64, error) {
+	// tasks go first, so the ones removed along with their projects still get counted
+	tasks, err := r.Tasks.PurgeDeletedBefore(ctx, before)
+	if err != nil {
+		return 0, fmt.Errorf("purging tasks: %w", err)
+	}
+
+	projects, err := r.Projects.PurgeDeletedBefore(ctx, before)
+	if err != nil {
+		return 0, fmt.Errorf("purging projects: %w", err)
+	}
+
+	return tasks + projects, nil
 }
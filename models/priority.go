@@ -0,0 +1,71 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type Priority int
+
+const (
+	PriorityNone Priority = iota
+	PriorityLow
+	PriorityMedium
+	PriorityHigh
+	PriorityUrgent
+)
+
+func (p Priority) String() string {
+	switch p {
+	case PriorityNone:
+		return "none"
+	case PriorityLow:
+		return "low"
+	case PriorityMedium:
+		return "medium"
+	case PriorityHigh:
+		return "high"
+	case PriorityUrgent:
+		return "urgent"
+	default:
+		return "unknown"
+	}
+}
+
+// Raise returns the next priority up, stopping at urgent.
+func (p Priority) Raise() Priority {
+	return min(p+1, PriorityUrgent)
+}
+
+// Lower returns the next priority down, stopping at none.
+func (p Priority) Lower() Priority {
+	return max(p-1, PriorityNone)
+}
+
+// ParsePriority parses a priority name, its first letter, or its number from 0 (none)
+// to 4 (urgent).
+func ParsePriority(s string) (Priority, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if n, err := strconv.Atoi(s); err == nil {
+		if n < int(PriorityNone) || n > int(PriorityUrgent) {
+			return PriorityNone, fmt.Errorf("priority must be between %d and %d", PriorityNone, PriorityUrgent)
+		}
+		return Priority(n), nil
+	}
+
+	switch s {
+	case "none", "n":
+		return PriorityNone, nil
+	case "low", "l":
+		return PriorityLow, nil
+	case "medium", "med", "m":
+		return PriorityMedium, nil
+	case "high", "h":
+		return PriorityHigh, nil
+	case "urgent", "u":
+		return PriorityUrgent, nil
+	default:
+		return PriorityNone, fmt.Errorf("unknown priority %q", s)
+	}
+}
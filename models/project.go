@@ -2,22 +2,43 @@ package models
 
 import (
 	"context"
+	"errors"
 	"time"
 )
 
+// ErrProjectCycle is returned when a project would become its own ancestor.
+var ErrProjectCycle = errors.New("a project cannot be moved under itself or one of its sub-projects")
+
 type Project struct {
 	ID        int64
 	Title     string
 	ParentID  *int64
+	Position  float64 // orders the project among the others with the same parent
 	CreatedAt time.Time
 	UpdatedAt time.Time
+	DeletedAt *time.Time // set while the project is in the trash
 }
 
 type ProjectRepo interface {
 	ListAll(ctx context.Context) ([]*Project, error)
 	ListByParentID(ctx context.Context, parentID int64) ([]*Project, error)
+	// ListSiblings returns every project with the given parent, or the top-level ones if
+	// it's nil, in manual order.
+	ListSiblings(ctx context.Context, parentID *int64) ([]*Project, error)
+	ListDescendantIDs(ctx context.Context, id int64) ([]int64, error)
 	Get(ctx context.Context, id int64) (*Project, error)
 	Create(ctx context.Context, p *Project) (*Project, error)
 	Update(ctx context.Context, p *Project) (*Project, error)
 	Delete(ctx context.Context, id int64) error
+	SetPosition(ctx context.Context, id int64, position float64) error
+
+	// Trash moves a project, its sub-projects and all of their tasks to the trash.
+	Trash(ctx context.Context, id int64) error
+	// Restore brings back a trashed project along with everything trashed with it.
+	Restore(ctx context.Context, p *Project) error
+	// ListTrashed returns the projects that were trashed on their own, rather than along
+	// with their parent, newest first.
+	ListTrashed(ctx context.Context) ([]*Project, error)
+	// PurgeDeletedBefore permanently deletes projects trashed before the given time.
+	PurgeDeletedBefore(ctx context.Context, before time.Time) (int64, error)
 }
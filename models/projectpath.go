@@ -0,0 +1,123 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ProjectPathSep separates the titles in a project path, like "work/clients".
+const ProjectPathSep = "/"
+
+var (
+	// ErrProjectNotFound is returned when no project is at a path.
+	ErrProjectNotFound = errors.New("project not found")
+
+	// ErrEmptyProjectTitle is returned for a path with an empty title in it, like "work/"
+	// or "a//b".
+	ErrEmptyProjectTitle = errors.New("project path has an empty title")
+)
+
+// ResolveProjectPath finds the ID of the project at a path like "work/clients". An
+// empty path means no project, which is returned as nil.
+func (r *AllRepos) ResolveProjectPath(ctx context.Context, path string) (*int64, error) {
+	if strings.TrimSpace(path) == "" {
+		return nil, nil
+	}
+
+	for part := range strings.SplitSeq(path, ProjectPathSep) {
+		if strings.TrimSpace(part) == "" {
+			return nil, fmt.Errorf("%w: %q", ErrEmptyProjectTitle, path)
+		}
+	}
+
+	projects, err := r.Projects.ListAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing projects: %w", err)
+	}
+
+	var parentID *int64
+	for part := range strings.SplitSeq(path, ProjectPathSep) {
+		part = strings.TrimSpace(part)
+		var found *Project
+		for _, p := range projects {
+			if strings.EqualFold(p.Title, part) && SameID(p.ParentID, parentID) {
+				found = p
+				break
+			}
+		}
+
+		if found == nil {
+			return nil, fmt.Errorf("%w: %q", ErrProjectNotFound, path)
+		}
+		parentID = &found.ID
+	}
+
+	return parentID, nil
+}
+
+// EnsureProjectPath resolves a project path like ResolveProjectPath, but creates any
+// projects along the path that don't exist yet. It reports whether anything was created.
+func (r *AllRepos) EnsureProjectPath(ctx context.Context, path string) (*int64, bool, error) {
+	id, err := r.ResolveProjectPath(ctx, path)
+	if err == nil || !errors.Is(err, ErrProjectNotFound) {
+		return id, false, err
+	}
+
+	var (
+		parentID *int64
+		resolved []string
+	)
+	for part := range strings.SplitSeq(path, ProjectPathSep) {
+		part = strings.TrimSpace(part)
+		resolved = append(resolved, part)
+		id, err := r.ResolveProjectPath(ctx, strings.Join(resolved, ProjectPathSep))
+		if err == nil {
+			parentID = id
+			continue
+		}
+
+		if !errors.Is(err, ErrProjectNotFound) {
+			return nil, false, err
+		}
+
+		created, err := r.Projects.Create(ctx, &Project{Title: part, ParentID: parentID})
+		if err != nil {
+			return nil, false, fmt.Errorf("creating project %q: %w", part, err)
+		}
+		parentID = &created.ID
+	}
+
+	return parentID, true, nil
+}
+
+// ProjectPath builds the path of titles from the root project down to the project
+// with the provided ID.
+func ProjectPath(projects []*Project, id int64) string {
+	byID := make(map[int64]*Project, len(projects))
+	for _, p := range projects {
+		byID[p.ID] = p
+	}
+
+	var parts []string
+	for cur, ok := byID[id]; ok; {
+		parts = append([]string{cur.Title}, parts...)
+		// the length check guards against looping forever on a cycle in bad data
+		if cur.ParentID == nil || len(parts) > len(projects) {
+			break
+		}
+		cur, ok = byID[*cur.ParentID]
+	}
+
+	return strings.Join(parts, ProjectPathSep)
+}
+
+// SameID reports whether two optional IDs, like a parent or project ID, are the same, with
+// nil only the same as nil.
+func SameID(a, b *int64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -0,0 +1,85 @@
+package models_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dsrosen6/yata/models"
+	"github.com/dsrosen6/yata/sqlitedb"
+)
+
+func TestEnsureProjectPath(t *testing.T) {
+	ctx := context.Background()
+	repos := openRepos(t)
+
+	id, created, err := repos.EnsureProjectPath(ctx, "Work/Clients")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !created || id == nil {
+		t.Fatalf("EnsureProjectPath = %v, %v, want a new project", id, created)
+	}
+
+	again, created, err := repos.EnsureProjectPath(ctx, " work / clients ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created || !models.SameID(again, id) {
+		t.Errorf("EnsureProjectPath again = %v, %v, want %d and nothing created", again, created, *id)
+	}
+
+	if _, err := repos.ResolveProjectPath(ctx, "work/other"); !errors.Is(err, models.ErrProjectNotFound) {
+		t.Errorf("ResolveProjectPath(work/other) = %v, want %v", err, models.ErrProjectNotFound)
+	}
+}
+
+func TestEmptyProjectTitle(t *testing.T) {
+	ctx := context.Background()
+	repos := openRepos(t)
+
+	for _, path := range []string{"work/", "/work", "a//b", " /x", "a/ /b"} {
+		t.Run(path, func(t *testing.T) {
+			if _, _, err := repos.EnsureProjectPath(ctx, path); !errors.Is(err, models.ErrEmptyProjectTitle) {
+				t.Errorf("EnsureProjectPath(%q) = %v, want %v", path, err, models.ErrEmptyProjectTitle)
+			}
+			if _, err := repos.ResolveProjectPath(ctx, path); !errors.Is(err, models.ErrEmptyProjectTitle) {
+				t.Errorf("ResolveProjectPath(%q) = %v, want %v", path, err, models.ErrEmptyProjectTitle)
+			}
+		})
+	}
+
+	projects, err := repos.Projects.ListAll(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(projects) != 0 {
+		t.Errorf("%d projects were created, want none", len(projects))
+	}
+}
+
+func TestEmptyPathIsNoProject(t *testing.T) {
+	for _, path := range []string{"", "  "} {
+		id, created, err := openRepos(t).EnsureProjectPath(context.Background(), path)
+		if id != nil || created || err != nil {
+			t.Errorf("EnsureProjectPath(%q) = %v, %v, %v, want no project", path, id, created, err)
+		}
+	}
+}
+
+func openRepos(t *testing.T) *models.AllRepos {
+	t.Helper()
+	h, err := sqlitedb.NewHandler(os.DirFS("../migrations"), filepath.Join(t.TempDir(), "app.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = h.Close() })
+
+	repos, err := h.InitStores(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return repos
+}
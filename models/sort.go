@@ -1,6 +1,10 @@
 package models
 
-import "sort"
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
 
 type (
 	SortParams struct {
@@ -18,6 +22,9 @@ const (
 	SortByDueAt
 	SortByCreatedAt
 	SortByUpdatedAt
+	SortByPriority
+	// SortByManual keeps the order tasks were arranged in by hand.
+	SortByManual
 )
 
 const (
@@ -25,25 +32,87 @@ const (
 	SortOrderDesc
 )
 
+// SortBys lists every sort option in the order they're cycled through.
+var SortBys = []SortBy{SortByComplete, SortByPriority, SortByDueAt, SortByTitle, SortByCreatedAt, SortByUpdatedAt, SortByManual}
+
+func (s SortBy) String() string {
+	switch s {
+	case SortByTitle:
+		return "title"
+	case SortByComplete:
+		return "complete"
+	case SortByDueAt:
+		return "due date"
+	case SortByCreatedAt:
+		return "created"
+	case SortByUpdatedAt:
+		return "updated"
+	case SortByPriority:
+		return "priority"
+	case SortByManual:
+		return "manual"
+	default:
+		return "unknown"
+	}
+}
+
+// Name is a one-word name for the sort option, like due for "due date", for flags and
+// query parameters.
+func (s SortBy) Name() string {
+	return strings.Fields(s.String())[0]
+}
+
+// ParseSortBy parses a sort option from its name or its full description.
+func ParseSortBy(s string) (SortBy, error) {
+	names := make([]string, len(SortBys))
+	for i, sb := range SortBys {
+		if strings.EqualFold(s, sb.Name()) || strings.EqualFold(s, sb.String()) {
+			return sb, nil
+		}
+		names[i] = sb.Name()
+	}
+
+	return 0, fmt.Errorf("unknown sort %q, expected one of %s", s, strings.Join(names, ", "))
+}
+
+func (o SortOrder) String() string {
+	if o == SortOrderDesc {
+		return "descending"
+	}
+	return "ascending"
+}
+
 func SortTasks(tasks []*Task, params SortParams) {
 	sort.SliceStable(tasks, func(i, j int) bool {
-		var less bool
+		a, b := tasks[i], tasks[j]
+		if params.SortBy == SortByDueAt && (a.DueAt == nil || b.DueAt == nil) {
+			// tasks without a due date always sort last, regardless of order
+			return a.DueAt != nil && b.DueAt == nil
+		}
+
+		// swapping rather than negating keeps equal tasks in their original order
+		if params.SortOrder == SortOrderDesc {
+			a, b = b, a
+		}
+
 		switch params.SortBy {
 		case SortByTitle:
-			less = tasks[i].Title < tasks[j].Title
+			return a.Title < b.Title
 		case SortByComplete:
-			less = !tasks[i].Complete && tasks[j].Complete
+			return !a.Complete && b.Complete
 		case SortByDueAt:
-			less = tasks[i].DueAt.Before(*tasks[j].DueAt)
+			return a.DueAt.Before(*b.DueAt)
 		case SortByCreatedAt:
-			less = tasks[i].CreatedAt.Before(tasks[j].CreatedAt)
+			return a.CreatedAt.Before(b.CreatedAt)
 		case SortByUpdatedAt:
-			less = tasks[i].UpdatedAt.Before(tasks[j].UpdatedAt)
-		}
-
-		if params.SortOrder == SortOrderDesc {
-			return !less
+			return a.UpdatedAt.Before(b.UpdatedAt)
+		case SortByPriority:
+			// ascending puts the most urgent tasks first, since that's how they're triaged
+			return a.Priority > b.Priority
+		case SortByManual:
+			return a.Position < b.Position
+		default:
+			return false
 		}
-		return less
 	})
 }
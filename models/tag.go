@@ -0,0 +1,24 @@
+package models
+
+import (
+	"context"
+	"time"
+)
+
+type Tag struct {
+	ID        int64
+	Name      string
+	CreatedAt time.Time
+}
+
+type TagRepo interface {
+	ListAll(ctx context.Context) ([]*Tag, error)
+	ListByTaskID(ctx context.Context, taskID int64) ([]*Tag, error)
+	// ListAllByTaskID returns the tags of every task that has any, keyed by task ID.
+	ListAllByTaskID(ctx context.Context) (map[int64][]*Tag, error)
+	// AddToTask tags a task, creating the tag if it doesn't exist yet.
+	AddToTask(ctx context.Context, taskID int64, name string) error
+	// RemoveFromTask untags a task. Tags that are no longer used by any task are deleted.
+	RemoveFromTask(ctx context.Context, taskID int64, name string) error
+	Delete(ctx context.Context, id int64) error
+}
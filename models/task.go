@@ -2,9 +2,16 @@ package models
 
 import (
 	"context"
+	"errors"
 	"time"
+
+	"github.com/dsrosen6/yata/filter"
 )
 
+// ErrDependencyCycle is returned when a task would end up blocking itself, directly or
+// through other tasks.
+var ErrDependencyCycle = errors.New("a task cannot be blocked by itself or by a task it blocks")
+
 type Task struct {
 	ID           int64
 	Title        string
@@ -12,16 +19,76 @@ type Task struct {
 	ProjectID    *int64
 	Complete     bool
 	DueAt        *time.Time
+	Priority     Priority
+	Notes        string
+	Recurrence   string  // a recur rule in its stored form, empty if the task doesn't repeat
+	Position     float64 // orders the task among the others with the same parent and project
 	CreatedAt    time.Time
 	UpdatedAt    time.Time
+	CompletedAt  *time.Time // set while the task is complete
+	ArchivedAt   *time.Time // set once a completed task is archived, which hides it from the task lists
+	DeletedAt    *time.Time // set while the task is in the trash
+}
+
+// MatchStart and MatchEnd surround each matched term in a TaskMatch's title. They're
+// control characters, so they can't be confused with anything in a title.
+const (
+	MatchStart = "\x02"
+	MatchEnd   = "\x03"
+)
+
+// TaskMatch is a task found by a search, with the matched terms of its title marked.
+type TaskMatch struct {
+	Task  *Task
+	Title string
 }
 
 type TaskRepo interface {
 	ListAll(ctx context.Context) ([]*Task, error)
 	ListByProjectID(ctx context.Context, projectID int64) ([]*Task, error)
+	ListByProjectTree(ctx context.Context, projectID int64) ([]*Task, error)
 	ListByParentID(ctx context.Context, parentID int64) ([]*Task, error)
+	// ListSiblings returns every task with the given parent task and project, including
+	// completed and archived ones, in manual order.
+	ListSiblings(ctx context.Context, parentID, projectID *int64) ([]*Task, error)
 	Get(ctx context.Context, id int64) (*Task, error)
 	Create(ctx context.Context, t *Task) (*Task, error)
 	Update(ctx context.Context, t *Task) (*Task, error)
 	Delete(ctx context.Context, id int64) error
+	SetPosition(ctx context.Context, id int64, position float64) error
+
+	// Trash moves a task and its subtasks to the trash, stamping them with the same time
+	// so they can be restored together.
+	Trash(ctx context.Context, id int64) error
+	// Restore brings back a trashed task along with the subtasks trashed with it.
+	Restore(ctx context.Context, t *Task) error
+	// ListTrashed returns the tasks that were trashed on their own, rather than along with
+	// their parent task or project, newest first.
+	ListTrashed(ctx context.Context) ([]*Task, error)
+	// PurgeDeletedBefore permanently deletes tasks trashed before the given time.
+	PurgeDeletedBefore(ctx context.Context, before time.Time) (int64, error)
+
+	// ListCompleted returns every completed task, archived or not, most recently
+	// completed first.
+	ListCompleted(ctx context.Context) ([]*Task, error)
+	// Archive archives the tasks completed before the given time, stamping them with at
+	// so they can be unarchived together. Tasks with open subtasks are left alone.
+	Archive(ctx context.Context, completedBefore, at time.Time) (int64, error)
+	// Unarchive brings back the tasks archived at the given time.
+	Unarchive(ctx context.Context, at time.Time) (int64, error)
+
+	// Search finds tasks whose titles contain every word in the query, best match first.
+	// The last word can be the start of a longer one, so results narrow as it's typed.
+	Search(ctx context.Context, query string, limit int) ([]*TaskMatch, error)
+
+	// Query returns the tasks matching a filter, from every project.
+	Query(ctx context.Context, f *filter.Filter) ([]*Task, error)
+
+	// ListDependencies returns the IDs of the tasks blocking each task.
+	ListDependencies(ctx context.Context) (map[int64][]int64, error)
+	// ListBlockedIDs returns the IDs of tasks with at least one incomplete blocker.
+	ListBlockedIDs(ctx context.Context) ([]int64, error)
+	AddDependency(ctx context.Context, taskID, blockedByID int64) error
+	RemoveDependency(ctx context.Context, taskID, blockedByID int64) error
+	ClearDependencies(ctx context.Context, taskID int64) error
 }
@@ -0,0 +1,232 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/dsrosen6/yata/recur"
+)
+
+// Completion is what completing a task changed.
+type Completion struct {
+	// Task is the completed task.
+	Task *Task
+	// Next is the next occurrence of a repeating task, or nil if it doesn't repeat.
+	Next *Task
+	// Subtasks are the open subtasks completed along with it, as they were before.
+	Subtasks []*Task
+}
+
+// CompleteTask completes a task, in one transaction with everything that goes along with
+// it. A repeating task gets its next occurrence, with the same tags, and with cascade set
+// its open subtasks are completed too.
+func (r *AllRepos) CompleteTask(ctx context.Context, t *Task, cascade bool) (*Completion, error) {
+	var c *Completion
+	err := r.InTx(ctx, func(r *AllRepos) error {
+		var err error
+		c, err = r.completeTask(ctx, t, cascade)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+func (r *AllRepos) completeTask(ctx context.Context, t *Task, cascade bool) (*Completion, error) {
+	after := *t
+	after.Complete = true
+
+	var next *Task
+	if after.Recurrence != "" {
+		rule, err := recur.Parse(after.Recurrence)
+		if err != nil {
+			return nil, fmt.Errorf("parsing recurrence of task %d: %w", t.ID, err)
+		}
+
+		now := time.Now()
+		due := startOfDay(now)
+		if after.DueAt != nil {
+			due = after.DueAt.In(time.Local)
+		}
+
+		nextDue := rule.Next(due, now)
+		n := after
+		n.ID = 0
+		n.Complete = false
+		n.DueAt = &nextDue
+		next = &n
+
+		// the completed task is kept as history, so it mustn't repeat again if it's
+		// uncompleted and completed again
+		after.Recurrence = ""
+	}
+
+	updated, err := r.Tasks.Update(ctx, &after)
+	if err != nil {
+		return nil, fmt.Errorf("completing task %d: %w", t.ID, err)
+	}
+	c := &Completion{Task: updated}
+
+	if next != nil {
+		if c.Next, err = r.createOccurrence(ctx, t.ID, next); err != nil {
+			return nil, err
+		}
+	}
+
+	if cascade {
+		subtasks, err := r.ListSubtasks(ctx, t.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, s := range subtasks {
+			if s.Complete {
+				continue
+			}
+
+			done := *s
+			done.Complete = true
+			if _, err := r.Tasks.Update(ctx, &done); err != nil {
+				return nil, fmt.Errorf("completing subtask %d: %w", s.ID, err)
+			}
+			c.Subtasks = append(c.Subtasks, s)
+		}
+	}
+
+	return c, nil
+}
+
+// createOccurrence creates the next occurrence of a repeating task, with the same tags.
+func (r *AllRepos) createOccurrence(ctx context.Context, fromID int64, next *Task) (*Task, error) {
+	created, err := r.Tasks.Create(ctx, next)
+	if err != nil {
+		return nil, fmt.Errorf("creating next occurrence of task %d: %w", fromID, err)
+	}
+
+	tags, err := r.Tags.ListByTaskID(ctx, fromID)
+	if err != nil {
+		return nil, fmt.Errorf("listing tags of task %d: %w", fromID, err)
+	}
+
+	for _, tag := range tags {
+		if err := r.Tags.AddToTask(ctx, created.ID, tag.Name); err != nil {
+			return nil, fmt.Errorf("adding tag %q: %w", tag.Name, err)
+		}
+	}
+
+	return created, nil
+}
+
+// MoveTask moves a task and its subtasks to another project, in one transaction. Subtasks
+// live in their parent's project, so a subtask being moved is taken out of its parent. It
+// returns the moved task and the subtasks that went with it, as they were before.
+func (r *AllRepos) MoveTask(ctx context.Context, t *Task, projectID *int64) (*Task, []*Task, error) {
+	var (
+		moved    *Task
+		subtasks []*Task
+	)
+	err := r.InTx(ctx, func(r *AllRepos) error {
+		var err error
+		if subtasks, err = r.ListSubtasks(ctx, t.ID); err != nil {
+			return err
+		}
+
+		after := *t
+		after.ProjectID = projectID
+		after.ParentTaskID = nil
+		if moved, err = r.Tasks.Update(ctx, &after); err != nil {
+			return fmt.Errorf("moving task %d: %w", t.ID, err)
+		}
+
+		for _, s := range subtasks {
+			m := *s
+			m.ProjectID = projectID
+			if _, err := r.Tasks.Update(ctx, &m); err != nil {
+				return fmt.Errorf("moving subtask %d: %w", s.ID, err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return moved, subtasks, nil
+}
+
+// ListSubtasks returns every task below the parent, at any depth.
+func (r *AllRepos) ListSubtasks(ctx context.Context, parentID int64) ([]*Task, error) {
+	children, err := r.Tasks.ListByParentID(ctx, parentID)
+	if err != nil {
+		return nil, fmt.Errorf("listing subtasks of %d: %w", parentID, err)
+	}
+
+	var all []*Task
+	for _, c := range children {
+		below, err := r.ListSubtasks(ctx, c.ID)
+		if err != nil {
+			return nil, err
+		}
+		all = append(append(all, c), below...)
+	}
+
+	return all, nil
+}
+
+// SetTaskTags replaces a task's tags.
+func (r *AllRepos) SetTaskTags(ctx context.Context, taskID int64, tags []string) error {
+	current, err := r.Tags.ListByTaskID(ctx, taskID)
+	if err != nil {
+		return fmt.Errorf("listing tags of task %d: %w", taskID, err)
+	}
+
+	for _, t := range current {
+		if !slices.ContainsFunc(tags, func(s string) bool { return strings.EqualFold(s, t.Name) }) {
+			if err := r.Tags.RemoveFromTask(ctx, taskID, t.Name); err != nil {
+				return fmt.Errorf("removing tag %q: %w", t.Name, err)
+			}
+		}
+	}
+
+	for _, t := range tags {
+		if err := r.Tags.AddToTask(ctx, taskID, t); err != nil {
+			return fmt.Errorf("adding tag %q: %w", t, err)
+		}
+	}
+
+	return nil
+}
+
+// SetRecurrence sets the task's repeat rule from a phrase like "every 2 weeks", or clears
+// it if the phrase is empty. A repeating task without a due date gets its first
+// occurrence from today as the due date.
+func (t *Task) SetRecurrence(s string) error {
+	if strings.TrimSpace(s) == "" {
+		t.Recurrence = ""
+		return nil
+	}
+
+	rule, err := recur.Parse(s)
+	if err != nil {
+		return err
+	}
+
+	if t.DueAt == nil {
+		first := rule.First(startOfDay(time.Now()))
+		t.DueAt = &first
+	}
+
+	t.Recurrence = rule.Anchor(t.DueAt.In(time.Local)).String()
+	return nil
+}
+
+func startOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
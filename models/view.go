@@ -0,0 +1,25 @@
+package models
+
+import (
+	"context"
+	"time"
+)
+
+// View is a saved filter, listed alongside the projects. Filter is in the syntax of the
+// filter package.
+type View struct {
+	ID        int64
+	Name      string
+	Filter    string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type ViewRepo interface {
+	// ListAll returns the saved views, ordered by name.
+	ListAll(ctx context.Context) ([]*View, error)
+	// Create saves a view. Its filter has to parse.
+	Create(ctx context.Context, v *View) (*View, error)
+	Update(ctx context.Context, v *View) (*View, error)
+	Delete(ctx context.Context, id int64) error
+}
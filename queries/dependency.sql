@@ -0,0 +1,36 @@
+-- name: AddTaskDependency :exec
+INSERT OR IGNORE INTO task_dependency (
+    task_id,
+    blocked_by_id
+) VALUES (
+    ?, ?
+);
+
+-- name: RemoveTaskDependency :exec
+DELETE FROM task_dependency
+WHERE task_id = ? AND blocked_by_id = ?;
+
+-- name: RemoveTaskDependenciesByTaskID :exec
+DELETE FROM task_dependency
+WHERE task_id = ?;
+
+-- name: ListAllTaskDependencies :many
+SELECT task_dependency.* FROM task_dependency
+JOIN task ON task.id = task_dependency.blocked_by_id
+WHERE task.deleted_at IS NULL
+ORDER BY task_dependency.task_id, task_dependency.blocked_by_id;
+
+-- name: ListBlockedTaskIDs :many
+SELECT DISTINCT task_dependency.task_id FROM task_dependency
+JOIN task ON task.id = task_dependency.blocked_by_id
+WHERE task.complete = 0 AND task.deleted_at IS NULL;
+
+-- name: ListTransitiveBlockerIDs :many
+WITH RECURSIVE blockers(id) AS (
+    SELECT task_dependency.blocked_by_id FROM task_dependency
+    WHERE task_dependency.task_id = ?
+    UNION
+    SELECT td.blocked_by_id FROM task_dependency td
+    JOIN blockers b ON td.task_id = b.id
+)
+SELECT id FROM blockers;
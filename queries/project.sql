@@ -1,9 +1,17 @@
 -- name: ListAllProjects :many
-SELECT * FROM project;
+SELECT * FROM project
+WHERE deleted_at IS NULL
+ORDER BY position, id;
+
+-- name: ListProjectSiblings :many
+SELECT * FROM project
+WHERE parent_project_id IS ? AND deleted_at IS NULL
+ORDER BY position, id;
 
 -- name: ListProjectsByParentProjectID :many
 SELECT * FROM project
-WHERE parent_project_id = ?;
+WHERE parent_project_id = ? AND deleted_at IS NULL
+ORDER BY position, id;
 
 -- name: GetProject :one
 SELECT * FROM project
@@ -12,9 +20,10 @@ WHERE id = ? LIMIT 1;
 -- name: CreateProject :one
 INSERT INTO project (
     title,
-    parent_project_id
+    parent_project_id,
+    position
 ) VALUES (
-    ?, ?
+    ?1, ?2, (SELECT COALESCE(MAX(position), 0) + 1 FROM project WHERE parent_project_id IS ?2)
 ) RETURNING *;
 
 -- name: UpdateProject :one
@@ -22,10 +31,102 @@ UPDATE project
 SET
     title = ?,
     parent_project_id = ?,
+    position = ?,
     updated_at = CURRENT_TIMESTAMP
 WHERE id = ?
 RETURNING *;
 
+-- name: NextProjectPosition :one
+SELECT CAST(COALESCE(MAX(position), 0) + 1 AS REAL) AS position FROM project
+WHERE parent_project_id IS ?;
+
+-- name: SetProjectPosition :exec
+UPDATE project
+SET position = ?
+WHERE id = ?;
+
 -- name: DeleteProject :exec
 DELETE FROM project
 WHERE id = ?;
+
+-- name: ListProjectDescendantIDs :many
+WITH RECURSIVE descendants(id) AS (
+    SELECT project.id FROM project
+    WHERE project.id = ?
+    UNION
+    SELECT p.id FROM project p
+    JOIN descendants d ON p.parent_project_id = d.id
+)
+SELECT id FROM descendants;
+
+-- name: ListTasksInProjectTree :many
+WITH RECURSIVE descendants(id) AS (
+    SELECT project.id FROM project
+    WHERE project.id = ?
+    UNION
+    SELECT p.id FROM project p
+    JOIN descendants d ON p.parent_project_id = d.id
+)
+SELECT task.* FROM task
+WHERE task.project_id IN (SELECT id FROM descendants) AND task.deleted_at IS NULL AND task.archived_at IS NULL;
+
+-- name: ListTrashedProjects :many
+SELECT project.* FROM project
+WHERE project.deleted_at IS NOT NULL
+    AND NOT EXISTS (
+        SELECT 1 FROM project parent
+        WHERE parent.id = project.parent_project_id AND parent.deleted_at = project.deleted_at
+    )
+ORDER BY project.deleted_at DESC;
+
+-- name: PurgeProjectsDeletedBefore :execrows
+DELETE FROM project
+WHERE deleted_at < ?;
+
+-- name: RestoreProjectTree :execrows
+WITH RECURSIVE descendants(id) AS (
+    SELECT project.id FROM project
+    WHERE project.id = ?
+    UNION
+    SELECT p.id FROM project p
+    JOIN descendants d ON p.parent_project_id = d.id
+)
+UPDATE project
+SET deleted_at = NULL
+WHERE id IN (SELECT id FROM descendants) AND deleted_at = ?;
+
+-- name: RestoreTasksInProjectTree :execrows
+WITH RECURSIVE descendants(id) AS (
+    SELECT project.id FROM project
+    WHERE project.id = ?
+    UNION
+    SELECT p.id FROM project p
+    JOIN descendants d ON p.parent_project_id = d.id
+)
+UPDATE task
+SET deleted_at = NULL
+WHERE project_id IN (SELECT id FROM descendants) AND deleted_at = ?;
+
+-- name: TrashProjectTree :execrows
+WITH RECURSIVE descendants(id) AS (
+    SELECT project.id FROM project
+    WHERE project.id = ?
+    UNION
+    SELECT p.id FROM project p
+    JOIN descendants d ON p.parent_project_id = d.id
+)
+UPDATE project
+SET deleted_at = ?
+WHERE id IN (SELECT id FROM descendants) AND deleted_at IS NULL;
+
+-- name: TrashTasksInProjectTree :execrows
+WITH RECURSIVE descendants(id) AS (
+    SELECT project.id FROM project
+    WHERE project.id = ?
+    UNION
+    SELECT p.id FROM project p
+    JOIN descendants d ON p.parent_project_id = d.id
+)
+UPDATE task
+SET deleted_at = ?
+WHERE project_id IN (SELECT id FROM descendants) AND deleted_at IS NULL;
@@ -0,0 +1,47 @@
+-- name: ListAllTags :many
+SELECT * FROM tag
+ORDER BY name;
+
+-- name: ListTagsByTaskID :many
+SELECT tag.* FROM tag
+JOIN task_tag ON task_tag.tag_id = tag.id
+WHERE task_tag.task_id = ?
+ORDER BY tag.name;
+
+-- name: ListAllTaskTags :many
+SELECT task_tag.task_id, tag.id, tag.name, tag.created_at FROM task_tag
+JOIN tag ON tag.id = task_tag.tag_id
+ORDER BY tag.name;
+
+-- name: GetTagByName :one
+SELECT * FROM tag
+WHERE name = ? LIMIT 1;
+
+-- name: CreateTag :one
+INSERT INTO tag (
+    name
+) VALUES (
+    ?
+)
+ON CONFLICT (name) DO UPDATE SET name = tag.name
+RETURNING *;
+
+-- name: DeleteTag :exec
+DELETE FROM tag
+WHERE id = ?;
+
+-- name: DeleteUnusedTags :exec
+DELETE FROM tag
+WHERE id NOT IN (SELECT tag_id FROM task_tag);
+
+-- name: AddTagToTask :exec
+INSERT OR IGNORE INTO task_tag (
+    task_id,
+    tag_id
+) VALUES (
+    ?, ?
+);
+
+-- name: RemoveTagFromTask :exec
+DELETE FROM task_tag
+WHERE task_id = ? AND tag_id = ?;
@@ -1,13 +1,14 @@
 -- name: ListAllTasks :many
-SELECT * FROM task;
+SELECT * FROM task
+WHERE deleted_at IS NULL AND archived_at IS NULL;
 
 -- name: ListTasksByProjectID :many
 SELECT * FROM task
-WHERE project_id = ?;
+WHERE project_id = ? AND deleted_at IS NULL AND archived_at IS NULL;
 
 -- name: ListTasksByParentTaskID :many
 SELECT * FROM task
-WHERE parent_task_id = ?;
+WHERE parent_task_id = ? AND deleted_at IS NULL;
 
 -- name: GetTask :one
 SELECT * FROM task
@@ -19,9 +20,15 @@ INSERT INTO task (
     parent_task_id,
     project_id,
     complete,
-    due_at
+    due_at,
+    priority,
+    notes,
+    recurrence,
+    completed_at,
+    position
 ) VALUES (
-    ?, ?, ?, ?, ?
+    ?1, ?2, ?3, ?4, ?5, ?6, ?7, ?8, ?9,
+    (SELECT COALESCE(MAX(position), 0) + 1 FROM task WHERE parent_task_id IS ?2 AND project_id IS ?3)
 ) RETURNING *;
 
 -- name: UpdateTask :one
@@ -32,10 +39,101 @@ SET
     project_id = ?,
     complete = ?,
     due_at = ?,
+    priority = ?,
+    notes = ?,
+    recurrence = ?,
+    position = ?,
+    completed_at = ?,
+    archived_at = ?,
     updated_at = CURRENT_TIMESTAMP
 WHERE id = ?
 RETURNING *;
 
+-- name: NextTaskPosition :one
+SELECT CAST(COALESCE(MAX(position), 0) + 1 AS REAL) AS position FROM task
+WHERE parent_task_id IS ? AND project_id IS ?;
+
+-- name: ListTaskSiblings :many
+SELECT * FROM task
+WHERE parent_task_id IS ? AND project_id IS ? AND deleted_at IS NULL
+ORDER BY position, id;
+
+-- name: SetTaskPosition :exec
+UPDATE task
+SET position = ?
+WHERE id = ?;
+
 -- name: DeleteTask :exec
 DELETE FROM task
 WHERE id = ?;
+
+-- name: ListTrashedTasks :many
+SELECT task.* FROM task
+WHERE task.deleted_at IS NOT NULL
+    AND NOT EXISTS (
+        SELECT 1 FROM project
+        WHERE project.id = task.project_id AND project.deleted_at = task.deleted_at
+    )
+    AND NOT EXISTS (
+        SELECT 1 FROM task parent
+        WHERE parent.id = task.parent_task_id AND parent.deleted_at = task.deleted_at
+    )
+ORDER BY task.deleted_at DESC;
+
+-- name: PurgeTasksDeletedBefore :execrows
+DELETE FROM task
+WHERE deleted_at < ?;
+
+-- name: RestoreTaskTree :execrows
+WITH RECURSIVE subtasks(id) AS (
+    SELECT task.id FROM task
+    WHERE task.id = ?
+    UNION
+    SELECT t.id FROM task t
+    JOIN subtasks s ON t.parent_task_id = s.id
+)
+UPDATE task
+SET deleted_at = NULL
+WHERE id IN (SELECT id FROM subtasks) AND deleted_at = ?;
+
+-- name: TrashTaskTree :execrows
+WITH RECURSIVE subtasks(id) AS (
+    SELECT task.id FROM task
+    WHERE task.id = ?
+    UNION
+    SELECT t.id FROM task t
+    JOIN subtasks s ON t.parent_task_id = s.id
+)
+UPDATE task
+SET deleted_at = ?
+WHERE id IN (SELECT id FROM subtasks) AND deleted_at IS NULL;
+
+-- name: SearchTasks :many
+SELECT
+    task.*,
+    CAST(highlight(task_fts, 0, CAST(sqlc.arg(mark_start) AS TEXT), CAST(sqlc.arg(mark_end) AS TEXT)) AS TEXT) AS highlighted_title
+FROM task_fts
+JOIN task ON task.id = task_fts.rowid
+WHERE task_fts MATCH CAST(sqlc.arg(query) AS TEXT) AND task.deleted_at IS NULL AND task.archived_at IS NULL
+ORDER BY bm25(task_fts), task.id
+LIMIT sqlc.arg(max_results);
+
+-- name: ListCompletedTasks :many
+SELECT * FROM task
+WHERE complete = 1 AND deleted_at IS NULL
+ORDER BY completed_at DESC, id DESC;
+
+-- name: ArchiveTasksCompletedBefore :execrows
+UPDATE task
+SET archived_at = sqlc.arg(archived_at)
+WHERE complete = 1 AND completed_at < sqlc.arg(completed_before)
+    AND archived_at IS NULL AND deleted_at IS NULL
+    AND NOT EXISTS (
+        SELECT 1 FROM task subtask
+        WHERE subtask.parent_task_id = task.id AND subtask.complete = 0 AND subtask.deleted_at IS NULL
+    );
+
+-- name: UnarchiveTasks :execrows
+UPDATE task
+SET archived_at = NULL
+WHERE archived_at = ?;
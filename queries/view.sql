@@ -0,0 +1,24 @@
+-- name: ListSavedViews :many
+SELECT * FROM saved_view
+ORDER BY name;
+
+-- name: CreateSavedView :one
+INSERT INTO saved_view (
+    name,
+    filter
+) VALUES (
+    ?, ?
+) RETURNING *;
+
+-- name: UpdateSavedView :one
+UPDATE saved_view
+SET
+    name = ?,
+    filter = ?,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = ?
+RETURNING *;
+
+-- name: DeleteSavedView :exec
+DELETE FROM saved_view
+WHERE id = ?;
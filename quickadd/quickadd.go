@@ -0,0 +1,133 @@
+// Package quickadd parses a single line of task entry with inline metadata, like
+// "Buy milk #errands @tomorrow !high +shopping".
+package quickadd
+
+import (
+	"strings"
+
+	"github.com/dsrosen6/yata/dates"
+	"github.com/dsrosen6/yata/models"
+)
+
+const (
+	projectSigil  = '#'
+	dueSigil      = '@'
+	prioritySigil = '!'
+	tagSigil      = '+'
+	escapeChar    = '\\'
+
+	// maxDueWords is how many words after an @ are tried as a date phrase, so that
+	// "@next fri 5pm" works without quoting.
+	maxDueWords = 4
+)
+
+// Result is a parsed entry line. Task has the title and due date filled in. The project,
+// priority and tags are returned as written since resolving them depends on the caller.
+type Result struct {
+	Task     *models.Task
+	Project  string
+	Priority string
+	Tags     []string
+
+	// Unresolved holds metadata tokens that couldn't be used, like an unparseable date
+	// or a second project. They're left out of the title.
+	Unresolved []string
+}
+
+// Parse splits an entry line into a task and its metadata:
+//
+//	#project   project title, or a path like #work/clients
+//	@date      due date, using any phrase the dates parser understands, like @tomorrow or @next fri 5pm
+//	!priority  priority, like !high
+//	+tag       tag, may be repeated
+//
+// Words that don't start with a sigil make up the title. A sigil on its own is kept in the
+// title, and a leading backslash keeps a word literal, so \#1 becomes #1 in the title.
+func Parse(s string, dp *dates.Parser) *Result {
+	res := &Result{Task: &models.Task{}}
+	words := strings.Fields(s)
+
+	var title []string
+	for i := 0; i < len(words); i++ {
+		w := words[i]
+		if len(w) < 2 {
+			title = append(title, w)
+			continue
+		}
+
+		switch w[0] {
+		case escapeChar:
+			title = append(title, w[1:])
+		case projectSigil:
+			if res.Project != "" {
+				res.Unresolved = append(res.Unresolved, w)
+				continue
+			}
+			res.Project = w[1:]
+		case prioritySigil:
+			if res.Priority != "" {
+				res.Unresolved = append(res.Unresolved, w)
+				continue
+			}
+			res.Priority = strings.ToLower(w[1:])
+		case tagSigil:
+			res.Tags = append(res.Tags, w[1:])
+		case dueSigil:
+			n := res.parseDue(words[i:], dp)
+			if n == 0 {
+				res.Unresolved = append(res.Unresolved, w)
+				continue
+			}
+			i += n - 1
+		default:
+			title = append(title, w)
+		}
+	}
+
+	res.Task.Title = strings.Join(title, " ")
+	return res
+}
+
+// parseDue tries the longest phrase starting at words[0] (which has the @ sigil) that parses
+// as a date, and returns the number of words it used. It returns 0 if nothing parses or a
+// due date was already set.
+func (r *Result) parseDue(words []string, dp *dates.Parser) int {
+	if r.Task.DueAt != nil {
+		return 0
+	}
+
+	// a following metadata word is never part of the phrase
+	limit := min(maxDueWords, len(words))
+	for j := 1; j < limit; j++ {
+		if isMeta(words[j]) {
+			limit = j
+			break
+		}
+	}
+
+	for n := limit; n >= 1; n-- {
+		phrase := strings.Join(words[:n], " ")[1:]
+		t, err := dp.Parse(phrase)
+		if err != nil {
+			continue
+		}
+
+		r.Task.DueAt = &t
+		return n
+	}
+
+	return 0
+}
+
+func isMeta(w string) bool {
+	if len(w) < 2 {
+		return false
+	}
+
+	switch w[0] {
+	case projectSigil, dueSigil, prioritySigil, tagSigil, escapeChar:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,140 @@
+package quickadd
+
+import (
+	"slices"
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/dsrosen6/yata/dates"
+)
+
+// parserAt returns a parser whose clock is stopped at the given wall clock time in New York.
+func parserAt(t testing.TB, y int, m time.Month, d, h, min int) *dates.Parser {
+	t.Helper()
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(y, m, d, h, min, 0, 0, loc)
+	return &dates.Parser{Now: func() time.Time { return now }, Loc: loc}
+}
+
+func TestParse(t *testing.T) {
+	// Wednesday
+	dp := parserAt(t, 2026, 3, 4, 10, 30)
+	at := func(m time.Month, d, h, min int) *time.Time {
+		t := time.Date(2026, m, d, h, min, 0, 0, dp.Loc)
+		return &t
+	}
+
+	tests := []struct {
+		name       string
+		in         string
+		title      string
+		project    string
+		priority   string
+		tags       []string
+		due        *time.Time
+		unresolved []string
+	}{
+		{name: "title only", in: "Buy milk", title: "Buy milk"},
+		{name: "extra spaces", in: "  Buy   milk  ", title: "Buy milk"},
+		{
+			name:     "every sigil",
+			in:       "Buy milk #errands @tomorrow !HIGH +shopping +food",
+			title:    "Buy milk",
+			project:  "errands",
+			priority: "high",
+			tags:     []string{"shopping", "food"},
+			due:      at(3, 5, 0, 0),
+		},
+		{name: "metadata between words", in: "Call +phone Sam #work back", title: "Call Sam back", project: "work", tags: []string{"phone"}},
+		{name: "project path", in: "Invoice #work/clients", title: "Invoice", project: "work/clients"},
+		{name: "metadata only", in: "#work !low", project: "work", priority: "low"},
+
+		{name: "multi-word due", in: "Call Sam @next fri 5pm", title: "Call Sam", due: at(3, 6, 17, 0)},
+		{name: "due before title words", in: "@fri lunch with Sam", title: "lunch with Sam", due: at(3, 6, 0, 0)},
+		{name: "due stops at metadata", in: "Call @in 3 days #work", title: "Call", project: "work", due: at(3, 7, 0, 0)},
+		{name: "due uses the longest phrase that parses", in: "Plan @in 3 days at 5pm", title: "Plan at 5pm", due: at(3, 7, 0, 0)},
+		{name: "four-word due", in: "Plan @next fri at 5pm sharp", title: "Plan sharp", due: at(3, 6, 17, 0)},
+		{name: "bad due", in: "Call @someday Sam", title: "Call Sam", unresolved: []string{"@someday"}},
+		{name: "bad due before metadata", in: "Call @next +phone", title: "Call", tags: []string{"phone"}, unresolved: []string{"@next"}},
+		{name: "second due", in: "Call @today @tomorrow", title: "Call", due: at(3, 4, 0, 0), unresolved: []string{"@tomorrow"}},
+
+		{name: "second project", in: "Call #work #home", title: "Call", project: "work", unresolved: []string{"#home"}},
+		{name: "second priority", in: "Call !low !high", title: "Call", priority: "low", unresolved: []string{"!high"}},
+		{name: "repeated tags", in: "Call +a +b +a", title: "Call", tags: []string{"a", "b", "a"}},
+
+		{name: "lone sigils", in: "a # b @ c ! d + e \\", title: "a # b @ c ! d + e \\"},
+		{name: "escaped sigils", in: `Fix \#1 \@home \!important \+1`, title: "Fix #1 @home !important +1"},
+		{name: "escaped backslash", in: `path \\x`, title: `path \x`},
+		{name: "escaped word", in: `\plain words`, title: "plain words"},
+		{name: "sigil inside a word", in: "C# and a@b.com", title: "C# and a@b.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Parse(tt.in, dp)
+			if got.Task.Title != tt.title {
+				t.Errorf("title = %q, want %q", got.Task.Title, tt.title)
+			}
+			if got.Project != tt.project {
+				t.Errorf("project = %q, want %q", got.Project, tt.project)
+			}
+			if got.Priority != tt.priority {
+				t.Errorf("priority = %q, want %q", got.Priority, tt.priority)
+			}
+			if !slices.Equal(got.Tags, tt.tags) {
+				t.Errorf("tags = %q, want %q", got.Tags, tt.tags)
+			}
+			if !slices.Equal(got.Unresolved, tt.unresolved) {
+				t.Errorf("unresolved = %q, want %q", got.Unresolved, tt.unresolved)
+			}
+			switch {
+			case got.Task.DueAt == nil && tt.due == nil:
+			case got.Task.DueAt == nil || tt.due == nil || !got.Task.DueAt.Equal(*tt.due):
+				t.Errorf("due = %v, want %v", got.Task.DueAt, tt.due)
+			}
+		})
+	}
+}
+
+func TestParseDue(t *testing.T) {
+	dp := parserAt(t, 2026, 3, 4, 10, 30)
+
+	tests := []struct {
+		name  string
+		words []string
+		want  int
+	}{
+		{"one word", []string{"@tomorrow", "call"}, 1},
+		{"longest phrase wins", []string{"@next", "fri", "5pm", "call"}, 3},
+		{"stops at metadata", []string{"@in", "3", "#days"}, 0},
+		{"metadata ends the phrase", []string{"@fri", "+5pm"}, 1},
+		{"nothing parses", []string{"@whenever", "soon"}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Result{Task: Parse("", dp).Task}
+			if got := r.parseDue(tt.words, dp); got != tt.want {
+				t.Errorf("parseDue(%q) = %d, want %d", tt.words, got, tt.want)
+			}
+			if (r.Task.DueAt != nil) != (tt.want > 0) {
+				t.Errorf("due = %v after using %d words", r.Task.DueAt, tt.want)
+			}
+		})
+	}
+
+	t.Run("due already set", func(t *testing.T) {
+		r := Parse("@today", dp)
+		before := *r.Task.DueAt
+		if got := r.parseDue([]string{"@tomorrow"}, dp); got != 0 {
+			t.Errorf("parseDue = %d, want 0", got)
+		}
+		if !r.Task.DueAt.Equal(before) {
+			t.Errorf("due changed to %v, want %v", r.Task.DueAt, before)
+		}
+	})
+}
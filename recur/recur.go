@@ -0,0 +1,615 @@
+// Package recur parses recurrence rules for repeating tasks, like "every weekday" or
+// "every 2 weeks on mon, thu", and works out when the next occurrence is due.
+//
+// Rules are stored in an RRULE-style form (see Rule.String) and can be parsed back from
+// either that or a phrase.
+package recur
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidRule is returned when a rule can't be parsed or doesn't make sense, like
+// weekdays on a daily rule.
+var ErrInvalidRule = errors.New("invalid recurrence rule")
+
+type Freq int
+
+const (
+	Daily Freq = iota + 1
+	Weekly
+	Monthly
+	Yearly
+)
+
+// LastDay is the MonthDay for the last day of the month.
+const LastDay = -1
+
+// maxInterval bounds a rule's interval, which is plenty for anything real and keeps the
+// date arithmetic far from overflowing.
+const maxInterval = 1000
+
+// maxSteps bounds how many occurrences Next will skip over when catching up on a task
+// that's long overdue.
+const maxSteps = 10000
+
+// Rule is a parsed recurrence rule.
+type Rule struct {
+	Freq Freq
+
+	// Interval is how many days, weeks, months or years there are between occurrences.
+	Interval int
+
+	// Weekdays limits a weekly rule to these days of the week. Empty means the same
+	// weekday as the previous occurrence.
+	Weekdays []time.Weekday
+
+	// MonthDay is the day of the month for a monthly rule, or LastDay. 0 means the same
+	// day as the previous occurrence, clamped to the length of the month.
+	MonthDay int
+
+	// LastBusinessDay makes a monthly rule fall on the last Monday to Friday of the month.
+	LastBusinessDay bool
+
+	// FromCompletion counts the interval from when the task was completed rather than
+	// from when it was due, like "3 days after completion".
+	FromCompletion bool
+}
+
+var (
+	afterCompletionRe = regexp.MustCompile(`^(\d+|a|an|one)\s+(day|week|month|year)s?\s+after\s+(?:completion|completed|done)$`)
+	everyRe           = regexp.MustCompile(`^every\s+(?:(\d+|other)\s+)?(day|week|month|year)s?$`)
+	monthDayRe        = regexp.MustCompile(`^(?:the\s+|day\s+)?(\d{1,2})(?:st|nd|rd|th)?$`)
+	listSepRe         = regexp.MustCompile(`\s*(?:,|/|&|\band\b)\s*|\s+`)
+)
+
+var weekdayNames = map[string]time.Weekday{
+	"sun": time.Sunday, "sunday": time.Sunday, "su": time.Sunday,
+	"mon": time.Monday, "monday": time.Monday, "mo": time.Monday,
+	"tue": time.Tuesday, "tues": time.Tuesday, "tuesday": time.Tuesday, "tu": time.Tuesday,
+	"wed": time.Wednesday, "wednesday": time.Wednesday, "we": time.Wednesday,
+	"thu": time.Thursday, "thur": time.Thursday, "thurs": time.Thursday, "thursday": time.Thursday, "th": time.Thursday,
+	"fri": time.Friday, "friday": time.Friday, "fr": time.Friday,
+	"sat": time.Saturday, "saturday": time.Saturday, "sa": time.Saturday,
+}
+
+var (
+	rruleDays    = []string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+	businessDays = []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+	units        = map[string]Freq{"day": Daily, "week": Weekly, "month": Monthly, "year": Yearly}
+)
+
+// Parse reads a rule from a phrase or its RRULE-style form. Supported phrases are:
+//
+//	daily, weekly, monthly, yearly (or annually)
+//	every day, every 3 days, every other week, every 2 months, every year
+//	every weekday
+//	every mon, every mon and thu
+//	every 2 weeks on mon, thu
+//	monthly on the 15th, every month on the last day
+//	every month on the last business day (or just "last business day")
+//	3 days after completion, 1 week after done
+func Parse(s string) (*Rule, error) {
+	s = strings.Join(strings.Fields(strings.ToLower(s)), " ")
+	if s == "" {
+		return nil, fmt.Errorf("%w: empty rule", ErrInvalidRule)
+	}
+
+	var (
+		r   *Rule
+		err error
+	)
+	if strings.Contains(s, "freq=") {
+		r, err = parseRRule(s)
+	} else {
+		r, err = parsePhrase(s)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.validate(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func parsePhrase(s string) (*Rule, error) {
+	if m := afterCompletionRe.FindStringSubmatch(s); m != nil {
+		n, err := parseCount(m[1])
+		if err != nil {
+			return nil, err
+		}
+		return &Rule{Freq: units[m[2]], Interval: n, FromCompletion: true}, nil
+	}
+
+	head, tail, hasTail := strings.Cut(s, " on ")
+	r := &Rule{Interval: 1}
+	switch head {
+	case "daily":
+		r.Freq = Daily
+	case "weekly":
+		r.Freq = Weekly
+	case "monthly":
+		r.Freq = Monthly
+	case "yearly", "annually":
+		r.Freq = Yearly
+	case "every weekday", "weekdays":
+		r.Freq = Weekly
+		r.Weekdays = slices.Clone(businessDays)
+	case "last business day", "the last business day":
+		r.Freq = Monthly
+		r.LastBusinessDay = true
+	case "last day", "the last day":
+		r.Freq = Monthly
+		r.MonthDay = LastDay
+	default:
+		if m := everyRe.FindStringSubmatch(head); m != nil {
+			n := 1
+			if m[1] != "" {
+				var err error
+				if n, err = parseCount(m[1]); err != nil {
+					return nil, err
+				}
+			}
+			r.Freq = units[m[2]]
+			r.Interval = n
+			break
+		}
+
+		// "every mon and thu"
+		days, ok := strings.CutPrefix(head, "every ")
+		if !ok {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidRule, s)
+		}
+		wds, err := parseWeekdays(days)
+		if err != nil {
+			return nil, err
+		}
+		r.Freq = Weekly
+		r.Weekdays = wds
+	}
+
+	if !hasTail {
+		return r, nil
+	}
+
+	switch {
+	case r.Freq == Weekly && len(r.Weekdays) == 0:
+		wds, err := parseWeekdays(tail)
+		if err != nil {
+			return nil, err
+		}
+		r.Weekdays = wds
+	case r.Freq == Monthly && r.MonthDay == 0 && !r.LastBusinessDay:
+		if err := r.parseMonthDay(tail); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("%w: unexpected %q", ErrInvalidRule, "on "+tail)
+	}
+
+	return r, nil
+}
+
+func (r *Rule) parseMonthDay(s string) error {
+	switch strings.TrimPrefix(s, "the ") {
+	case "last day":
+		r.MonthDay = LastDay
+		return nil
+	case "last business day", "last weekday":
+		r.LastBusinessDay = true
+		return nil
+	}
+
+	m := monthDayRe.FindStringSubmatch(s)
+	if m == nil {
+		return fmt.Errorf("%w: bad day of month %q", ErrInvalidRule, s)
+	}
+
+	d, _ := strconv.Atoi(m[1])
+	r.MonthDay = d
+	return nil
+}
+
+func parseCount(s string) (int, error) {
+	switch s {
+	case "a", "an", "one":
+		return 1, nil
+	case "other":
+		return 2, nil
+	}
+
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("%w: bad interval %q", ErrInvalidRule, s)
+	}
+	return n, nil
+}
+
+func parseWeekdays(s string) ([]time.Weekday, error) {
+	var wds []time.Weekday
+	for _, name := range listSepRe.Split(strings.TrimSpace(s), -1) {
+		if name == "" {
+			continue
+		}
+
+		wd, ok := weekdayNames[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown weekday %q", ErrInvalidRule, name)
+		}
+		if !slices.Contains(wds, wd) {
+			wds = append(wds, wd)
+		}
+	}
+
+	if len(wds) == 0 {
+		return nil, fmt.Errorf("%w: no weekdays", ErrInvalidRule)
+	}
+
+	slices.Sort(wds)
+	return wds, nil
+}
+
+// parseRRule reads the subset of RFC 5545 RRULE that Rule can represent: FREQ, INTERVAL,
+// BYDAY, BYMONTHDAY, and BYSETPOS=-1 with BYDAY=MO,TU,WE,TH,FR for the last business day.
+// X-FROM=COMPLETION marks a rule that counts from completion.
+func parseRRule(s string) (*Rule, error) {
+	s = strings.TrimPrefix(strings.ToUpper(s), "RRULE:")
+	r := &Rule{Interval: 1}
+	setPos := 0
+	for part := range strings.SplitSeq(s, ";") {
+		if part == "" {
+			continue
+		}
+
+		k, v, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("%w: bad part %q", ErrInvalidRule, part)
+		}
+
+		var err error
+		switch k {
+		case "FREQ":
+			switch v {
+			case "DAILY":
+				r.Freq = Daily
+			case "WEEKLY":
+				r.Freq = Weekly
+			case "MONTHLY":
+				r.Freq = Monthly
+			case "YEARLY":
+				r.Freq = Yearly
+			default:
+				return nil, fmt.Errorf("%w: unsupported FREQ %q", ErrInvalidRule, v)
+			}
+		case "INTERVAL":
+			r.Interval, err = strconv.Atoi(v)
+		case "BYDAY":
+			r.Weekdays, err = parseWeekdays(strings.ToLower(v))
+		case "BYMONTHDAY":
+			r.MonthDay, err = strconv.Atoi(v)
+		case "BYSETPOS":
+			setPos, err = strconv.Atoi(v)
+		case "X-FROM":
+			if v != "COMPLETION" {
+				return nil, fmt.Errorf("%w: unsupported X-FROM %q", ErrInvalidRule, v)
+			}
+			r.FromCompletion = true
+		default:
+			return nil, fmt.Errorf("%w: unsupported part %q", ErrInvalidRule, k)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("%w: bad %s %q", ErrInvalidRule, k, v)
+		}
+	}
+
+	if setPos != 0 {
+		if setPos != -1 || r.Freq != Monthly || !slices.Equal(r.Weekdays, businessDays) {
+			return nil, fmt.Errorf("%w: BYSETPOS is only supported for the last business day", ErrInvalidRule)
+		}
+		r.Weekdays = nil
+		r.LastBusinessDay = true
+	}
+
+	return r, nil
+}
+
+func (r *Rule) validate() error {
+	switch {
+	case r.Freq < Daily || r.Freq > Yearly:
+		return fmt.Errorf("%w: missing frequency", ErrInvalidRule)
+	case r.Interval < 1 || r.Interval > maxInterval:
+		return fmt.Errorf("%w: interval must be 1-%d", ErrInvalidRule, maxInterval)
+	case len(r.Weekdays) > 0 && r.Freq != Weekly:
+		return fmt.Errorf("%w: weekdays are only supported on weekly rules", ErrInvalidRule)
+	case (r.MonthDay != 0 || r.LastBusinessDay) && r.Freq != Monthly:
+		return fmt.Errorf("%w: a day of the month is only supported on monthly rules", ErrInvalidRule)
+	case r.MonthDay < LastDay || r.MonthDay > 31:
+		return fmt.Errorf("%w: day of month must be 1-31", ErrInvalidRule)
+	case r.MonthDay != 0 && r.LastBusinessDay:
+		return fmt.Errorf("%w: both a day of the month and the last business day", ErrInvalidRule)
+	case r.FromCompletion && (len(r.Weekdays) > 0 || r.MonthDay != 0 || r.LastBusinessDay):
+		return fmt.Errorf("%w: rules counted from completion can't have fixed days", ErrInvalidRule)
+	}
+
+	return nil
+}
+
+// String returns the rule's RRULE-style form, which Parse reads back.
+func (r Rule) String() string {
+	parts := []string{"FREQ=" + r.Freq.rrule()}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+
+	days := r.Weekdays
+	if r.LastBusinessDay {
+		days = businessDays
+	}
+	if len(days) > 0 {
+		names := make([]string, len(days))
+		for i, d := range days {
+			names[i] = rruleDays[d]
+		}
+		parts = append(parts, "BYDAY="+strings.Join(names, ","))
+	}
+
+	if r.LastBusinessDay {
+		parts = append(parts, "BYSETPOS=-1")
+	}
+	if r.MonthDay != 0 {
+		parts = append(parts, "BYMONTHDAY="+strconv.Itoa(r.MonthDay))
+	}
+	if r.FromCompletion {
+		parts = append(parts, "X-FROM=COMPLETION")
+	}
+
+	return strings.Join(parts, ";")
+}
+
+// Describe returns the rule as a phrase, like "every 2 weeks on mon, thu". Parse reads
+// the phrase back as the same rule.
+func (r Rule) Describe() string {
+	unit := r.Freq.unit()
+	if r.FromCompletion {
+		if r.Interval != 1 {
+			unit += "s"
+		}
+		return fmt.Sprintf("%d %s after completion", r.Interval, unit)
+	}
+
+	if r.Freq == Weekly && r.Interval == 1 && slices.Equal(r.Weekdays, businessDays) {
+		return "every weekday"
+	}
+
+	s := "every " + unit
+	switch {
+	case r.Interval == 2:
+		s = "every other " + unit
+	case r.Interval > 2:
+		s = fmt.Sprintf("every %d %ss", r.Interval, unit)
+	}
+
+	switch {
+	case len(r.Weekdays) > 0:
+		names := make([]string, len(r.Weekdays))
+		for i, d := range r.Weekdays {
+			names[i] = strings.ToLower(d.String()[:3])
+		}
+		s += " on " + strings.Join(names, ", ")
+	case r.LastBusinessDay:
+		s += " on the last business day"
+	case r.MonthDay == LastDay:
+		s += " on the last day"
+	case r.MonthDay > 0:
+		s += " on the " + ordinal(r.MonthDay)
+	}
+
+	return s
+}
+
+func (f Freq) rrule() string {
+	switch f {
+	case Daily:
+		return "DAILY"
+	case Weekly:
+		return "WEEKLY"
+	case Monthly:
+		return "MONTHLY"
+	case Yearly:
+		return "YEARLY"
+	default:
+		return ""
+	}
+}
+
+func (f Freq) unit() string {
+	switch f {
+	case Daily:
+		return "day"
+	case Weekly:
+		return "week"
+	case Monthly:
+		return "month"
+	case Yearly:
+		return "year"
+	default:
+		return ""
+	}
+}
+
+func ordinal(n int) string {
+	suffix := "th"
+	switch {
+	case n%100 >= 11 && n%100 <= 13:
+	case n%10 == 1:
+		suffix = "st"
+	case n%10 == 2:
+		suffix = "nd"
+	case n%10 == 3:
+		suffix = "rd"
+	}
+
+	return strconv.Itoa(n) + suffix
+}
+
+// Anchor fixes a monthly rule without a day of the month to the day that due falls on, so
+// a task due on the 31st keeps coming back on the last day of shorter months rather than
+// drifting to the 28th.
+func (r Rule) Anchor(due time.Time) Rule {
+	if r.Freq == Monthly && r.MonthDay == 0 && !r.LastBusinessDay && !r.FromCompletion {
+		r.MonthDay = due.Day()
+	}
+	return r
+}
+
+// First returns the first occurrence on or after from, keeping from's time of day. Rules
+// without fixed days start on from itself. The interval is counted from the first
+// occurrence, so it doesn't affect which day that is.
+func (r Rule) First(from time.Time) time.Time {
+	if !r.hasFixedDays() {
+		return from
+	}
+
+	r.Interval = 1
+	y, m, d := from.Date()
+	return r.step(r.at(from, y, m, d-1))
+}
+
+// Next returns when the occurrence after one due at due should be due, given that it was
+// completed at completed. The time of day and location of due are kept, and calendar
+// arithmetic is done on wall clock dates so DST changes don't shift it.
+//
+// Occurrences that would already be overdue on the day of completion are skipped, so a
+// daily chore that was missed for a week comes back once rather than seven times.
+func (r Rule) Next(due, completed time.Time) time.Time {
+	if r.FromCompletion {
+		c := completed.In(due.Location())
+		base := time.Date(c.Year(), c.Month(), c.Day(), due.Hour(), due.Minute(), due.Second(), 0, due.Location())
+		return r.step(base)
+	}
+
+	next := r.step(due)
+	today := dayNumber(completed.In(due.Location()))
+	for i := 0; i < maxSteps && dayNumber(next) < today; i++ {
+		next = r.step(next)
+	}
+
+	return next
+}
+
+func (r Rule) hasFixedDays() bool {
+	return len(r.Weekdays) > 0 || r.MonthDay != 0 || r.LastBusinessDay
+}
+
+// step returns the first occurrence after t.
+func (r Rule) step(t time.Time) time.Time {
+	y, m, d := t.Date()
+	switch r.Freq {
+	case Daily:
+		return r.at(t, y, m, d+r.Interval)
+	case Weekly:
+		if len(r.Weekdays) == 0 {
+			return r.at(t, y, m, d+7*r.Interval)
+		}
+		return r.nextWeekday(t)
+	case Monthly:
+		if r.hasFixedDays() {
+			// this month's occurrence may still be ahead
+			if c := r.monthOccurrence(t, y, m); dayNumber(c) > dayNumber(t) {
+				return c
+			}
+		}
+		return r.monthOccurrence(t, y, m+time.Month(r.Interval))
+	case Yearly:
+		return r.at(t, y+r.Interval, m, min(d, daysIn(y+r.Interval, m)))
+	default:
+		return t
+	}
+}
+
+// nextWeekday finds the next day after t that's on one of the rule's weekdays, in a week
+// that's a multiple of the interval after t's week. Weeks start on Monday.
+func (r Rule) nextWeekday(t time.Time) time.Time {
+	y, m, d := t.Date()
+
+	// the rest of t's week comes first, then the first day of the next week in the rule
+	offset := dayNumber(t) - weekStart(dayNumber(t))
+	for i := offset + 1; i < 7; i++ {
+		if c := r.at(t, y, m, d-offset+i); slices.Contains(r.Weekdays, c.Weekday()) {
+			return c
+		}
+	}
+
+	monday := d - offset + 7*r.Interval
+	for i := range 7 {
+		if c := r.at(t, y, m, monday+i); slices.Contains(r.Weekdays, c.Weekday()) {
+			return c
+		}
+	}
+
+	// unreachable for a valid rule
+	return r.at(t, y, m, d+7*r.Interval)
+}
+
+// monthOccurrence returns the rule's day in the given month, which may overflow into
+// later years.
+func (r Rule) monthOccurrence(t time.Time, y int, m time.Month) time.Time {
+	// normalize the month first so daysIn gets a real one
+	first := time.Date(y, m, 1, 12, 0, 0, 0, time.UTC)
+	y, m = first.Year(), first.Month()
+	last := daysIn(y, m)
+
+	switch {
+	case r.LastBusinessDay:
+		d := last
+		for {
+			wd := time.Date(y, m, d, 12, 0, 0, 0, time.UTC).Weekday()
+			if wd != time.Saturday && wd != time.Sunday {
+				break
+			}
+			d--
+		}
+		return r.at(t, y, m, d)
+	case r.MonthDay == LastDay:
+		return r.at(t, y, m, last)
+	case r.MonthDay > 0:
+		return r.at(t, y, m, min(r.MonthDay, last))
+	default:
+		return r.at(t, y, m, min(t.Day(), last))
+	}
+}
+
+// at returns the given date with t's time of day and location.
+func (r Rule) at(t time.Time, y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+}
+
+func daysIn(y int, m time.Month) int {
+	return time.Date(y, m+1, 0, 12, 0, 0, 0, time.UTC).Day()
+}
+
+// dayNumber counts calendar days since the Unix epoch for t's date in its own location,
+// which is unaffected by DST.
+func dayNumber(t time.Time) int {
+	y, m, d := t.Date()
+	return int(time.Date(y, m, d, 0, 0, 0, 0, time.UTC).Unix() / 86400)
+}
+
+// weekStart returns the day number of the Monday on or before day n. Day 0 was a Thursday.
+func weekStart(n int) int {
+	offset := (n + 3) % 7
+	if offset < 0 {
+		offset += 7
+	}
+	return n - offset
+}
@@ -0,0 +1,265 @@
+package recur
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+var mon, tue, wed, thu, fri = time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Rule
+	}{
+		{"daily", Rule{Freq: Daily, Interval: 1}},
+		{"weekly", Rule{Freq: Weekly, Interval: 1}},
+		{"monthly", Rule{Freq: Monthly, Interval: 1}},
+		{"annually", Rule{Freq: Yearly, Interval: 1}},
+		{"every day", Rule{Freq: Daily, Interval: 1}},
+		{"every 3 days", Rule{Freq: Daily, Interval: 3}},
+		{"every other week", Rule{Freq: Weekly, Interval: 2}},
+		{"every 2 months", Rule{Freq: Monthly, Interval: 2}},
+		{"  Every   YEAR ", Rule{Freq: Yearly, Interval: 1}},
+		{"every weekday", Rule{Freq: Weekly, Interval: 1, Weekdays: []time.Weekday{mon, tue, wed, thu, fri}}},
+		{"every mon", Rule{Freq: Weekly, Interval: 1, Weekdays: []time.Weekday{mon}}},
+		{"every thursday and mon", Rule{Freq: Weekly, Interval: 1, Weekdays: []time.Weekday{mon, thu}}},
+		{"every 2 weeks on mon, thu", Rule{Freq: Weekly, Interval: 2, Weekdays: []time.Weekday{mon, thu}}},
+		{"weekly on tue/fri", Rule{Freq: Weekly, Interval: 1, Weekdays: []time.Weekday{tue, fri}}},
+		{"monthly on the 15th", Rule{Freq: Monthly, Interval: 1, MonthDay: 15}},
+		{"every month on 31", Rule{Freq: Monthly, Interval: 1, MonthDay: 31}},
+		{"every month on the last day", Rule{Freq: Monthly, Interval: 1, MonthDay: LastDay}},
+		{"last day", Rule{Freq: Monthly, Interval: 1, MonthDay: LastDay}},
+		{"every month on the last business day", Rule{Freq: Monthly, Interval: 1, LastBusinessDay: true}},
+		{"last business day", Rule{Freq: Monthly, Interval: 1, LastBusinessDay: true}},
+		{"3 days after completion", Rule{Freq: Daily, Interval: 3, FromCompletion: true}},
+		{"a week after done", Rule{Freq: Weekly, Interval: 1, FromCompletion: true}},
+
+		{"FREQ=DAILY", Rule{Freq: Daily, Interval: 1}},
+		{"FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH", Rule{Freq: Weekly, Interval: 2, Weekdays: []time.Weekday{mon, thu}}},
+		{"RRULE:FREQ=MONTHLY;BYMONTHDAY=-1", Rule{Freq: Monthly, Interval: 1, MonthDay: LastDay}},
+		{"freq=monthly;bymonthday=31", Rule{Freq: Monthly, Interval: 1, MonthDay: 31}},
+		{"FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1", Rule{Freq: Monthly, Interval: 1, LastBusinessDay: true}},
+		{"FREQ=DAILY;INTERVAL=3;X-FROM=COMPLETION", Rule{Freq: Daily, Interval: 3, FromCompletion: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := Parse(tt.in)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.in, err)
+			}
+			if !reflect.DeepEqual(*got, tt.want) {
+				t.Errorf("Parse(%q) = %+v, want %+v", tt.in, *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	tests := []string{
+		"",
+		"sometimes",
+		"every blue moon",
+		"every 0 days",
+		"every 1001 days",
+		"every 100000000 weeks on mon",
+		"every funday",
+		"monthly on the 32nd",
+		"daily on mon",
+		"every mon on tue",
+		"last business day on the 3rd",
+		"FREQ=HOURLY",
+		"FREQ=DAILY;BYDAY=MO",
+		"FREQ=WEEKLY;BYMONTHDAY=3",
+		"FREQ=WEEKLY;BYDAY=MO;BYSETPOS=-1",
+		"FREQ=MONTHLY;BYMONTHDAY=10;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1",
+		"FREQ=DAILY;INTERVAL=x",
+		"FREQ=DAILY;COUNT=3",
+		"FREQ=WEEKLY;BYDAY=MO;X-FROM=COMPLETION",
+		"INTERVAL=2",
+	}
+
+	for _, in := range tests {
+		t.Run(in, func(t *testing.T) {
+			r, err := Parse(in)
+			if !errors.Is(err, ErrInvalidRule) {
+				t.Errorf("Parse(%q) = %+v, %v, want ErrInvalidRule", in, r, err)
+			}
+		})
+	}
+}
+
+func TestStringDescribe(t *testing.T) {
+	tests := []struct {
+		rule     Rule
+		str      string
+		describe string
+	}{
+		{Rule{Freq: Daily, Interval: 1}, "FREQ=DAILY", "every day"},
+		{Rule{Freq: Daily, Interval: 2}, "FREQ=DAILY;INTERVAL=2", "every other day"},
+		{Rule{Freq: Weekly, Interval: 3}, "FREQ=WEEKLY;INTERVAL=3", "every 3 weeks"},
+		{Rule{Freq: Weekly, Interval: 1, Weekdays: []time.Weekday{mon, tue, wed, thu, fri}}, "FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR", "every weekday"},
+		{Rule{Freq: Weekly, Interval: 2, Weekdays: []time.Weekday{mon, tue, wed, thu, fri}}, "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TU,WE,TH,FR", "every other week on mon, tue, wed, thu, fri"},
+		{Rule{Freq: Weekly, Interval: 2, Weekdays: []time.Weekday{mon, thu}}, "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH", "every other week on mon, thu"},
+		{Rule{Freq: Weekly, Interval: 1, Weekdays: []time.Weekday{time.Sunday, time.Saturday}}, "FREQ=WEEKLY;BYDAY=SU,SA", "every week on sun, sat"},
+		{Rule{Freq: Monthly, Interval: 1, MonthDay: 1}, "FREQ=MONTHLY;BYMONTHDAY=1", "every month on the 1st"},
+		{Rule{Freq: Monthly, Interval: 1, MonthDay: 22}, "FREQ=MONTHLY;BYMONTHDAY=22", "every month on the 22nd"},
+		{Rule{Freq: Monthly, Interval: 6, MonthDay: 13}, "FREQ=MONTHLY;INTERVAL=6;BYMONTHDAY=13", "every 6 months on the 13th"},
+		{Rule{Freq: Monthly, Interval: 1, MonthDay: LastDay}, "FREQ=MONTHLY;BYMONTHDAY=-1", "every month on the last day"},
+		{Rule{Freq: Monthly, Interval: 1, LastBusinessDay: true}, "FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1", "every month on the last business day"},
+		{Rule{Freq: Yearly, Interval: 1}, "FREQ=YEARLY", "every year"},
+		{Rule{Freq: Daily, Interval: 1, FromCompletion: true}, "FREQ=DAILY;X-FROM=COMPLETION", "1 day after completion"},
+		{Rule{Freq: Monthly, Interval: 2, FromCompletion: true}, "FREQ=MONTHLY;INTERVAL=2;X-FROM=COMPLETION", "2 months after completion"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.str, func(t *testing.T) {
+			if got := tt.rule.String(); got != tt.str {
+				t.Errorf("String() = %q, want %q", got, tt.str)
+			}
+			if got := tt.rule.Describe(); got != tt.describe {
+				t.Errorf("Describe() = %q, want %q", got, tt.describe)
+			}
+
+			for _, s := range []string{tt.str, tt.describe} {
+				back, err := Parse(s)
+				if err != nil {
+					t.Fatalf("Parse(%q): %v", s, err)
+				}
+				if !reflect.DeepEqual(*back, tt.rule) {
+					t.Errorf("Parse(%q) = %+v, want %+v", s, *back, tt.rule)
+				}
+			}
+		})
+	}
+}
+
+func mustParse(t *testing.T, s string) Rule {
+	t.Helper()
+	r, err := Parse(s)
+	if err != nil {
+		t.Fatalf("Parse(%q): %v", s, err)
+	}
+	return *r
+}
+
+func TestNext(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := func(y int, m time.Month, d, h, min int) time.Time {
+		return time.Date(y, m, d, h, min, 0, 0, ny)
+	}
+
+	tests := []struct {
+		name      string
+		rule      string
+		anchor    bool
+		due       time.Time
+		completed time.Time
+		want      time.Time
+	}{
+		{"daily", "daily", false, at(2026, 3, 2, 9, 0), at(2026, 3, 2, 10, 0), at(2026, 3, 3, 9, 0)},
+		{"daily completed early", "daily", false, at(2026, 3, 5, 9, 0), at(2026, 3, 2, 10, 0), at(2026, 3, 6, 9, 0)},
+		{"every 3 days", "every 3 days", false, at(2026, 3, 2, 9, 0), at(2026, 3, 2, 9, 0), at(2026, 3, 5, 9, 0)},
+		{"daily over spring forward", "daily", false, at(2026, 3, 7, 9, 30), at(2026, 3, 7, 9, 30), at(2026, 3, 8, 9, 30)},
+		{"daily over fall back", "daily", false, at(2026, 10, 31, 9, 30), at(2026, 10, 31, 9, 30), at(2026, 11, 1, 9, 30)},
+		{"weekly over spring forward", "weekly", false, at(2026, 3, 4, 23, 0), at(2026, 3, 4, 23, 0), at(2026, 3, 11, 23, 0)},
+		{"weekly", "weekly", false, at(2026, 3, 4, 9, 0), at(2026, 3, 4, 9, 0), at(2026, 3, 11, 9, 0)},
+		{"every weekday over the weekend", "every weekday", false, at(2026, 3, 6, 9, 0), at(2026, 3, 6, 9, 0), at(2026, 3, 9, 9, 0)},
+		{"interval weekly in the same week", "every 2 weeks on mon, thu", false, at(2026, 3, 2, 9, 0), at(2026, 3, 2, 9, 0), at(2026, 3, 5, 9, 0)},
+		{"interval weekly skips a week", "every 2 weeks on mon, thu", false, at(2026, 3, 5, 9, 0), at(2026, 3, 5, 9, 0), at(2026, 3, 16, 9, 0)},
+		{"interval weekly from a sunday", "every 3 weeks on mon", false, at(2026, 3, 8, 9, 0), at(2026, 3, 8, 9, 0), at(2026, 3, 23, 9, 0)},
+		{"long interval weekly", "every 1000 weeks on mon", false, at(2026, 3, 2, 9, 0), at(2026, 3, 2, 9, 0), at(2026, 3, 2, 9, 0).AddDate(0, 0, 7000)},
+		{"monthly on the 31st into february", "monthly on the 31st", false, at(2026, 1, 31, 9, 0), at(2026, 1, 31, 9, 0), at(2026, 2, 28, 9, 0)},
+		{"monthly on the 31st out of february", "monthly on the 31st", false, at(2026, 2, 28, 9, 0), at(2026, 2, 28, 9, 0), at(2026, 3, 31, 9, 0)},
+		{"monthly on the 31st into april", "monthly on the 31st", false, at(2026, 3, 31, 9, 0), at(2026, 3, 31, 9, 0), at(2026, 4, 30, 9, 0)},
+		{"monthly anchored to the 31st", "monthly", true, at(2026, 1, 31, 9, 0), at(2026, 1, 31, 9, 0), at(2026, 2, 28, 9, 0)},
+		{"monthly on the 15th later this month", "monthly on the 15th", false, at(2026, 3, 2, 9, 0), at(2026, 3, 2, 9, 0), at(2026, 3, 15, 9, 0)},
+		{"monthly on the last day", "every month on the last day", false, at(2026, 2, 28, 9, 0), at(2026, 2, 28, 9, 0), at(2026, 3, 31, 9, 0)},
+		{"monthly over the year end", "every 2 months", false, at(2026, 12, 10, 9, 0), at(2026, 12, 10, 9, 0), at(2027, 2, 10, 9, 0)},
+		{"last business day skips a weekend", "last business day", false, at(2026, 1, 30, 9, 0), at(2026, 1, 30, 9, 0), at(2026, 2, 27, 9, 0)},
+		{"last business day on the last day", "last business day", false, at(2026, 2, 27, 9, 0), at(2026, 2, 27, 9, 0), at(2026, 3, 31, 9, 0)},
+		{"monthly on the 29th in a leap year", "monthly on the 29th", false, at(2024, 1, 29, 9, 0), at(2024, 1, 29, 9, 0), at(2024, 2, 29, 9, 0)},
+		{"monthly on the 29th otherwise", "monthly on the 29th", false, at(2025, 1, 29, 9, 0), at(2025, 1, 29, 9, 0), at(2025, 2, 28, 9, 0)},
+		{"daily onto a leap day", "daily", false, at(2024, 2, 28, 9, 0), at(2024, 2, 28, 9, 0), at(2024, 2, 29, 9, 0)},
+		{"yearly from a leap day", "yearly", false, at(2024, 2, 29, 9, 0), at(2024, 2, 29, 9, 0), at(2025, 2, 28, 9, 0)},
+		{"every 4 years from a leap day", "every 4 years", false, at(2024, 2, 29, 9, 0), at(2024, 2, 29, 9, 0), at(2028, 2, 29, 9, 0)},
+		{"days after completion", "3 days after completion", false, at(2026, 3, 2, 9, 0), at(2026, 3, 10, 18, 0), at(2026, 3, 13, 9, 0)},
+		{"weeks after completion", "2 weeks after done", false, at(2026, 3, 2, 9, 0), at(2026, 2, 27, 8, 0), at(2026, 3, 13, 9, 0)},
+		{"after completion late at night in utc", "1 day after completion", false, at(2026, 3, 2, 9, 0), time.Date(2026, 3, 3, 3, 0, 0, 0, time.UTC), at(2026, 3, 3, 9, 0)},
+		{"catch up daily", "daily", false, at(2026, 3, 2, 9, 0), at(2026, 3, 10, 15, 0), at(2026, 3, 10, 9, 0)},
+		{"catch up weekly", "every mon", false, at(2026, 3, 2, 9, 0), at(2026, 3, 18, 15, 0), at(2026, 3, 23, 9, 0)},
+		{"catch up interval weekly", "every 2 weeks on mon", false, at(2026, 3, 2, 9, 0), at(2026, 3, 18, 15, 0), at(2026, 3, 30, 9, 0)},
+		{"catch up monthly", "monthly on the 31st", false, at(2026, 1, 31, 9, 0), at(2026, 5, 1, 9, 0), at(2026, 5, 31, 9, 0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := mustParse(t, tt.rule)
+			if tt.anchor {
+				r = r.Anchor(tt.due)
+			}
+
+			got := r.Next(tt.due, tt.completed)
+			if !got.Equal(tt.want) || got.Location() != ny {
+				t.Errorf("Next(%v, %v) = %v, want %v", tt.due, tt.completed, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNextAnchoredMonthly(t *testing.T) {
+	r := mustParse(t, "monthly").Anchor(time.Date(2026, 1, 31, 9, 0, 0, 0, time.UTC))
+	due := time.Date(2026, 1, 31, 9, 0, 0, 0, time.UTC)
+
+	var got []int
+	for range 4 {
+		due = r.Next(due, due)
+		got = append(got, due.Day())
+	}
+
+	// the 31st comes back after a short month rather than drifting to the 28th
+	if want := []int{28, 31, 30, 31}; !reflect.DeepEqual(got, want) {
+		t.Errorf("days = %v, want %v", got, want)
+	}
+}
+
+func TestFirst(t *testing.T) {
+	at := func(m time.Month, d int) time.Time {
+		return time.Date(2026, m, d, 9, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		rule string
+		from time.Time
+		want time.Time
+	}{
+		{"daily", at(3, 3), at(3, 3)},
+		{"every 3 weeks", at(3, 3), at(3, 3)},
+		{"every mon, thu", at(3, 3), at(3, 5)},
+		{"every mon, thu", at(3, 2), at(3, 2)},
+		{"every 2 weeks on mon", at(3, 3), at(3, 9)},
+		{"every weekday", at(3, 7), at(3, 9)},
+		{"monthly on the 15th", at(3, 15), at(3, 15)},
+		{"monthly on the 15th", at(3, 20), at(4, 15)},
+		{"every 3 months on the 1st", at(3, 20), at(4, 1)},
+		{"monthly on the 31st", at(4, 2), at(4, 30)},
+		{"every month on the last day", at(2, 1), at(2, 28)},
+		{"last business day", at(1, 31), at(2, 27)},
+		{"last business day", at(3, 1), at(3, 31)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rule+" from "+tt.from.Format("Jan 2"), func(t *testing.T) {
+			if got := mustParse(t, tt.rule).First(tt.from); !got.Equal(tt.want) {
+				t.Errorf("First(%v) = %v, want %v", tt.from, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,269 @@
+package sqlitedb
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dsrosen6/yata/dates"
+	"github.com/dsrosen6/yata/filter"
+	"github.com/dsrosen6/yata/models"
+)
+
+// queryTasks is the start of a filtered task query, which the compiled filter completes.
+// It isn't generated, since sqlc can only work with fixed queries.
+const queryTasks = `SELECT id, title, parent_task_id, project_id, complete, due_at, created_at, updated_at, priority, notes, recurrence, deleted_at, position, completed_at, archived_at FROM task
+WHERE task.deleted_at IS NULL AND task.archived_at IS NULL AND `
+
+// dueWallClock is the due date's local date and time, without the zone that follows it,
+// in a form that sorts the same as it reads.
+const (
+	dueWallClock       = "substr(task.due_at, 1, 19)"
+	dueWallClockLayout = "2006-01-02 15:04:05"
+)
+
+// filterCompiler turns a parsed filter into a WHERE clause for the task table. Values are
+// always passed as arguments, never written into the SQL.
+type filterCompiler struct {
+	dates *dates.Parser
+	args  []any
+}
+
+func compileFilter(f *filter.Filter, dp *dates.Parser) (string, []any, error) {
+	c := &filterCompiler{dates: dp}
+	where, err := c.expr(f.Expr)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return where, c.args, nil
+}
+
+func (c *filterCompiler) expr(e filter.Expr) (string, error) {
+	switch e := e.(type) {
+	case filter.And:
+		return c.binary(e.X, e.Y, "AND")
+	case filter.Or:
+		return c.binary(e.X, e.Y, "OR")
+	case filter.Not:
+		x, err := c.expr(e.X)
+		if err != nil {
+			return "", err
+		}
+		return "(NOT " + x + ")", nil
+	case filter.Cond:
+		return c.cond(e)
+	}
+
+	return "", fmt.Errorf("unknown filter expression %T", e)
+}
+
+func (c *filterCompiler) binary(x, y filter.Expr, op string) (string, error) {
+	xs, err := c.expr(x)
+	if err != nil {
+		return "", err
+	}
+
+	ys, err := c.expr(y)
+	if err != nil {
+		return "", err
+	}
+
+	return "(" + xs + " " + op + " " + ys + ")", nil
+}
+
+// cond compiles a single condition. Every clause it returns is true or false, never
+// NULL, so negating it with NOT always matches the rest of the tasks.
+func (c *filterCompiler) cond(e filter.Cond) (string, error) {
+	switch e.Field {
+	case filter.Done:
+		return "(task.complete = 1)", nil
+	case filter.Blocked:
+		return `(EXISTS (
+    SELECT 1 FROM task_dependency
+    JOIN task blocker ON blocker.id = task_dependency.blocked_by_id
+    WHERE task_dependency.task_id = task.id AND blocker.complete = 0 AND blocker.deleted_at IS NULL
+))`, nil
+	case filter.Recurring:
+		return "(task.recurrence != '')", nil
+	case filter.Due:
+		return c.due(e)
+	case filter.Priority:
+		p, err := models.ParsePriority(e.Value)
+		if err != nil {
+			return "", filter.Errorf(e.ValuePos, "%v", err)
+		}
+
+		op := string(e.Op)
+		if e.Op == filter.Is {
+			op = "="
+		}
+		return c.clause("(task.priority "+op+" ?)", int64(p)), nil
+	}
+
+	var match string
+	switch e.Field {
+	case filter.Title:
+		if e.Op == filter.Is {
+			return c.clause(`(task.title LIKE ? ESCAPE '\')`, "%"+escapeLike(e.Value)+"%"), nil
+		}
+		match = c.clause("(task.title = ? COLLATE NOCASE)", e.Value)
+
+	case filter.Tag:
+		match = c.clause(`(EXISTS (
+    SELECT 1 FROM task_tag
+    JOIN tag ON tag.id = task_tag.tag_id
+    WHERE task_tag.task_id = task.id AND tag.name = ?
+))`, strings.TrimPrefix(e.Value, "+"))
+
+	case filter.Project:
+		m, err := c.project(e)
+		if err != nil {
+			return "", err
+		}
+		match = m
+
+	default:
+		return "", filter.Errorf(e.FieldPos, "unknown field %q", e.Field)
+	}
+
+	if e.Op == filter.Ne {
+		return "(NOT " + match + ")", nil
+	}
+	return match, nil
+}
+
+// project matches tasks in a project or any of its sub-projects. A path like work/clients
+// starts from a top-level project, while a single title matches a project at any level.
+func (c *filterCompiler) project(e filter.Cond) (string, error) {
+	path := e.Value
+	if strings.EqualFold(path, filter.None) {
+		return "(task.project_id IS NULL)", nil
+	}
+
+	var titles []string
+	for _, t := range strings.Split(path, "/") {
+		if t = strings.TrimSpace(t); t != "" {
+			titles = append(titles, t)
+		}
+	}
+	if len(titles) == 0 {
+		return "", filter.Errorf(e.ValuePos, "empty project path")
+	}
+
+	// the path is matched from its last title up, so the arguments go in that order
+	seed := "SELECT id FROM project WHERE deleted_at IS NULL AND title = ? COLLATE NOCASE"
+	if len(titles) > 1 {
+		seed += " AND parent_project_id IS NULL"
+	}
+	for i := 1; i < len(titles); i++ {
+		seed = "SELECT id FROM project WHERE deleted_at IS NULL AND title = ? COLLATE NOCASE AND parent_project_id IN (" + seed + ")"
+	}
+	for i := len(titles) - 1; i >= 0; i-- {
+		c.args = append(c.args, titles[i])
+	}
+
+	return `(task.project_id IS NOT NULL AND task.project_id IN (
+    WITH RECURSIVE tree(id) AS (
+        ` + seed + `
+        UNION
+        SELECT project.id FROM project
+        JOIN tree ON project.parent_project_id = tree.id
+        WHERE project.deleted_at IS NULL
+    )
+    SELECT id FROM tree
+))`, nil
+}
+
+// due compares due dates. A date without a time covers the whole day, so due<=fri
+// includes anything due on Friday and due>fri starts on Saturday. A time covers its
+// minute.
+func (c *filterCompiler) due(e filter.Cond) (string, error) {
+	switch strings.ToLower(e.Value) {
+	case filter.None, filter.Any:
+		if e.Op != filter.Is && e.Op != filter.Eq && e.Op != filter.Ne {
+			return "", filter.Errorf(e.ValuePos, "due%s%s doesn't make sense, use due:%s", e.Op, e.Value, e.Value)
+		}
+
+		has := (e.Op == filter.Ne) == strings.EqualFold(e.Value, filter.None)
+		if has {
+			return "(task.due_at IS NOT NULL)", nil
+		}
+		return "(task.due_at IS NULL)", nil
+	}
+
+	start, err := c.dates.Parse(e.Value)
+	if err != nil {
+		return "", filter.Errorf(e.ValuePos, "can't read %q as a date", e.Value)
+	}
+
+	end := start.Add(time.Minute)
+	if !dates.HasTime(start) {
+		end = start.AddDate(0, 0, 1)
+	}
+
+	lo, hi := start.Format(dueWallClockLayout), end.Format(dueWallClockLayout)
+	col := dueWallClock
+	switch e.Op {
+	case filter.Lt:
+		return c.clause("(task.due_at IS NOT NULL AND "+col+" < ?)", lo), nil
+	case filter.Le:
+		return c.clause("(task.due_at IS NOT NULL AND "+col+" < ?)", hi), nil
+	case filter.Gt:
+		return c.clause("(task.due_at IS NOT NULL AND "+col+" >= ?)", hi), nil
+	case filter.Ge:
+		return c.clause("(task.due_at IS NOT NULL AND "+col+" >= ?)", lo), nil
+	}
+
+	match := c.clause("(task.due_at IS NOT NULL AND "+col+" >= ? AND "+col+" < ?)", lo, hi)
+	if e.Op == filter.Ne {
+		return "(NOT " + match + ")", nil
+	}
+	return match, nil
+}
+
+// clause adds the arguments for a clause's placeholders and returns it.
+func (c *filterCompiler) clause(sql string, args ...any) string {
+	c.args = append(c.args, args...)
+	return sql
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+func scanTasks(rows *sql.Rows) ([]*Task, error) {
+	defer rows.Close()
+	items := []*Task{}
+	for rows.Next() {
+		var i Task
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.ParentTaskID,
+			&i.ProjectID,
+			&i.Complete,
+			&i.DueAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Priority,
+			&i.Notes,
+			&i.Recurrence,
+			&i.DeletedAt,
+			&i.Position,
+			&i.CompletedAt,
+			&i.ArchivedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,238 @@
+package sqlitedb
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/dsrosen6/yata/dates"
+	"github.com/dsrosen6/yata/filter"
+	"github.com/dsrosen6/yata/models"
+)
+
+// filterRepos makes a database of tasks to filter, with dates read as of Wednesday
+// March 4th 2026 at 10:30 in New York.
+func filterRepos(t *testing.T) *models.AllRepos {
+	t.Helper()
+	ctx := context.Background()
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, 3, 4, 10, 30, 0, 0, loc)
+
+	repos := openRepos(t, filepath.Join(t.TempDir(), "app.db"))
+	repos.Tasks.(*TaskRepo).dates = &dates.Parser{Now: func() time.Time { return now }, Loc: loc}
+
+	project := func(title string, parentID *int64) *int64 {
+		p, err := repos.Projects.Create(ctx, &models.Project{Title: title, ParentID: parentID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &p.ID
+	}
+	work := project("Work", nil)
+	clients := project("Clients", work)
+	home := project("Home", nil)
+
+	due := func(d, h, m int) *time.Time {
+		t := time.Date(2026, 3, d, h, m, 0, 0, loc)
+		return &t
+	}
+
+	ids := make(map[string]int64)
+	add := func(tk *models.Task, tags ...string) {
+		created, err := repos.Tasks.Create(ctx, tk)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, tag := range tags {
+			if err := repos.Tags.AddToTask(ctx, created.ID, tag); err != nil {
+				t.Fatal(err)
+			}
+		}
+		ids[tk.Title] = created.ID
+	}
+
+	add(&models.Task{Title: "buy milk", ProjectID: home, DueAt: due(4, 0, 0), Priority: models.PriorityHigh}, "errand")
+	// late on Friday in New York, which is Saturday in UTC
+	add(&models.Task{Title: "invoice", ProjectID: clients, DueAt: due(6, 23, 30)}, "waiting")
+	add(&models.Task{Title: "standup", ProjectID: work, DueAt: due(7, 0, 0), Priority: models.PriorityLow})
+	add(&models.Task{Title: "call Sam", Complete: true})
+
+	plants := &models.Task{Title: "water plants", DueAt: due(6, 17, 0)}
+	if err := plants.SetRecurrence("every week"); err != nil {
+		t.Fatal(err)
+	}
+	add(plants)
+
+	if err := repos.Tasks.AddDependency(ctx, ids["invoice"], ids["standup"]); err != nil {
+		t.Fatal(err)
+	}
+	return repos
+}
+
+func TestQuery(t *testing.T) {
+	repos := filterRepos(t)
+
+	tests := []struct {
+		filter string
+		want   []string
+	}{
+		{"project:work", []string{"invoice", "standup"}},
+		{"project:WORK/clients", []string{"invoice"}},
+		{"project:clients", []string{"invoice"}},
+		{"project:home/clients", nil},
+		{"project:clients/work", nil},
+		{"project:none", []string{"call Sam", "water plants"}},
+		{"project!=work", []string{"buy milk", "call Sam", "water plants"}},
+		{"project!=none", []string{"buy milk", "invoice", "standup"}},
+
+		{"tag:errand", []string{"buy milk"}},
+		{"tag:+errand", []string{"buy milk"}},
+		{"tag!=waiting", []string{"buy milk", "standup", "call Sam", "water plants"}},
+
+		{"title:MILK", []string{"buy milk"}},
+		{"milk", []string{"buy milk"}},
+		{`title:"buy milk"`, []string{"buy milk"}},
+		{"title=milk", nil},
+		{`title="Buy Milk"`, []string{"buy milk"}},
+		// LIKE's wildcards are matched literally
+		{"title:%", nil},
+		{"title:_", nil},
+
+		{"due:today", []string{"buy milk"}},
+		{"due:fri", []string{"invoice", "water plants"}},
+		{"due=fri", []string{"invoice", "water plants"}},
+		{"due!=fri", []string{"buy milk", "standup", "call Sam"}},
+		{"due<fri", []string{"buy milk"}},
+		{"due<=fri", []string{"buy milk", "invoice", "water plants"}},
+		{"due>fri", []string{"standup"}},
+		{"due>=fri", []string{"invoice", "standup", "water plants"}},
+		{"due>=sat", []string{"standup"}},
+		{`due:"fri 5pm"`, []string{"water plants"}},
+		{`due<"fri 5pm"`, []string{"buy milk"}},
+		{`due>"fri 5pm"`, []string{"invoice", "standup"}},
+		{"due:none", []string{"call Sam"}},
+		{"due:any", []string{"buy milk", "invoice", "standup", "water plants"}},
+		{"due!=none", []string{"buy milk", "invoice", "standup", "water plants"}},
+		{"due!=any", []string{"call Sam"}},
+
+		{"priority:high", []string{"buy milk"}},
+		{"priority>=low", []string{"buy milk", "standup"}},
+		{"priority<medium", []string{"invoice", "standup", "call Sam", "water plants"}},
+
+		{"done", []string{"call Sam"}},
+		{"blocked", []string{"invoice"}},
+		{"recurring", []string{"water plants"}},
+
+		{"not done", []string{"buy milk", "invoice", "standup", "water plants"}},
+		{"not due:fri", []string{"buy milk", "standup", "call Sam"}},
+		{"not project:work", []string{"buy milk", "call Sam", "water plants"}},
+		{"not not blocked", []string{"invoice"}},
+		{"not (done or recurring)", []string{"buy milk", "invoice", "standup"}},
+
+		{"project:work due:sat", []string{"standup"}},
+		{"project:work and due:sat", []string{"standup"}},
+		{"project:home or project:work due:sat", []string{"buy milk", "standup"}},
+		{"project:work due:sat or project:home", []string{"buy milk", "standup"}},
+		{"(project:home or project:work) due:sat", []string{"standup"}},
+		{"due:fri or done not recurring", []string{"invoice", "water plants", "call Sam"}},
+		{"not project:work and tag:errand or done", []string{"buy milk", "call Sam"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filter, func(t *testing.T) {
+			f, err := filter.Parse(tt.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			tasks, err := repos.Tasks.Query(context.Background(), f)
+			if err != nil {
+				t.Fatalf("Query(%q) = %v", tt.filter, err)
+			}
+
+			var got []string
+			for _, tk := range tasks {
+				got = append(got, tk.Title)
+			}
+			slices.Sort(got)
+			want := slices.Clone(tt.want)
+			slices.Sort(want)
+			if !slices.Equal(got, want) {
+				t.Errorf("Query(%q) = %q, want %q", tt.filter, got, want)
+			}
+		})
+	}
+}
+
+func TestQueryErrors(t *testing.T) {
+	repos := filterRepos(t)
+
+	tests := []struct {
+		filter string
+		pos    int
+		msg    string
+	}{
+		{"due:someday", 5, `can't read "someday" as a date`},
+		{"a or due<=whenever", 11, `can't read "whenever" as a date`},
+		{"due<none", 5, "doesn't make sense, use due:none"},
+		{"due>=any", 6, "doesn't make sense, use due:any"},
+		{"priority:urgentest", 10, "priority"},
+		{"project:/", 9, "empty project path"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filter, func(t *testing.T) {
+			f, err := filter.Parse(tt.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = repos.Tasks.Query(context.Background(), f)
+			var fe *filter.Error
+			if !errors.As(err, &fe) {
+				t.Fatalf("Query(%q) = %v, want a *filter.Error", tt.filter, err)
+			}
+			if fe.Pos != tt.pos || !strings.Contains(fe.Msg, tt.msg) {
+				t.Errorf("Query(%q) = %v, want column %d: ...%s...", tt.filter, err, tt.pos, tt.msg)
+			}
+		})
+	}
+}
+
+// TestDueWallClock checks due dates are compared by their date and time where they were
+// set, not in UTC, and that the values never go into the SQL.
+func TestDueWallClock(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, 3, 4, 10, 30, 0, 0, loc)
+	dp := &dates.Parser{Now: func() time.Time { return now }, Loc: loc}
+
+	f, err := filter.Parse("due<=fri")
+	if err != nil {
+		t.Fatal(err)
+	}
+	where, args, err := compileFilter(f, dp)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(where, dueWallClock+" < ?") {
+		t.Errorf("where = %s, want it to compare %s", where, dueWallClock)
+	}
+	if want := []any{"2026-03-07 00:00:00"}; !slices.Equal(args, want) {
+		t.Errorf("args = %q, want %q", args, want)
+	}
+	if strings.Contains(where, "2026") {
+		t.Errorf("where = %s, want the date as an argument", where)
+	}
+}
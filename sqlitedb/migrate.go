@@ -0,0 +1,207 @@
+package sqlitedb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrDBTooNew is returned when the database has been migrated by a newer version
+// of the app than the one currently running.
+var ErrDBTooNew = errors.New("database schema is newer than this version of yata")
+
+type migration struct {
+	version int
+	name    string
+	sql     string
+}
+
+// loadMigrations reads all up-migrations from the provided filesystem. Files must be
+// named like 0001_description.sql, and versions must start at 1 with no gaps.
+func loadMigrations(fsys fs.FS) ([]migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("reading migrations directory: %w", err)
+	}
+
+	var migrations []migration
+	for _, e := range entries {
+		if e.IsDir() || path.Ext(e.Name()) != ".sql" {
+			continue
+		}
+
+		prefix, _, ok := strings.Cut(e.Name(), "_")
+		if !ok {
+			return nil, fmt.Errorf("migration %s: name must be in the format 0001_description.sql", e.Name())
+		}
+
+		v, err := strconv.Atoi(prefix)
+		if err != nil {
+			return nil, fmt.Errorf("migration %s: parsing version: %w", e.Name(), err)
+		}
+
+		b, err := fs.ReadFile(fsys, e.Name())
+		if err != nil {
+			return nil, fmt.Errorf("reading migration %s: %w", e.Name(), err)
+		}
+
+		migrations = append(migrations, migration{version: v, name: e.Name(), sql: string(b)})
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].version < migrations[j].version
+	})
+
+	for i, m := range migrations {
+		if m.version != i+1 {
+			return nil, fmt.Errorf("migration %s: expected version %d", m.name, i+1)
+		}
+	}
+
+	return migrations, nil
+}
+
+// migrate applies all pending migrations, tracking the current version with PRAGMA user_version.
+// Each migration runs in its own transaction along with its version bump, so a failed migration
+// leaves the database at the last successful version. If any migrations are pending on an existing
+// database, a backup copy is made first.
+func (h *Handler) migrate(ctx context.Context) error {
+	current, err := h.schemaVersion(ctx)
+	if err != nil {
+		return err
+	}
+
+	latest := len(h.migrations)
+	if current > latest {
+		return fmt.Errorf("%w: database is at version %d, latest known is %d", ErrDBTooNew, current, latest)
+	}
+
+	if current == latest {
+		slog.Debug("database schema up to date", "version", current)
+		return nil
+	}
+
+	if err := h.backup(ctx, current); err != nil {
+		return fmt.Errorf("backing up database: %w", err)
+	}
+
+	for _, m := range h.migrations[current:] {
+		var applied bool
+		err := retryBusy(ctx, func() error {
+			var err error
+			applied, err = h.applyMigration(ctx, m)
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("applying migration %s: %w", m.name, err)
+		}
+		if applied {
+			slog.Info("applied migration", "version", m.version, "name", m.name)
+		}
+	}
+
+	return nil
+}
+
+// applyMigration runs a migration unless the database is already past it, which happens
+// when another process started at the same time and got there first. It reports whether
+// the migration was run.
+func (h *Handler) applyMigration(ctx context.Context, m migration) (bool, error) {
+	// transactions begin immediately, so no one else can migrate between checking the
+	// version and bumping it
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var current int
+	if err := tx.QueryRowContext(ctx, "PRAGMA user_version;").Scan(&current); err != nil {
+		return false, fmt.Errorf("reading user version: %w", err)
+	}
+	if current >= m.version {
+		return false, nil
+	}
+
+	if _, err := tx.ExecContext(ctx, m.sql); err != nil {
+		return false, fmt.Errorf("executing sql: %w", err)
+	}
+
+	// PRAGMA doesn't accept bound parameters, but the version is always an int we parsed ourselves.
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf("PRAGMA user_version = %d;", m.version)); err != nil {
+		return false, fmt.Errorf("setting user version: %w", err)
+	}
+
+	return true, tx.Commit()
+}
+
+func (h *Handler) schemaVersion(ctx context.Context) (int, error) {
+	var v int
+	if err := h.db.QueryRowContext(ctx, "PRAGMA user_version;").Scan(&v); err != nil {
+		return 0, fmt.Errorf("getting schema version: %w", err)
+	}
+
+	return v, nil
+}
+
+// backup copies the database to a file next to it before migrating, named with the version
+// it was at and a timestamp. Nothing is done for new, empty databases.
+func (h *Handler) backup(ctx context.Context, version int) error {
+	// the main file's size says nothing in WAL mode, where the tables can still be in
+	// the -wal file
+	if _, err := os.Stat(h.dbPath); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("checking database file: %w", err)
+	}
+
+	empty, err := h.isEmpty(ctx)
+	if err != nil {
+		return err
+	}
+
+	if empty {
+		return nil
+	}
+
+	// VACUUM INTO accepts an empty file, so creating it first means another process
+	// starting at the same moment finds it taken and leaves the backup to this one
+	dst := fmt.Sprintf("%s.v%d-%s.bak", h.dbPath, version, time.Now().Format("20060102150405"))
+	f, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if errors.Is(err, os.ErrExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("creating %s: %w", dst, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("creating %s: %w", dst, err)
+	}
+
+	if _, err := h.db.ExecContext(ctx, "VACUUM INTO ?;", dst); err != nil {
+		return fmt.Errorf("copying database to %s: %w", dst, err)
+	}
+
+	slog.Info("backed up database before migrating", "path", dst, "version", version)
+	return nil
+}
+
+func (h *Handler) isEmpty(ctx context.Context) (bool, error) {
+	var n int
+	err := h.db.QueryRowContext(ctx, "SELECT count(*) FROM sqlite_schema WHERE type = 'table';").Scan(&n)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return false, fmt.Errorf("counting tables: %w", err)
+	}
+
+	return n == 0, nil
+}
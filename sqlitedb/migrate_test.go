@@ -0,0 +1,336 @@
+package sqlitedb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+// testMigrations are two small migrations, for tests that don't need the real schema.
+var testMigrations = fstest.MapFS{
+	"0001_init.sql":  {Data: []byte("CREATE TABLE item (id INTEGER PRIMARY KEY, name TEXT NOT NULL);")},
+	"0002_notes.sql": {Data: []byte("ALTER TABLE item ADD COLUMN notes TEXT NOT NULL DEFAULT '';")},
+}
+
+// firstMigration is testMigrations as it was before the second one was added.
+func firstMigration() fstest.MapFS {
+	return fstest.MapFS{"0001_init.sql": testMigrations["0001_init.sql"]}
+}
+
+// openHandler opens the database at path with the migrations in fsys and migrates it.
+func openHandler(t *testing.T, fsys fs.FS, path string) (*Handler, error) {
+	t.Helper()
+	h, err := NewHandler(fsys, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = h.Close() })
+
+	return h, h.migrate(context.Background())
+}
+
+func userVersion(t *testing.T, db *sql.DB) int {
+	t.Helper()
+	var v int
+	if err := db.QueryRow("PRAGMA user_version;").Scan(&v); err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func TestLoadMigrations(t *testing.T) {
+	sql := &fstest.MapFile{Data: []byte("SELECT 1;")}
+
+	tests := []struct {
+		name     string
+		fsys     fstest.MapFS
+		versions int
+		err      string
+	}{
+		{"sorted", fstest.MapFS{"0002_b.sql": sql, "0001_a.sql": sql, "0003_c.sql": sql}, 3, ""},
+		{"other files ignored", fstest.MapFS{"0001_a.sql": sql, "README.md": sql, "0002_b.sql/x.sql": sql}, 1, ""},
+		{"none", fstest.MapFS{}, 0, ""},
+		{"gap", fstest.MapFS{"0001_a.sql": sql, "0003_c.sql": sql}, 0, "migration 0003_c.sql: expected version 2"},
+		{"starts after 1", fstest.MapFS{"0002_b.sql": sql}, 0, "expected version 1"},
+		{"duplicate", fstest.MapFS{"0001_a.sql": sql, "0001_b.sql": sql}, 0, "expected version 2"},
+		{"no description", fstest.MapFS{"0001.sql": sql}, 0, "name must be in the format"},
+		{"bad version", fstest.MapFS{"one_a.sql": sql}, 0, "parsing version"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ms, err := loadMigrations(tt.fsys)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Errorf("loadMigrations = %v, want an error with %q", err, tt.err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("loadMigrations = %v", err)
+			}
+			if len(ms) != tt.versions {
+				t.Fatalf("loaded %d migrations, want %d", len(ms), tt.versions)
+			}
+			for i, m := range ms {
+				if m.version != i+1 || m.sql != "SELECT 1;" {
+					t.Errorf("migration %d = %+v", i, m)
+				}
+			}
+		})
+	}
+
+	ms, err := loadMigrations(os.DirFS("../migrations"))
+	if err != nil {
+		t.Fatalf("loading the real migrations: %v", err)
+	}
+	if len(ms) == 0 {
+		t.Error("no real migrations were loaded")
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.db")
+
+	h, err := openHandler(t, firstMigration(), path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := userVersion(t, h.db); v != 1 {
+		t.Fatalf("version = %d, want 1", v)
+	}
+	if _, err := h.db.Exec("INSERT INTO item (name) VALUES ('kept')"); err != nil {
+		t.Fatal(err)
+	}
+	_ = h.Close()
+
+	h, err = openHandler(t, testMigrations, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := userVersion(t, h.db); v != 2 {
+		t.Errorf("version = %d, want 2", v)
+	}
+
+	var name, notes string
+	if err := h.db.QueryRow("SELECT name, notes FROM item").Scan(&name, &notes); err != nil {
+		t.Fatal(err)
+	}
+	if name != "kept" || notes != "" {
+		t.Errorf("item = %q, %q after migrating", name, notes)
+	}
+
+	// migrating again is a no-op
+	if err := h.migrate(context.Background()); err != nil {
+		t.Errorf("migrating an up to date database: %v", err)
+	}
+}
+
+func TestMigrateFailure(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.db")
+	bad := firstMigration()
+	bad["0002_bad.sql"] = &fstest.MapFile{Data: []byte("ALTER TABLE item ADD COLUMN notes TEXT; ALTER TABLE missing ADD COLUMN x TEXT;")}
+
+	h, err := openHandler(t, bad, path)
+	if err == nil || !strings.Contains(err.Error(), "0002_bad.sql") {
+		t.Fatalf("migrate = %v, want 0002_bad.sql to fail", err)
+	}
+
+	// the failed migration is rolled back as a whole
+	if v := userVersion(t, h.db); v != 1 {
+		t.Errorf("version = %d, want 1", v)
+	}
+	if _, err := h.db.Exec("SELECT notes FROM item"); err == nil {
+		t.Error("the failed migration's first statement was kept")
+	}
+}
+
+func TestDBTooNew(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.db")
+	h, err := openHandler(t, testMigrations, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = h.Close()
+
+	h, err = openHandler(t, firstMigration(), path)
+	if !errors.Is(err, ErrDBTooNew) {
+		t.Fatalf("migrate = %v, want %v", err, ErrDBTooNew)
+	}
+	if v := userVersion(t, h.db); v != 2 {
+		t.Errorf("version = %d, want it left at 2", v)
+	}
+}
+
+func TestBackup(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.db")
+	backups := func() []string {
+		t.Helper()
+		names, err := filepath.Glob(path + ".v*.bak")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return names
+	}
+
+	// a new database has nothing to back up
+	h, err := openHandler(t, firstMigration(), path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b := backups(); len(b) != 0 {
+		t.Fatalf("backups of a new database = %q, want none", b)
+	}
+	if _, err := h.db.Exec("INSERT INTO item (name) VALUES ('kept')"); err != nil {
+		t.Fatal(err)
+	}
+	_ = h.Close()
+
+	h, err = openHandler(t, testMigrations, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = h.Close()
+
+	b := backups()
+	if len(b) != 1 || !strings.HasPrefix(filepath.Base(b[0]), "app.db.v1-") {
+		t.Fatalf("backups = %q, want one of version 1", b)
+	}
+
+	// the backup is the database as it was before migrating
+	db, err := sql.Open("sqlite", b[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if v := userVersion(t, db); v != 1 {
+		t.Errorf("backup version = %d, want 1", v)
+	}
+	var name string
+	if err := db.QueryRow("SELECT name FROM item").Scan(&name); err != nil || name != "kept" {
+		t.Errorf("backed up item = %q, %v", name, err)
+	}
+}
+
+// TestBackupTaken checks a backup file that already exists, as when another process is
+// migrating at the same moment, is left to whoever made it.
+func TestBackupTaken(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.db")
+	h, err := openHandler(t, firstMigration(), path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := h.db.Exec("INSERT INTO item (name) VALUES ('kept')"); err != nil {
+		t.Fatal(err)
+	}
+
+	// the name has the time to the second, so this second and the next are both taken
+	now := time.Now()
+	var taken []string
+	for _, at := range []time.Time{now, now.Add(time.Second)} {
+		name := path + ".v1-" + at.Format("20060102150405") + ".bak"
+		if err := os.WriteFile(name, []byte("taken"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		taken = append(taken, name)
+	}
+
+	if err := h.backup(context.Background(), 1); err != nil {
+		t.Fatalf("backup = %v, want nil when the file is taken", err)
+	}
+
+	for _, name := range taken {
+		b, err := os.ReadFile(name)
+		if err != nil || string(b) != "taken" {
+			t.Errorf("%s = %q, %v, want it untouched", filepath.Base(name), b, err)
+		}
+	}
+	if names, _ := filepath.Glob(path + ".v*.bak"); len(names) != len(taken) {
+		t.Errorf("backups = %q, want only the taken ones", names)
+	}
+}
+
+// TestUpgradeFromBaseline migrates a database made with only the first real migration
+// up to the latest schema.
+func TestUpgradeFromBaseline(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "app.db")
+
+	real := os.DirFS("../migrations")
+	init, err := fs.ReadFile(real, "0001_init.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h, err := openHandler(t, fstest.MapFS{"0001_init.sql": {Data: init}}, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	seed := []string{
+		"INSERT INTO project (id, title) VALUES (1, 'work')",
+		"INSERT INTO project (id, title, parent_project_id) VALUES (2, 'clients', 1)",
+		"INSERT INTO task (id, title, project_id, due_at) VALUES (1, 'invoice', 2, '2026-03-06 17:00:00-05:00')",
+		"INSERT INTO task (id, title, parent_task_id, project_id, complete) VALUES (2, 'send it', 1, 2, true)",
+	}
+	for _, s := range seed {
+		if _, err := h.db.Exec(s); err != nil {
+			t.Fatalf("%s: %v", s, err)
+		}
+	}
+	_ = h.Close()
+
+	h, err = NewHandler(real, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = h.Close() })
+
+	repos, err := h.InitStores(ctx)
+	if err != nil {
+		t.Fatalf("upgrading: %v", err)
+	}
+	if v, want := userVersion(t, h.db), len(h.migrations); v != want {
+		t.Errorf("version = %d, want %d", v, want)
+	}
+	if b, _ := filepath.Glob(path + ".v1-*.bak"); len(b) != 1 {
+		t.Errorf("backups = %q, want one of version 1", b)
+	}
+
+	tasks, err := repos.Tasks.ListAll(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tasks) != 2 {
+		t.Fatalf("tasks = %+v, want the two from before", tasks)
+	}
+	for _, tk := range tasks {
+		if tk.ProjectID == nil || *tk.ProjectID != 2 {
+			t.Errorf("task %d project = %v, want 2", tk.ID, tk.ProjectID)
+		}
+	}
+
+	sub, err := repos.Tasks.Get(ctx, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sub.ParentTaskID == nil || *sub.ParentTaskID != 1 || !sub.Complete {
+		t.Errorf("subtask = %+v", sub)
+	}
+
+	// the upgraded database works with the newest features
+	if err := repos.Tags.AddToTask(ctx, 1, "billing"); err != nil {
+		t.Errorf("tagging an upgraded task: %v", err)
+	}
+	if err := repos.Tasks.AddDependency(ctx, 1, 2); err != nil {
+		t.Errorf("adding a dependency to an upgraded task: %v", err)
+	}
+}
@@ -14,6 +14,22 @@ type Project struct {
 	ParentProjectID *int64
 	CreatedAt       time.Time
 	UpdatedAt       time.Time
+	DeletedAt       *time.Time
+	Position        float64
+}
+
+type SavedView struct {
+	ID        int64
+	Name      string
+	Filter    string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type Tag struct {
+	ID        int64
+	Name      string
+	CreatedAt time.Time
 }
 
 type Task struct {
@@ -25,4 +41,21 @@ type Task struct {
 	DueAt        *time.Time
 	CreatedAt    time.Time
 	UpdatedAt    time.Time
+	Priority     int64
+	Notes        string
+	Recurrence   string
+	DeletedAt    *time.Time
+	Position     float64
+	CompletedAt  *time.Time
+	ArchivedAt   *time.Time
+}
+
+type TaskDependency struct {
+	TaskID      int64
+	BlockedByID int64
+}
+
+type TaskTag struct {
+	TaskID int64
+	TagID  int64
 }
@@ -7,15 +7,17 @@ package sqlitedb
 
 import (
 	"context"
+	"time"
 )
 
 const createProject = `-- name: CreateProject :one
 INSERT INTO project (
     title,
-    parent_project_id
+    parent_project_id,
+    position
 ) VALUES (
-    ?, ?
-) RETURNING id, title, parent_project_id, created_at, updated_at
+    ?1, ?2, (SELECT COALESCE(MAX(position), 0) + 1 FROM project WHERE parent_project_id IS ?2)
+) RETURNING id, title, parent_project_id, created_at, updated_at, deleted_at, position
 `
 
 type CreateProjectParams struct {
@@ -32,6 +34,8 @@ func (q *Queries) CreateProject(ctx context.Context, arg *CreateProjectParams) (
 		&i.ParentProjectID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.Position,
 	)
 	return &i, err
 }
@@ -47,7 +51,7 @@ func (q *Queries) DeleteProject(ctx context.Context, id int64) error {
 }
 
 const getProject = `-- name: GetProject :one
-SELECT id, title, parent_project_id, created_at, updated_at FROM project
+SELECT id, title, parent_project_id, created_at, updated_at, deleted_at, position FROM project
 WHERE id = ? LIMIT 1
 `
 
@@ -60,12 +64,16 @@ func (q *Queries) GetProject(ctx context.Context, id int64) (*Project, error) {
 		&i.ParentProjectID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.Position,
 	)
 	return &i, err
 }
 
 const listAllProjects = `-- name: ListAllProjects :many
-SELECT id, title, parent_project_id, created_at, updated_at FROM project
+SELECT id, title, parent_project_id, created_at, updated_at, deleted_at, position FROM project
+WHERE deleted_at IS NULL
+ORDER BY position, id
 `
 
 func (q *Queries) ListAllProjects(ctx context.Context) ([]*Project, error) {
@@ -83,6 +91,79 @@ func (q *Queries) ListAllProjects(ctx context.Context) ([]*Project, error) {
 			&i.ParentProjectID,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.Position,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listProjectDescendantIDs = `-- name: ListProjectDescendantIDs :many
+WITH RECURSIVE descendants(id) AS (
+    SELECT project.id FROM project
+    WHERE project.id = ?
+    UNION
+    SELECT p.id FROM project p
+    JOIN descendants d ON p.parent_project_id = d.id
+)
+SELECT id FROM descendants
+`
+
+func (q *Queries) ListProjectDescendantIDs(ctx context.Context, id int64) ([]int64, error) {
+	rows, err := q.db.QueryContext(ctx, listProjectDescendantIDs, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []int64{}
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listProjectSiblings = `-- name: ListProjectSiblings :many
+SELECT id, title, parent_project_id, created_at, updated_at, deleted_at, position FROM project
+WHERE parent_project_id IS ? AND deleted_at IS NULL
+ORDER BY position, id
+`
+
+func (q *Queries) ListProjectSiblings(ctx context.Context, parentProjectID *int64) ([]*Project, error) {
+	rows, err := q.db.QueryContext(ctx, listProjectSiblings, parentProjectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*Project{}
+	for rows.Next() {
+		var i Project
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.ParentProjectID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.Position,
 		); err != nil {
 			return nil, err
 		}
@@ -98,8 +179,9 @@ func (q *Queries) ListAllProjects(ctx context.Context) ([]*Project, error) {
 }
 
 const listProjectsByParentProjectID = `-- name: ListProjectsByParentProjectID :many
-SELECT id, title, parent_project_id, created_at, updated_at FROM project
-WHERE parent_project_id = ?
+SELECT id, title, parent_project_id, created_at, updated_at, deleted_at, position FROM project
+WHERE parent_project_id = ? AND deleted_at IS NULL
+ORDER BY position, id
 `
 
 func (q *Queries) ListProjectsByParentProjectID(ctx context.Context, parentProjectID *int64) ([]*Project, error) {
@@ -117,6 +199,100 @@ func (q *Queries) ListProjectsByParentProjectID(ctx context.Context, parentProje
 			&i.ParentProjectID,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.Position,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTasksInProjectTree = `-- name: ListTasksInProjectTree :many
+WITH RECURSIVE descendants(id) AS (
+    SELECT project.id FROM project
+    WHERE project.id = ?
+    UNION
+    SELECT p.id FROM project p
+    JOIN descendants d ON p.parent_project_id = d.id
+)
+SELECT task.id, task.title, task.parent_task_id, task.project_id, task.complete, task.due_at, task.created_at, task.updated_at, task.priority, task.notes, task.recurrence, task.deleted_at, task.position, task.completed_at, task.archived_at FROM task
+WHERE task.project_id IN (SELECT id FROM descendants) AND task.deleted_at IS NULL AND task.archived_at IS NULL
+`
+
+func (q *Queries) ListTasksInProjectTree(ctx context.Context, id int64) ([]*Task, error) {
+	rows, err := q.db.QueryContext(ctx, listTasksInProjectTree, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*Task{}
+	for rows.Next() {
+		var i Task
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.ParentTaskID,
+			&i.ProjectID,
+			&i.Complete,
+			&i.DueAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Priority,
+			&i.Notes,
+			&i.Recurrence,
+			&i.DeletedAt,
+			&i.Position,
+			&i.CompletedAt,
+			&i.ArchivedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTrashedProjects = `-- name: ListTrashedProjects :many
+SELECT project.id, project.title, project.parent_project_id, project.created_at, project.updated_at, project.deleted_at, project.position FROM project
+WHERE project.deleted_at IS NOT NULL
+    AND NOT EXISTS (
+        SELECT 1 FROM project parent
+        WHERE parent.id = project.parent_project_id AND parent.deleted_at = project.deleted_at
+    )
+ORDER BY project.deleted_at DESC
+`
+
+func (q *Queries) ListTrashedProjects(ctx context.Context) ([]*Project, error) {
+	rows, err := q.db.QueryContext(ctx, listTrashedProjects)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*Project{}
+	for rows.Next() {
+		var i Project
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.ParentProjectID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.Position,
 		); err != nil {
 			return nil, err
 		}
@@ -131,24 +307,176 @@ func (q *Queries) ListProjectsByParentProjectID(ctx context.Context, parentProje
 	return items, nil
 }
 
+const nextProjectPosition = `-- name: NextProjectPosition :one
+SELECT CAST(COALESCE(MAX(position), 0) + 1 AS REAL) AS position FROM project
+WHERE parent_project_id IS ?
+`
+
+func (q *Queries) NextProjectPosition(ctx context.Context, parentProjectID *int64) (float64, error) {
+	row := q.db.QueryRowContext(ctx, nextProjectPosition, parentProjectID)
+	var position float64
+	err := row.Scan(&position)
+	return position, err
+}
+
+const purgeProjectsDeletedBefore = `-- name: PurgeProjectsDeletedBefore :execrows
+DELETE FROM project
+WHERE deleted_at < ?
+`
+
+func (q *Queries) PurgeProjectsDeletedBefore(ctx context.Context, deletedAt *time.Time) (int64, error) {
+	result, err := q.db.ExecContext(ctx, purgeProjectsDeletedBefore, deletedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const restoreProjectTree = `-- name: RestoreProjectTree :execrows
+WITH RECURSIVE descendants(id) AS (
+    SELECT project.id FROM project
+    WHERE project.id = ?
+    UNION
+    SELECT p.id FROM project p
+    JOIN descendants d ON p.parent_project_id = d.id
+)
+UPDATE project
+SET deleted_at = NULL
+WHERE id IN (SELECT id FROM descendants) AND deleted_at = ?
+`
+
+type RestoreProjectTreeParams struct {
+	ID        int64
+	DeletedAt *time.Time
+}
+
+func (q *Queries) RestoreProjectTree(ctx context.Context, arg *RestoreProjectTreeParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, restoreProjectTree, arg.ID, arg.DeletedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const restoreTasksInProjectTree = `-- name: RestoreTasksInProjectTree :execrows
+WITH RECURSIVE descendants(id) AS (
+    SELECT project.id FROM project
+    WHERE project.id = ?
+    UNION
+    SELECT p.id FROM project p
+    JOIN descendants d ON p.parent_project_id = d.id
+)
+UPDATE task
+SET deleted_at = NULL
+WHERE project_id IN (SELECT id FROM descendants) AND deleted_at = ?
+`
+
+type RestoreTasksInProjectTreeParams struct {
+	ID        int64
+	DeletedAt *time.Time
+}
+
+func (q *Queries) RestoreTasksInProjectTree(ctx context.Context, arg *RestoreTasksInProjectTreeParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, restoreTasksInProjectTree, arg.ID, arg.DeletedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const setProjectPosition = `-- name: SetProjectPosition :exec
+UPDATE project
+SET position = ?
+WHERE id = ?
+`
+
+type SetProjectPositionParams struct {
+	Position float64
+	ID       int64
+}
+
+func (q *Queries) SetProjectPosition(ctx context.Context, arg *SetProjectPositionParams) error {
+	_, err := q.db.ExecContext(ctx, setProjectPosition, arg.Position, arg.ID)
+	return err
+}
+
+const trashProjectTree = `-- name: TrashProjectTree :execrows
+WITH RECURSIVE descendants(id) AS (
+    SELECT project.id FROM project
+    WHERE project.id = ?
+    UNION
+    SELECT p.id FROM project p
+    JOIN descendants d ON p.parent_project_id = d.id
+)
+UPDATE project
+SET deleted_at = ?
+WHERE id IN (SELECT id FROM descendants) AND deleted_at IS NULL
+`
+
+type TrashProjectTreeParams struct {
+	ID        int64
+	DeletedAt *time.Time
+}
+
+func (q *Queries) TrashProjectTree(ctx context.Context, arg *TrashProjectTreeParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, trashProjectTree, arg.ID, arg.DeletedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const trashTasksInProjectTree = `-- name: TrashTasksInProjectTree :execrows
+WITH RECURSIVE descendants(id) AS (
+    SELECT project.id FROM project
+    WHERE project.id = ?
+    UNION
+    SELECT p.id FROM project p
+    JOIN descendants d ON p.parent_project_id = d.id
+)
+UPDATE task
+SET deleted_at = ?
+WHERE project_id IN (SELECT id FROM descendants) AND deleted_at IS NULL
+`
+
+type TrashTasksInProjectTreeParams struct {
+	ID        int64
+	DeletedAt *time.Time
+}
+
+func (q *Queries) TrashTasksInProjectTree(ctx context.Context, arg *TrashTasksInProjectTreeParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, trashTasksInProjectTree, arg.ID, arg.DeletedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const updateProject = `-- name: UpdateProject :one
 UPDATE project
 SET
     title = ?,
     parent_project_id = ?,
+    position = ?,
     updated_at = CURRENT_TIMESTAMP
 WHERE id = ?
-RETURNING id, title, parent_project_id, created_at, updated_at
+RETURNING id, title, parent_project_id, created_at, updated_at, deleted_at, position
 `
 
 type UpdateProjectParams struct {
 	Title           string
 	ParentProjectID *int64
+	Position        float64
 	ID              int64
 }
 
 func (q *Queries) UpdateProject(ctx context.Context, arg *UpdateProjectParams) (*Project, error) {
-	row := q.db.QueryRowContext(ctx, updateProject, arg.Title, arg.ParentProjectID, arg.ID)
+	row := q.db.QueryRowContext(ctx, updateProject,
+		arg.Title,
+		arg.ParentProjectID,
+		arg.Position,
+		arg.ID,
+	)
 	var i Project
 	err := row.Scan(
 		&i.ID,
@@ -156,6 +484,8 @@ func (q *Queries) UpdateProject(ctx context.Context, arg *UpdateProjectParams) (
 		&i.ParentProjectID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.Position,
 	)
 	return &i, err
 }
@@ -2,6 +2,11 @@ package sqlitedb
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
 
 	"github.com/dsrosen6/yata/models"
 )
@@ -34,9 +39,26 @@ func (pr *ProjectRepo) ListByParentID(ctx context.Context, parentID int64) ([]*m
 	return dbProjectSliceToProjectSlice(dp), nil
 }
 
+func (pr *ProjectRepo) ListSiblings(ctx context.Context, parentID *int64) ([]*models.Project, error) {
+	dp, err := pr.q.ListProjectSiblings(ctx, parentID)
+	if err != nil {
+		return nil, err
+	}
+
+	return dbProjectSliceToProjectSlice(dp), nil
+}
+
+// ListDescendantIDs returns the IDs of a project and all of its sub-projects, at any depth.
+func (pr *ProjectRepo) ListDescendantIDs(ctx context.Context, id int64) ([]int64, error) {
+	return pr.q.ListProjectDescendantIDs(ctx, id)
+}
+
 func (pr *ProjectRepo) Get(ctx context.Context, id int64) (*models.Project, error) {
 	d, err := pr.q.GetProject(ctx, id)
 	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("project %d: %w", id, models.ErrNotFound)
+		}
 		return nil, err
 	}
 
@@ -52,8 +74,15 @@ func (pr *ProjectRepo) Create(ctx context.Context, p *models.Project) (*models.P
 	return dbProjectToProject(d), nil
 }
 
+// Update returns models.ErrProjectCycle if the project would be moved under itself. The
+// check and the update are one transaction, so a concurrent move can't make a cycle.
 func (pr *ProjectRepo) Update(ctx context.Context, p *models.Project) (*models.Project, error) {
-	d, err := pr.q.UpdateProject(ctx, projectToUpdateParams(p))
+	var d *Project
+	err := pr.q.inTx(ctx, func(q *Queries) error {
+		var err error
+		d, err = applyProjectUpdate(ctx, q, p)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -61,10 +90,89 @@ func (pr *ProjectRepo) Update(ctx context.Context, p *models.Project) (*models.P
 	return dbProjectToProject(d), nil
 }
 
+func applyProjectUpdate(ctx context.Context, q *Queries, p *models.Project) (*Project, error) {
+	if p.ParentID != nil {
+		ids, err := q.ListProjectDescendantIDs(ctx, p.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		if slices.Contains(ids, *p.ParentID) {
+			return nil, models.ErrProjectCycle
+		}
+	}
+
+	cur, err := q.GetProject(ctx, p.ID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("project %d: %w", p.ID, models.ErrNotFound)
+		}
+		return nil, err
+	}
+
+	// like tasks, a project under a new parent goes after the sub-projects already there
+	params := projectToUpdateParams(p)
+	if !models.SameID(cur.ParentProjectID, p.ParentID) && cur.Position == p.Position {
+		if params.Position, err = q.NextProjectPosition(ctx, p.ParentID); err != nil {
+			return nil, err
+		}
+	}
+
+	return q.UpdateProject(ctx, params)
+}
+
+func (pr *ProjectRepo) SetPosition(ctx context.Context, id int64, position float64) error {
+	return pr.q.SetProjectPosition(ctx, &SetProjectPositionParams{ID: id, Position: position})
+}
+
 func (pr *ProjectRepo) Delete(ctx context.Context, id int64) error {
 	return pr.q.DeleteProject(ctx, id)
 }
 
+// Trash stamps the project tree and its tasks with the same time, which is what Restore
+// uses to tell them apart from things trashed earlier. Both happen in one transaction, so
+// a failure can't leave tasks trashed in a project that isn't.
+func (pr *ProjectRepo) Trash(ctx context.Context, id int64) error {
+	now := time.Now().UTC()
+	return pr.q.inTx(ctx, func(q *Queries) error {
+		if _, err := q.TrashTasksInProjectTree(ctx, &TrashTasksInProjectTreeParams{ID: id, DeletedAt: &now}); err != nil {
+			return err
+		}
+
+		_, err := q.TrashProjectTree(ctx, &TrashProjectTreeParams{ID: id, DeletedAt: &now})
+		return err
+	})
+}
+
+func (pr *ProjectRepo) Restore(ctx context.Context, p *models.Project) error {
+	if p.DeletedAt == nil {
+		return nil
+	}
+
+	return pr.q.inTx(ctx, func(q *Queries) error {
+		if _, err := q.RestoreProjectTree(ctx, &RestoreProjectTreeParams{ID: p.ID, DeletedAt: p.DeletedAt}); err != nil {
+			return err
+		}
+
+		_, err := q.RestoreTasksInProjectTree(ctx, &RestoreTasksInProjectTreeParams{ID: p.ID, DeletedAt: p.DeletedAt})
+		return err
+	})
+}
+
+func (pr *ProjectRepo) ListTrashed(ctx context.Context) ([]*models.Project, error) {
+	dp, err := pr.q.ListTrashedProjects(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return dbProjectSliceToProjectSlice(dp), nil
+}
+
+func (pr *ProjectRepo) PurgeDeletedBefore(ctx context.Context, before time.Time) (int64, error) {
+	before = before.UTC()
+	return pr.q.PurgeProjectsDeletedBefore(ctx, &before)
+}
+
 func projectToCreateParams(p *models.Project) *CreateProjectParams {
 	return &CreateProjectParams{
 		Title:           p.Title,
@@ -77,6 +185,7 @@ func projectToUpdateParams(p *models.Project) *UpdateProjectParams {
 		ID:              p.ID,
 		Title:           p.Title,
 		ParentProjectID: p.ParentID,
+		Position:        p.Position,
 	}
 }
 
@@ -96,5 +205,7 @@ func dbProjectToProject(d *Project) *models.Project {
 		ParentID:  d.ParentProjectID,
 		CreatedAt: d.CreatedAt,
 		UpdatedAt: d.UpdatedAt,
+		DeletedAt: d.DeletedAt,
+		Position:  d.Position,
 	}
 }
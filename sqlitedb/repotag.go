@@ -0,0 +1,101 @@
+package sqlitedb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/dsrosen6/yata/models"
+)
+
+type TagRepo struct {
+	q *Queries
+}
+
+func NewTagRepo(q *Queries) *TagRepo {
+	return &TagRepo{
+		q: q,
+	}
+}
+
+func (tr *TagRepo) ListAll(ctx context.Context) ([]*models.Tag, error) {
+	dt, err := tr.q.ListAllTags(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return dbTagSliceToTagSlice(dt), nil
+}
+
+func (tr *TagRepo) ListByTaskID(ctx context.Context, taskID int64) ([]*models.Tag, error) {
+	dt, err := tr.q.ListTagsByTaskID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	return dbTagSliceToTagSlice(dt), nil
+}
+
+func (tr *TagRepo) ListAllByTaskID(ctx context.Context) (map[int64][]*models.Tag, error) {
+	rows, err := tr.q.ListAllTaskTags(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tags := make(map[int64][]*models.Tag)
+	for _, r := range rows {
+		tags[r.TaskID] = append(tags[r.TaskID], &models.Tag{
+			ID:        r.ID,
+			Name:      r.Name,
+			CreatedAt: r.CreatedAt,
+		})
+	}
+
+	return tags, nil
+}
+
+func (tr *TagRepo) AddToTask(ctx context.Context, taskID int64, name string) error {
+	t, err := tr.q.CreateTag(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	return tr.q.AddTagToTask(ctx, &AddTagToTaskParams{TaskID: taskID, TagID: t.ID})
+}
+
+func (tr *TagRepo) RemoveFromTask(ctx context.Context, taskID int64, name string) error {
+	t, err := tr.q.GetTagByName(ctx, name)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return err
+	}
+
+	if err := tr.q.RemoveTagFromTask(ctx, &RemoveTagFromTaskParams{TaskID: taskID, TagID: t.ID}); err != nil {
+		return err
+	}
+
+	return tr.q.DeleteUnusedTags(ctx)
+}
+
+func (tr *TagRepo) Delete(ctx context.Context, id int64) error {
+	return tr.q.DeleteTag(ctx, id)
+}
+
+func dbTagSliceToTagSlice(ds []*Tag) []*models.Tag {
+	var t []*models.Tag
+	for _, d := range ds {
+		t = append(t, dbTagToTag(d))
+	}
+
+	return t
+}
+
+func dbTagToTag(d *Tag) *models.Tag {
+	return &models.Tag{
+		ID:        d.ID,
+		Name:      d.Name,
+		CreatedAt: d.CreatedAt,
+	}
+}
@@ -2,17 +2,27 @@ package sqlitedb
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
 
+	"github.com/dsrosen6/yata/dates"
+	"github.com/dsrosen6/yata/filter"
 	"github.com/dsrosen6/yata/models"
 )
 
 type TaskRepo struct {
-	q *Queries
+	q     *Queries
+	dates *dates.Parser
 }
 
 func NewTaskRepo(q *Queries) *TaskRepo {
 	return &TaskRepo{
-		q: q,
+		q:     q,
+		dates: dates.NewParser(),
 	}
 }
 
@@ -34,6 +44,16 @@ func (tr *TaskRepo) ListByProjectID(ctx context.Context, projectID int64) ([]*mo
 	return dbTaskSliceToTaskSlice(dt), nil
 }
 
+// ListByProjectTree returns the tasks in a project and all of its sub-projects.
+func (tr *TaskRepo) ListByProjectTree(ctx context.Context, projectID int64) ([]*models.Task, error) {
+	dt, err := tr.q.ListTasksInProjectTree(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	return dbTaskSliceToTaskSlice(dt), nil
+}
+
 func (tr *TaskRepo) ListByParentID(ctx context.Context, parentID int64) ([]*models.Task, error) {
 	dt, err := tr.q.ListTasksByParentTaskID(ctx, &parentID)
 	if err != nil {
@@ -43,9 +63,21 @@ func (tr *TaskRepo) ListByParentID(ctx context.Context, parentID int64) ([]*mode
 	return dbTaskSliceToTaskSlice(dt), nil
 }
 
+func (tr *TaskRepo) ListSiblings(ctx context.Context, parentID, projectID *int64) ([]*models.Task, error) {
+	dt, err := tr.q.ListTaskSiblings(ctx, &ListTaskSiblingsParams{ParentTaskID: parentID, ProjectID: projectID})
+	if err != nil {
+		return nil, err
+	}
+
+	return dbTaskSliceToTaskSlice(dt), nil
+}
+
 func (tr *TaskRepo) Get(ctx context.Context, id int64) (*models.Task, error) {
 	d, err := tr.q.GetTask(ctx, id)
 	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("task %d: %w", id, models.ErrNotFound)
+		}
 		return nil, err
 	}
 
@@ -53,7 +85,13 @@ func (tr *TaskRepo) Get(ctx context.Context, id int64) (*models.Task, error) {
 }
 
 func (tr *TaskRepo) Create(ctx context.Context, t *models.Task) (*models.Task, error) {
-	d, err := tr.q.CreateTask(ctx, taskToCreateParams(t))
+	p := taskToCreateParams(t)
+	if t.Complete && p.CompletedAt == nil {
+		now := time.Now().UTC()
+		p.CompletedAt = &now
+	}
+
+	d, err := tr.q.CreateTask(ctx, p)
 	if err != nil {
 		return nil, err
 	}
@@ -61,8 +99,20 @@ func (tr *TaskRepo) Create(ctx context.Context, t *models.Task) (*models.Task, e
 	return dbTaskToTask(d), nil
 }
 
+// Update puts a task that's given new siblings, by changing its parent or the project of a
+// top-level task, after the ones already there. A task that's also given a new position
+// keeps it, which is how a move is undone.
+//
+// It also keeps the completion time in step with completion: it's set when a task is
+// completed, unless the task already has one to restore, and cleared along with the
+// archive time when it's reopened.
 func (tr *TaskRepo) Update(ctx context.Context, t *models.Task) (*models.Task, error) {
-	d, err := tr.q.UpdateTask(ctx, taskToUpdateParams(t))
+	var d *Task
+	err := tr.q.inTx(ctx, func(q *Queries) error {
+		var err error
+		d, err = applyTaskUpdate(ctx, q, t)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -70,10 +120,222 @@ func (tr *TaskRepo) Update(ctx context.Context, t *models.Task) (*models.Task, e
 	return dbTaskToTask(d), nil
 }
 
+// applyTaskUpdate reads the task's current state and updates it, which Update does in one
+// transaction so nothing can change in between.
+func applyTaskUpdate(ctx context.Context, q *Queries, t *models.Task) (*Task, error) {
+	cur, err := q.GetTask(ctx, t.ID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("task %d: %w", t.ID, models.ErrNotFound)
+		}
+		return nil, err
+	}
+
+	p := taskToUpdateParams(t)
+	switch {
+	case !t.Complete:
+		p.CompletedAt, p.ArchivedAt = nil, nil
+	case cur.Complete && cur.CompletedAt != nil:
+		p.CompletedAt = cur.CompletedAt
+	case p.CompletedAt == nil:
+		now := time.Now().UTC()
+		p.CompletedAt = &now
+	}
+
+	moved := !models.SameID(cur.ParentTaskID, t.ParentTaskID) || (t.ParentTaskID == nil && !models.SameID(cur.ProjectID, t.ProjectID))
+	if moved && cur.Position == t.Position {
+		if p.Position, err = q.NextTaskPosition(ctx, &NextTaskPositionParams{ParentTaskID: t.ParentTaskID, ProjectID: t.ProjectID}); err != nil {
+			return nil, err
+		}
+	}
+
+	return q.UpdateTask(ctx, p)
+}
+
+// SetPosition changes only where a task sorts among its siblings, so it isn't counted as
+// an update.
+func (tr *TaskRepo) SetPosition(ctx context.Context, id int64, position float64) error {
+	return tr.q.SetTaskPosition(ctx, &SetTaskPositionParams{ID: id, Position: position})
+}
+
 func (tr *TaskRepo) Delete(ctx context.Context, id int64) error {
 	return tr.q.DeleteTask(ctx, id)
 }
 
+func (tr *TaskRepo) Trash(ctx context.Context, id int64) error {
+	now := time.Now().UTC()
+	_, err := tr.q.TrashTaskTree(ctx, &TrashTaskTreeParams{ID: id, DeletedAt: &now})
+	return err
+}
+
+// Restore only brings back subtasks with the same deletion time as the task, so ones
+// trashed separately before it stay in the trash.
+func (tr *TaskRepo) Restore(ctx context.Context, t *models.Task) error {
+	if t.DeletedAt == nil {
+		return nil
+	}
+
+	_, err := tr.q.RestoreTaskTree(ctx, &RestoreTaskTreeParams{ID: t.ID, DeletedAt: t.DeletedAt})
+	return err
+}
+
+func (tr *TaskRepo) ListTrashed(ctx context.Context) ([]*models.Task, error) {
+	dt, err := tr.q.ListTrashedTasks(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return dbTaskSliceToTaskSlice(dt), nil
+}
+
+func (tr *TaskRepo) PurgeDeletedBefore(ctx context.Context, before time.Time) (int64, error) {
+	before = before.UTC()
+	return tr.q.PurgeTasksDeletedBefore(ctx, &before)
+}
+
+func (tr *TaskRepo) ListCompleted(ctx context.Context) ([]*models.Task, error) {
+	dt, err := tr.q.ListCompletedTasks(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return dbTaskSliceToTaskSlice(dt), nil
+}
+
+func (tr *TaskRepo) Archive(ctx context.Context, completedBefore, at time.Time) (int64, error) {
+	completedBefore, at = completedBefore.UTC(), at.UTC()
+	return tr.q.ArchiveTasksCompletedBefore(ctx, &ArchiveTasksCompletedBeforeParams{
+		ArchivedAt:      &at,
+		CompletedBefore: &completedBefore,
+	})
+}
+
+func (tr *TaskRepo) Unarchive(ctx context.Context, at time.Time) (int64, error) {
+	at = at.UTC()
+	return tr.q.UnarchiveTasks(ctx, &at)
+}
+
+func (tr *TaskRepo) Search(ctx context.Context, query string, limit int) ([]*models.TaskMatch, error) {
+	match := searchQuery(query)
+	if match == "" {
+		return nil, nil
+	}
+
+	rows, err := tr.q.SearchTasks(ctx, &SearchTasksParams{
+		MarkStart:  models.MatchStart,
+		MarkEnd:    models.MatchEnd,
+		Query:      match,
+		MaxResults: int64(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	matches := make([]*models.TaskMatch, len(rows))
+	for i, r := range rows {
+		matches[i] = &models.TaskMatch{
+			Task: dbTaskToTask(&Task{
+				ID:           r.ID,
+				Title:        r.Title,
+				ParentTaskID: r.ParentTaskID,
+				ProjectID:    r.ProjectID,
+				Complete:     r.Complete,
+				DueAt:        r.DueAt,
+				CreatedAt:    r.CreatedAt,
+				UpdatedAt:    r.UpdatedAt,
+				Priority:     r.Priority,
+				Notes:        r.Notes,
+				Recurrence:   r.Recurrence,
+				DeletedAt:    r.DeletedAt,
+				Position:     r.Position,
+				CompletedAt:  r.CompletedAt,
+				ArchivedAt:   r.ArchivedAt,
+			}),
+			Title: r.HighlightedTitle,
+		}
+	}
+
+	return matches, nil
+}
+
+func (tr *TaskRepo) Query(ctx context.Context, f *filter.Filter) ([]*models.Task, error) {
+	where, args, err := compileFilter(f, tr.dates)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := tr.q.db.QueryContext(ctx, queryTasks+where, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	dt, err := scanTasks(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	return dbTaskSliceToTaskSlice(dt), nil
+}
+
+func (tr *TaskRepo) ListDependencies(ctx context.Context) (map[int64][]int64, error) {
+	deps, err := tr.q.ListAllTaskDependencies(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	blockers := make(map[int64][]int64)
+	for _, d := range deps {
+		blockers[d.TaskID] = append(blockers[d.TaskID], d.BlockedByID)
+	}
+
+	return blockers, nil
+}
+
+func (tr *TaskRepo) ListBlockedIDs(ctx context.Context) ([]int64, error) {
+	return tr.q.ListBlockedTaskIDs(ctx)
+}
+
+// AddDependency marks a task as blocked by another. It returns models.ErrDependencyCycle
+// if the blocker is the task itself or is already blocked by it, at any depth. The check
+// and the insert are one transaction, so two adds at once can't make a cycle between them.
+func (tr *TaskRepo) AddDependency(ctx context.Context, taskID, blockedByID int64) error {
+	if taskID == blockedByID {
+		return models.ErrDependencyCycle
+	}
+
+	return tr.q.inTx(ctx, func(q *Queries) error {
+		ids, err := q.ListTransitiveBlockerIDs(ctx, blockedByID)
+		if err != nil {
+			return err
+		}
+
+		if slices.Contains(ids, taskID) {
+			return models.ErrDependencyCycle
+		}
+
+		return q.AddTaskDependency(ctx, &AddTaskDependencyParams{TaskID: taskID, BlockedByID: blockedByID})
+	})
+}
+
+func (tr *TaskRepo) RemoveDependency(ctx context.Context, taskID, blockedByID int64) error {
+	return tr.q.RemoveTaskDependency(ctx, &RemoveTaskDependencyParams{TaskID: taskID, BlockedByID: blockedByID})
+}
+
+func (tr *TaskRepo) ClearDependencies(ctx context.Context, taskID int64) error {
+	return tr.q.RemoveTaskDependenciesByTaskID(ctx, taskID)
+}
+
+// searchQuery turns what the user typed into an FTS5 query. Each word is quoted, so
+// characters FTS5 treats as syntax are searched for as text, and made a prefix match.
+func searchQuery(q string) string {
+	var terms []string
+	for _, w := range strings.Fields(q) {
+		terms = append(terms, `"`+strings.ReplaceAll(w, `"`, `""`)+`"*`)
+	}
+
+	return strings.Join(terms, " ")
+}
+
 func taskToCreateParams(t *models.Task) *CreateTaskParams {
 	return &CreateTaskParams{
 		Title:        t.Title,
@@ -81,6 +343,10 @@ func taskToCreateParams(t *models.Task) *CreateTaskParams {
 		ProjectID:    t.ProjectID,
 		Complete:     t.Complete,
 		DueAt:        t.DueAt,
+		Priority:     int64(t.Priority),
+		Notes:        t.Notes,
+		Recurrence:   t.Recurrence,
+		CompletedAt:  t.CompletedAt,
 	}
 }
 
@@ -92,6 +358,12 @@ func taskToUpdateParams(t *models.Task) *UpdateTaskParams {
 		Title:        t.Title,
 		Complete:     t.Complete,
 		DueAt:        t.DueAt,
+		Priority:     int64(t.Priority),
+		Notes:        t.Notes,
+		Recurrence:   t.Recurrence,
+		Position:     t.Position,
+		CompletedAt:  t.CompletedAt,
+		ArchivedAt:   t.ArchivedAt,
 	}
 }
 
@@ -112,7 +384,14 @@ func dbTaskToTask(d *Task) *models.Task {
 		ProjectID:    d.ProjectID,
 		Complete:     d.Complete,
 		DueAt:        d.DueAt,
+		Priority:     models.Priority(d.Priority),
+		Notes:        d.Notes,
+		Recurrence:   d.Recurrence,
 		CreatedAt:    d.CreatedAt,
 		UpdatedAt:    d.UpdatedAt,
+		DeletedAt:    d.DeletedAt,
+		Position:     d.Position,
+		CompletedAt:  d.CompletedAt,
+		ArchivedAt:   d.ArchivedAt,
 	}
 }
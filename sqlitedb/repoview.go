@@ -0,0 +1,73 @@
+package sqlitedb
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dsrosen6/yata/filter"
+	"github.com/dsrosen6/yata/models"
+)
+
+type ViewRepo struct {
+	q *Queries
+}
+
+func NewViewRepo(q *Queries) *ViewRepo {
+	return &ViewRepo{
+		q: q,
+	}
+}
+
+func (vr *ViewRepo) ListAll(ctx context.Context) ([]*models.View, error) {
+	dv, err := vr.q.ListSavedViews(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	views := make([]*models.View, len(dv))
+	for i, d := range dv {
+		views[i] = dbViewToView(d)
+	}
+
+	return views, nil
+}
+
+func (vr *ViewRepo) Create(ctx context.Context, v *models.View) (*models.View, error) {
+	if _, err := filter.Parse(v.Filter); err != nil {
+		return nil, fmt.Errorf("invalid filter: %w", err)
+	}
+
+	d, err := vr.q.CreateSavedView(ctx, &CreateSavedViewParams{Name: v.Name, Filter: v.Filter})
+	if err != nil {
+		return nil, err
+	}
+
+	return dbViewToView(d), nil
+}
+
+func (vr *ViewRepo) Update(ctx context.Context, v *models.View) (*models.View, error) {
+	if _, err := filter.Parse(v.Filter); err != nil {
+		return nil, fmt.Errorf("invalid filter: %w", err)
+	}
+
+	d, err := vr.q.UpdateSavedView(ctx, &UpdateSavedViewParams{ID: v.ID, Name: v.Name, Filter: v.Filter})
+	if err != nil {
+		return nil, err
+	}
+
+	return dbViewToView(d), nil
+}
+
+func (vr *ViewRepo) Delete(ctx context.Context, id int64) error {
+	return vr.q.DeleteSavedView(ctx, id)
+}
+
+func dbViewToView(d *SavedView) *models.View {
+	return &models.View{
+		ID:        d.ID,
+		Name:      d.Name,
+		Filter:    d.Filter,
+		CreatedAt: d.CreatedAt,
+		UpdatedAt: d.UpdatedAt,
+	}
+}
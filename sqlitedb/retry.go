@@ -0,0 +1,102 @@
+package sqlitedb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"math/rand/v2"
+	"time"
+
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+const (
+	// busyTimeout is how long a connection waits on another's lock before failing with
+	// SQLITE_BUSY.
+	busyTimeout = 5 * time.Second
+
+	// busyRetries is how many more times a statement that still failed with SQLITE_BUSY
+	// is tried, waiting a little longer each time.
+	busyRetries  = 5
+	retryBackoff = 50 * time.Millisecond
+)
+
+// retryDB runs the queries' statements on the pool, trying them again if they fail
+// because another connection or process held the database lock for longer than the busy
+// timeout. Statements outside a transaction commit on their own, so one that failed with
+// SQLITE_BUSY changed nothing and is safe to run again.
+type retryDB struct {
+	db *sql.DB
+}
+
+func (r *retryDB) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	var res sql.Result
+	err := retryBusy(ctx, func() error {
+		var err error
+		res, err = r.db.ExecContext(ctx, query, args...)
+		return err
+	})
+	return res, err
+}
+
+func (r *retryDB) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	var stmt *sql.Stmt
+	err := retryBusy(ctx, func() error {
+		var err error
+		stmt, err = r.db.PrepareContext(ctx, query)
+		return err
+	})
+	return stmt, err
+}
+
+func (r *retryDB) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	var rows *sql.Rows
+	err := retryBusy(ctx, func() error {
+		var err error
+		rows, err = r.db.QueryContext(ctx, query, args...)
+		return err
+	})
+	return rows, err
+}
+
+func (r *retryDB) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	var row *sql.Row
+	// the row's error is only kept, not returned, but Err has it without scanning
+	_ = retryBusy(ctx, func() error {
+		row = r.db.QueryRowContext(ctx, query, args...)
+		return row.Err()
+	})
+	return row
+}
+
+// retryBusy runs fn until it succeeds, fails with anything but SQLITE_BUSY, or runs out
+// of retries. The backoff is jittered so processes waiting on each other don't retry in
+// lockstep.
+func retryBusy(ctx context.Context, fn func() error) error {
+	wait := retryBackoff
+	for attempt := 0; ; attempt++ {
+		err := fn()
+		if err == nil || !isBusy(err) || attempt == busyRetries {
+			return err
+		}
+
+		slog.Debug("database busy, retrying", "attempt", attempt+1, "error", err)
+		t := time.NewTimer(wait/2 + rand.N(wait))
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return err
+		case <-t.C:
+		}
+		wait *= 2
+	}
+}
+
+// isBusy reports whether err is SQLITE_BUSY, "database is locked", including the extended
+// codes like SQLITE_BUSY_SNAPSHOT.
+func isBusy(err error) bool {
+	var se *sqlite.Error
+	return errors.As(err, &se) && se.Code()&0xff == sqlite3.SQLITE_BUSY
+}
@@ -0,0 +1,271 @@
+package sqlitedb
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/dsrosen6/yata/models"
+)
+
+const (
+	// childDBEnv tells a re-run of the test binary to write to the database at its path,
+	// as another process would.
+	childDBEnv = "YATA_SQLITEDB_CHILD_DB"
+
+	// tasksPerWorker is how many tasks each writing goroutine makes.
+	tasksPerWorker = 30
+)
+
+// busyError makes SQLite fail with SQLITE_BUSY, by writing while another connection
+// holds the write lock, without waiting on the busy timeout.
+func busyError(t *testing.T) error {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "busy.db")
+	open := func() *sql.DB {
+		db, err := sql.Open("sqlite", path+"?_pragma=busy_timeout(0)&_pragma=journal_mode(WAL)")
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { _ = db.Close() })
+		return db
+	}
+
+	holder, writer := open(), open()
+	if _, err := holder.Exec("CREATE TABLE t (x INTEGER)"); err != nil {
+		t.Fatal(err)
+	}
+
+	tx, err := holder.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	if _, err := tx.Exec("INSERT INTO t VALUES (1)"); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = writer.Exec("INSERT INTO t VALUES (2)")
+	if err == nil {
+		t.Fatal("writing while another connection held the lock succeeded")
+	}
+	return err
+}
+
+func TestIsBusy(t *testing.T) {
+	busy := busyError(t)
+
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "other.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	_, constraint := db.Exec("CREATE TABLE t (x INTEGER UNIQUE); INSERT INTO t VALUES (1); INSERT INTO t VALUES (1)")
+	if constraint == nil {
+		t.Fatal("inserting a duplicate succeeded")
+	}
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"busy", busy, true},
+		{"wrapped busy", fmt.Errorf("creating task: %w", busy), true},
+		{"constraint", constraint, false},
+		{"same text", errors.New(busy.Error()), false},
+		{"no rows", sql.ErrNoRows, false},
+		{"nil", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isBusy(tt.err); got != tt.want {
+				t.Errorf("isBusy(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryBusy(t *testing.T) {
+	busy := busyError(t)
+	other := errors.New("other")
+
+	// failing returns fn that fails with err the first n times it's called
+	failing := func(n int, err error) (func() error, *int) {
+		calls := 0
+		return func() error {
+			calls++
+			if calls <= n {
+				return err
+			}
+			return nil
+		}, &calls
+	}
+
+	t.Run("succeeds after busy", func(t *testing.T) {
+		fn, calls := failing(2, busy)
+		if err := retryBusy(context.Background(), fn); err != nil {
+			t.Errorf("retryBusy = %v, want nil", err)
+		}
+		if *calls != 3 {
+			t.Errorf("called %d times, want 3", *calls)
+		}
+	})
+
+	t.Run("other errors aren't retried", func(t *testing.T) {
+		fn, calls := failing(1, other)
+		if err := retryBusy(context.Background(), fn); !errors.Is(err, other) {
+			t.Errorf("retryBusy = %v, want %v", err, other)
+		}
+		if *calls != 1 {
+			t.Errorf("called %d times, want 1", *calls)
+		}
+	})
+
+	t.Run("gives up", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("waits out every backoff")
+		}
+
+		fn, calls := failing(busyRetries+10, busy)
+		if err := retryBusy(context.Background(), fn); !isBusy(err) {
+			t.Errorf("retryBusy = %v, want SQLITE_BUSY", err)
+		}
+		if *calls != busyRetries+1 {
+			t.Errorf("called %d times, want %d", *calls, busyRetries+1)
+		}
+	})
+
+	t.Run("stops when canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		fn, calls := failing(busyRetries+10, busy)
+		if err := retryBusy(ctx, fn); !isBusy(err) {
+			t.Errorf("retryBusy = %v, want SQLITE_BUSY", err)
+		}
+		if *calls != 1 {
+			t.Errorf("called %d times, want 1", *calls)
+		}
+	})
+}
+
+// TestConcurrentWrites writes to one database from several goroutines sharing a handler
+// and from other processes with their own, the way the TUI, CLI and API server do, and
+// checks none of them sees SQLITE_BUSY.
+func TestConcurrentWrites(t *testing.T) {
+	const (
+		workers  = 6
+		children = 2
+	)
+
+	if path := os.Getenv(childDBEnv); path != "" {
+		writeConcurrently(t, openRepos(t, path), "child", workers)
+		return
+	}
+
+	path := filepath.Join(t.TempDir(), "app.db")
+	repos := openRepos(t, path)
+
+	// the children start writing while the goroutines do
+	var cmds []*exec.Cmd
+	outs := make([]bytes.Buffer, children)
+	for i := range children {
+		cmd := exec.Command(os.Args[0], "-test.run=^TestConcurrentWrites$", "-test.count=1")
+		cmd.Env = append(os.Environ(), childDBEnv+"="+path)
+		cmd.Stdout, cmd.Stderr = &outs[i], &outs[i]
+		if err := cmd.Start(); err != nil {
+			t.Fatal(err)
+		}
+		cmds = append(cmds, cmd)
+	}
+
+	writeConcurrently(t, repos, "parent", workers)
+
+	for i, cmd := range cmds {
+		if err := cmd.Wait(); err != nil {
+			t.Errorf("child %d: %v\n%s", i, err, outs[i].String())
+		}
+	}
+
+	tasks, err := repos.Tasks.ListAll(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (1 + children) * workers * tasksPerWorker; len(tasks) != want {
+		t.Errorf("%d tasks, want %d", len(tasks), want)
+	}
+	for _, tk := range tasks {
+		if !tk.Complete {
+			t.Errorf("task %d %q wasn't completed", tk.ID, tk.Title)
+		}
+	}
+}
+
+// writeConcurrently creates, renames and completes tasks from several goroutines at once.
+func writeConcurrently(t *testing.T, repos *models.AllRepos, name string, workers int) {
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for w := range workers {
+		wg.Go(func() {
+			for i := range tasksPerWorker {
+				if err := writeTask(ctx, repos, fmt.Sprintf("%s %d/%d", name, w, i)); err != nil {
+					if isBusy(err) {
+						t.Errorf("SQLITE_BUSY reached the caller: %v", err)
+					} else {
+						t.Error(err)
+					}
+					return
+				}
+			}
+		})
+	}
+	wg.Wait()
+}
+
+func writeTask(ctx context.Context, repos *models.AllRepos, title string) error {
+	created, err := repos.Tasks.Create(ctx, &models.Task{Title: title})
+	if err != nil {
+		return fmt.Errorf("creating %s: %w", title, err)
+	}
+
+	created.Title += " renamed"
+	updated, err := repos.Tasks.Update(ctx, created)
+	if err != nil {
+		return fmt.Errorf("renaming %s: %w", title, err)
+	}
+
+	if err := repos.Tags.AddToTask(ctx, updated.ID, "load"); err != nil {
+		return fmt.Errorf("tagging %s: %w", title, err)
+	}
+
+	// completing is a transaction, rather than one statement at a time
+	if _, err := repos.CompleteTask(ctx, updated, false); err != nil {
+		return fmt.Errorf("completing %s: %w", title, err)
+	}
+	return nil
+}
+
+func openRepos(t *testing.T, path string) *models.AllRepos {
+	t.Helper()
+	h, err := NewHandler(os.DirFS("../migrations"), path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = h.Close() })
+
+	repos, err := h.InitStores(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return repos
+}
@@ -4,42 +4,68 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"io/fs"
+	"net/url"
 
 	"github.com/dsrosen6/yata/models"
 	_ "modernc.org/sqlite"
 )
 
 type Handler struct {
-	embedSchema string
-	db          *sql.DB
-	queries     *Queries
+	migrations []migration
+	dbPath     string
+	db         *sql.DB
+	queries    *Queries
 }
 
-func NewHandler(embedSchema, dbPath string) (*Handler, error) {
-	db, err := sql.Open("sqlite", dbPath)
+// NewHandler opens the database at dbPath. The migrations filesystem should contain
+// the ordered up-migrations, which are applied by InitStores.
+func NewHandler(migrations fs.FS, dbPath string) (*Handler, error) {
+	ms, err := loadMigrations(migrations)
 	if err != nil {
-		return nil, fmt.Errorf("opening db: %w", err)
+		return nil, fmt.Errorf("loading migrations: %w", err)
 	}
 
-	// Enable foreign key constraints
-	if _, err := db.Exec("PRAGMA foreign_keys = ON;"); err != nil {
-		return nil, fmt.Errorf("enabling foreign keys: %w", err)
+	db, err := sql.Open("sqlite", dsn(dbPath))
+	if err != nil {
+		return nil, fmt.Errorf("opening db: %w", err)
 	}
 
-	q := New(db)
+	// one connection does everything, which SQLite would serialize anyway, so PRAGMA
+	// data_version on it only changes when another process writes
+	db.SetMaxOpenConns(1)
+
+	q := New(&retryDB{db: db})
 	return &Handler{
-		embedSchema: embedSchema,
-		db:          db,
-		queries:     q,
+		migrations: ms,
+		dbPath:     dbPath,
+		db:         db,
+		queries:    q,
 	}, nil
 }
 
+// dsn adds the settings every pooled connection is opened with to the database path.
+// WAL lets readers carry on while another process writes, and transactions take the
+// write lock when they begin, where waiting on the busy timeout works, instead of
+// failing with SQLITE_BUSY partway through.
+func dsn(dbPath string) string {
+	q := url.Values{}
+	q.Add("_pragma", fmt.Sprintf("busy_timeout(%d)", busyTimeout.Milliseconds()))
+	q.Add("_pragma", "journal_mode(WAL)")
+	q.Add("_pragma", "synchronous(NORMAL)")
+	q.Add("_pragma", "foreign_keys(1)")
+	q.Set("_txlock", "immediate")
+	return dbPath + "?" + q.Encode()
+}
+
 func (h *Handler) InitStores(ctx context.Context) (*models.AllRepos, error) {
-	if _, err := h.db.ExecContext(ctx, h.embedSchema); err != nil {
-		return nil, fmt.Errorf("executing schema: %w", err)
+	if err := h.migrate(ctx); err != nil {
+		return nil, fmt.Errorf("migrating database: %w", err)
 	}
 
-	return NewRepos(h.queries), nil
+	repos := NewRepos(h.queries)
+	repos.Changes = &changeWatcher{db: h.queries.db}
+	return repos, nil
 }
 
 func (h *Handler) Close() error {
@@ -50,5 +76,8 @@ func NewRepos(q *Queries) *models.AllRepos {
 	return &models.AllRepos{
 		Tasks:    NewTaskRepo(q),
 		Projects: NewProjectRepo(q),
+		Tags:     NewTagRepo(q),
+		Views:    NewViewRepo(q),
+		Tx:       transactor{q: q},
 	}
 }
@@ -10,16 +10,46 @@ import (
 	"time"
 )
 
+const archiveTasksCompletedBefore = `-- name: ArchiveTasksCompletedBefore :execrows
+UPDATE task
+SET archived_at = ?
+WHERE complete = 1 AND completed_at < ?
+    AND archived_at IS NULL AND deleted_at IS NULL
+    AND NOT EXISTS (
+        SELECT 1 FROM task subtask
+        WHERE subtask.parent_task_id = task.id AND subtask.complete = 0 AND subtask.deleted_at IS NULL
+    )
+`
+
+type ArchiveTasksCompletedBeforeParams struct {
+	ArchivedAt      *time.Time
+	CompletedBefore *time.Time
+}
+
+func (q *Queries) ArchiveTasksCompletedBefore(ctx context.Context, arg *ArchiveTasksCompletedBeforeParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, archiveTasksCompletedBefore, arg.ArchivedAt, arg.CompletedBefore)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const createTask = `-- name: CreateTask :one
 INSERT INTO task (
     title,
     parent_task_id,
     project_id,
     complete,
-    due_at
+    due_at,
+    priority,
+    notes,
+    recurrence,
+    completed_at,
+    position
 ) VALUES (
-    ?, ?, ?, ?, ?
-) RETURNING id, title, parent_task_id, project_id, complete, due_at, created_at, updated_at
+    ?1, ?2, ?3, ?4, ?5, ?6, ?7, ?8, ?9,
+    (SELECT COALESCE(MAX(position), 0) + 1 FROM task WHERE parent_task_id IS ?2 AND project_id IS ?3)
+) RETURNING id, title, parent_task_id, project_id, complete, due_at, created_at, updated_at, priority, notes, recurrence, deleted_at, position, completed_at, archived_at
 `
 
 type CreateTaskParams struct {
@@ -28,6 +58,10 @@ type CreateTaskParams struct {
 	ProjectID    *int64
 	Complete     bool
 	DueAt        *time.Time
+	Priority     int64
+	Notes        string
+	Recurrence   string
+	CompletedAt  *time.Time
 }
 
 func (q *Queries) CreateTask(ctx context.Context, arg *CreateTaskParams) (*Task, error) {
@@ -37,6 +71,10 @@ func (q *Queries) CreateTask(ctx context.Context, arg *CreateTaskParams) (*Task,
 		arg.ProjectID,
 		arg.Complete,
 		arg.DueAt,
+		arg.Priority,
+		arg.Notes,
+		arg.Recurrence,
+		arg.CompletedAt,
 	)
 	var i Task
 	err := row.Scan(
@@ -48,6 +86,13 @@ func (q *Queries) CreateTask(ctx context.Context, arg *CreateTaskParams) (*Task,
 		&i.DueAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Priority,
+		&i.Notes,
+		&i.Recurrence,
+		&i.DeletedAt,
+		&i.Position,
+		&i.CompletedAt,
+		&i.ArchivedAt,
 	)
 	return &i, err
 }
@@ -63,7 +108,7 @@ func (q *Queries) DeleteTask(ctx context.Context, id int64) error {
 }
 
 const getTask = `-- name: GetTask :one
-SELECT id, title, parent_task_id, project_id, complete, due_at, created_at, updated_at FROM task
+SELECT id, title, parent_task_id, project_id, complete, due_at, created_at, updated_at, priority, notes, recurrence, deleted_at, position, completed_at, archived_at FROM task
 WHERE id = ? LIMIT 1
 `
 
@@ -79,12 +124,20 @@ func (q *Queries) GetTask(ctx context.Context, id int64) (*Task, error) {
 		&i.DueAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Priority,
+		&i.Notes,
+		&i.Recurrence,
+		&i.DeletedAt,
+		&i.Position,
+		&i.CompletedAt,
+		&i.ArchivedAt,
 	)
 	return &i, err
 }
 
 const listAllTasks = `-- name: ListAllTasks :many
-SELECT id, title, parent_task_id, project_id, complete, due_at, created_at, updated_at FROM task
+SELECT id, title, parent_task_id, project_id, complete, due_at, created_at, updated_at, priority, notes, recurrence, deleted_at, position, completed_at, archived_at FROM task
+WHERE deleted_at IS NULL AND archived_at IS NULL
 `
 
 func (q *Queries) ListAllTasks(ctx context.Context) ([]*Task, error) {
@@ -105,6 +158,108 @@ func (q *Queries) ListAllTasks(ctx context.Context) ([]*Task, error) {
 			&i.DueAt,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Priority,
+			&i.Notes,
+			&i.Recurrence,
+			&i.DeletedAt,
+			&i.Position,
+			&i.CompletedAt,
+			&i.ArchivedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCompletedTasks = `-- name: ListCompletedTasks :many
+SELECT id, title, parent_task_id, project_id, complete, due_at, created_at, updated_at, priority, notes, recurrence, deleted_at, position, completed_at, archived_at FROM task
+WHERE complete = 1 AND deleted_at IS NULL
+ORDER BY completed_at DESC, id DESC
+`
+
+func (q *Queries) ListCompletedTasks(ctx context.Context) ([]*Task, error) {
+	rows, err := q.db.QueryContext(ctx, listCompletedTasks)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*Task{}
+	for rows.Next() {
+		var i Task
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.ParentTaskID,
+			&i.ProjectID,
+			&i.Complete,
+			&i.DueAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Priority,
+			&i.Notes,
+			&i.Recurrence,
+			&i.DeletedAt,
+			&i.Position,
+			&i.CompletedAt,
+			&i.ArchivedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTaskSiblings = `-- name: ListTaskSiblings :many
+SELECT id, title, parent_task_id, project_id, complete, due_at, created_at, updated_at, priority, notes, recurrence, deleted_at, position, completed_at, archived_at FROM task
+WHERE parent_task_id IS ? AND project_id IS ? AND deleted_at IS NULL
+ORDER BY position, id
+`
+
+type ListTaskSiblingsParams struct {
+	ParentTaskID *int64
+	ProjectID    *int64
+}
+
+func (q *Queries) ListTaskSiblings(ctx context.Context, arg *ListTaskSiblingsParams) ([]*Task, error) {
+	rows, err := q.db.QueryContext(ctx, listTaskSiblings, arg.ParentTaskID, arg.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*Task{}
+	for rows.Next() {
+		var i Task
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.ParentTaskID,
+			&i.ProjectID,
+			&i.Complete,
+			&i.DueAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Priority,
+			&i.Notes,
+			&i.Recurrence,
+			&i.DeletedAt,
+			&i.Position,
+			&i.CompletedAt,
+			&i.ArchivedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -120,8 +275,8 @@ func (q *Queries) ListAllTasks(ctx context.Context) ([]*Task, error) {
 }
 
 const listTasksByParentTaskID = `-- name: ListTasksByParentTaskID :many
-SELECT id, title, parent_task_id, project_id, complete, due_at, created_at, updated_at FROM task
-WHERE parent_task_id = ?
+SELECT id, title, parent_task_id, project_id, complete, due_at, created_at, updated_at, priority, notes, recurrence, deleted_at, position, completed_at, archived_at FROM task
+WHERE parent_task_id = ? AND deleted_at IS NULL
 `
 
 func (q *Queries) ListTasksByParentTaskID(ctx context.Context, parentTaskID *int64) ([]*Task, error) {
@@ -142,6 +297,13 @@ func (q *Queries) ListTasksByParentTaskID(ctx context.Context, parentTaskID *int
 			&i.DueAt,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Priority,
+			&i.Notes,
+			&i.Recurrence,
+			&i.DeletedAt,
+			&i.Position,
+			&i.CompletedAt,
+			&i.ArchivedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -157,8 +319,8 @@ func (q *Queries) ListTasksByParentTaskID(ctx context.Context, parentTaskID *int
 }
 
 const listTasksByProjectID = `-- name: ListTasksByProjectID :many
-SELECT id, title, parent_task_id, project_id, complete, due_at, created_at, updated_at FROM task
-WHERE project_id = ?
+SELECT id, title, parent_task_id, project_id, complete, due_at, created_at, updated_at, priority, notes, recurrence, deleted_at, position, completed_at, archived_at FROM task
+WHERE project_id = ? AND deleted_at IS NULL AND archived_at IS NULL
 `
 
 func (q *Queries) ListTasksByProjectID(ctx context.Context, projectID *int64) ([]*Task, error) {
@@ -179,6 +341,13 @@ func (q *Queries) ListTasksByProjectID(ctx context.Context, projectID *int64) ([
 			&i.DueAt,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Priority,
+			&i.Notes,
+			&i.Recurrence,
+			&i.DeletedAt,
+			&i.Position,
+			&i.CompletedAt,
+			&i.ArchivedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -193,6 +362,253 @@ func (q *Queries) ListTasksByProjectID(ctx context.Context, projectID *int64) ([
 	return items, nil
 }
 
+const listTrashedTasks = `-- name: ListTrashedTasks :many
+SELECT task.id, task.title, task.parent_task_id, task.project_id, task.complete, task.due_at, task.created_at, task.updated_at, task.priority, task.notes, task.recurrence, task.deleted_at, task.position, task.completed_at, task.archived_at FROM task
+WHERE task.deleted_at IS NOT NULL
+    AND NOT EXISTS (
+        SELECT 1 FROM project
+        WHERE project.id = task.project_id AND project.deleted_at = task.deleted_at
+    )
+    AND NOT EXISTS (
+        SELECT 1 FROM task parent
+        WHERE parent.id = task.parent_task_id AND parent.deleted_at = task.deleted_at
+    )
+ORDER BY task.deleted_at DESC
+`
+
+func (q *Queries) ListTrashedTasks(ctx context.Context) ([]*Task, error) {
+	rows, err := q.db.QueryContext(ctx, listTrashedTasks)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*Task{}
+	for rows.Next() {
+		var i Task
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.ParentTaskID,
+			&i.ProjectID,
+			&i.Complete,
+			&i.DueAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Priority,
+			&i.Notes,
+			&i.Recurrence,
+			&i.DeletedAt,
+			&i.Position,
+			&i.CompletedAt,
+			&i.ArchivedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const nextTaskPosition = `-- name: NextTaskPosition :one
+SELECT CAST(COALESCE(MAX(position), 0) + 1 AS REAL) AS position FROM task
+WHERE parent_task_id IS ? AND project_id IS ?
+`
+
+type NextTaskPositionParams struct {
+	ParentTaskID *int64
+	ProjectID    *int64
+}
+
+func (q *Queries) NextTaskPosition(ctx context.Context, arg *NextTaskPositionParams) (float64, error) {
+	row := q.db.QueryRowContext(ctx, nextTaskPosition, arg.ParentTaskID, arg.ProjectID)
+	var position float64
+	err := row.Scan(&position)
+	return position, err
+}
+
+const purgeTasksDeletedBefore = `-- name: PurgeTasksDeletedBefore :execrows
+DELETE FROM task
+WHERE deleted_at < ?
+`
+
+func (q *Queries) PurgeTasksDeletedBefore(ctx context.Context, deletedAt *time.Time) (int64, error) {
+	result, err := q.db.ExecContext(ctx, purgeTasksDeletedBefore, deletedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const restoreTaskTree = `-- name: RestoreTaskTree :execrows
+WITH RECURSIVE subtasks(id) AS (
+    SELECT task.id FROM task
+    WHERE task.id = ?
+    UNION
+    SELECT t.id FROM task t
+    JOIN subtasks s ON t.parent_task_id = s.id
+)
+UPDATE task
+SET deleted_at = NULL
+WHERE id IN (SELECT id FROM subtasks) AND deleted_at = ?
+`
+
+type RestoreTaskTreeParams struct {
+	ID        int64
+	DeletedAt *time.Time
+}
+
+func (q *Queries) RestoreTaskTree(ctx context.Context, arg *RestoreTaskTreeParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, restoreTaskTree, arg.ID, arg.DeletedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const searchTasks = `-- name: SearchTasks :many
+SELECT
+    task.id, task.title, task.parent_task_id, task.project_id, task.complete, task.due_at, task.created_at, task.updated_at, task.priority, task.notes, task.recurrence, task.deleted_at, task.position, task.completed_at, task.archived_at,
+    CAST(highlight(task_fts, 0, CAST(? AS TEXT), CAST(? AS TEXT)) AS TEXT) AS highlighted_title
+FROM task_fts
+JOIN task ON task.id = task_fts.rowid
+WHERE task_fts MATCH CAST(? AS TEXT) AND task.deleted_at IS NULL AND task.archived_at IS NULL
+ORDER BY bm25(task_fts), task.id
+LIMIT ?
+`
+
+type SearchTasksParams struct {
+	MarkStart  string
+	MarkEnd    string
+	Query      string
+	MaxResults int64
+}
+
+type SearchTasksRow struct {
+	ID               int64
+	Title            string
+	ParentTaskID     *int64
+	ProjectID        *int64
+	Complete         bool
+	DueAt            *time.Time
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+	Priority         int64
+	Notes            string
+	Recurrence       string
+	DeletedAt        *time.Time
+	Position         float64
+	CompletedAt      *time.Time
+	ArchivedAt       *time.Time
+	HighlightedTitle string
+}
+
+func (q *Queries) SearchTasks(ctx context.Context, arg *SearchTasksParams) ([]*SearchTasksRow, error) {
+	rows, err := q.db.QueryContext(ctx, searchTasks,
+		arg.MarkStart,
+		arg.MarkEnd,
+		arg.Query,
+		arg.MaxResults,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*SearchTasksRow{}
+	for rows.Next() {
+		var i SearchTasksRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.ParentTaskID,
+			&i.ProjectID,
+			&i.Complete,
+			&i.DueAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Priority,
+			&i.Notes,
+			&i.Recurrence,
+			&i.DeletedAt,
+			&i.Position,
+			&i.CompletedAt,
+			&i.ArchivedAt,
+			&i.HighlightedTitle,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setTaskPosition = `-- name: SetTaskPosition :exec
+UPDATE task
+SET position = ?
+WHERE id = ?
+`
+
+type SetTaskPositionParams struct {
+	Position float64
+	ID       int64
+}
+
+func (q *Queries) SetTaskPosition(ctx context.Context, arg *SetTaskPositionParams) error {
+	_, err := q.db.ExecContext(ctx, setTaskPosition, arg.Position, arg.ID)
+	return err
+}
+
+const trashTaskTree = `-- name: TrashTaskTree :execrows
+WITH RECURSIVE subtasks(id) AS (
+    SELECT task.id FROM task
+    WHERE task.id = ?
+    UNION
+    SELECT t.id FROM task t
+    JOIN subtasks s ON t.parent_task_id = s.id
+)
+UPDATE task
+SET deleted_at = ?
+WHERE id IN (SELECT id FROM subtasks) AND deleted_at IS NULL
+`
+
+type TrashTaskTreeParams struct {
+	ID        int64
+	DeletedAt *time.Time
+}
+
+func (q *Queries) TrashTaskTree(ctx context.Context, arg *TrashTaskTreeParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, trashTaskTree, arg.ID, arg.DeletedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const unarchiveTasks = `-- name: UnarchiveTasks :execrows
+UPDATE task
+SET archived_at = NULL
+WHERE archived_at = ?
+`
+
+func (q *Queries) UnarchiveTasks(ctx context.Context, archivedAt *time.Time) (int64, error) {
+	result, err := q.db.ExecContext(ctx, unarchiveTasks, archivedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const updateTask = `-- name: UpdateTask :one
 UPDATE task
 SET
@@ -201,9 +617,15 @@ SET
     project_id = ?,
     complete = ?,
     due_at = ?,
+    priority = ?,
+    notes = ?,
+    recurrence = ?,
+    position = ?,
+    completed_at = ?,
+    archived_at = ?,
     updated_at = CURRENT_TIMESTAMP
 WHERE id = ?
-RETURNING id, title, parent_task_id, project_id, complete, due_at, created_at, updated_at
+RETURNING id, title, parent_task_id, project_id, complete, due_at, created_at, updated_at, priority, notes, recurrence, deleted_at, position, completed_at, archived_at
 `
 
 type UpdateTaskParams struct {
@@ -212,6 +634,12 @@ type UpdateTaskParams struct {
 	ProjectID    *int64
 	Complete     bool
 	DueAt        *time.Time
+	Priority     int64
+	Notes        string
+	Recurrence   string
+	Position     float64
+	CompletedAt  *time.Time
+	ArchivedAt   *time.Time
 	ID           int64
 }
 
@@ -222,6 +650,12 @@ func (q *Queries) UpdateTask(ctx context.Context, arg *UpdateTaskParams) (*Task,
 		arg.ProjectID,
 		arg.Complete,
 		arg.DueAt,
+		arg.Priority,
+		arg.Notes,
+		arg.Recurrence,
+		arg.Position,
+		arg.CompletedAt,
+		arg.ArchivedAt,
 		arg.ID,
 	)
 	var i Task
@@ -234,6 +668,13 @@ func (q *Queries) UpdateTask(ctx context.Context, arg *UpdateTaskParams) (*Task,
 		&i.DueAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Priority,
+		&i.Notes,
+		&i.Recurrence,
+		&i.DeletedAt,
+		&i.Position,
+		&i.CompletedAt,
+		&i.ArchivedAt,
 	)
 	return &i, err
 }
@@ -0,0 +1,48 @@
+package sqlitedb
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/dsrosen6/yata/models"
+)
+
+// transactor gives models a way to make several changes in one transaction.
+type transactor struct {
+	q *Queries
+}
+
+func (t transactor) InTx(ctx context.Context, fn func(r *models.AllRepos) error) error {
+	return t.q.inTx(ctx, func(q *Queries) error { return fn(NewRepos(q)) })
+}
+
+// inTx runs fn in a transaction, committing if it returns nil and rolling back otherwise.
+// If q is already in a transaction, fn just runs in that one.
+func (q *Queries) inTx(ctx context.Context, fn func(q *Queries) error) error {
+	r, ok := q.db.(*retryDB)
+	if !ok {
+		return fn(q)
+	}
+
+	return r.inTx(ctx, func(tx *sql.Tx) error { return fn(q.WithTx(tx)) })
+}
+
+// inTx runs fn in a transaction on the pool. Transactions begin immediately, so they wait
+// for the write lock up front, but if another process holds it past the busy timeout the
+// whole transaction is tried again from the start.
+func (r *retryDB) inTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	return retryBusy(ctx, func() error {
+		tx, err := r.db.BeginTx(ctx, nil)
+		if err != nil {
+			return fmt.Errorf("beginning transaction: %w", err)
+		}
+		defer func() { _ = tx.Rollback() }()
+
+		if err := fn(tx); err != nil {
+			return err
+		}
+
+		return tx.Commit()
+	})
+}
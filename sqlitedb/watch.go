@@ -0,0 +1,22 @@
+package sqlitedb
+
+import (
+	"context"
+	"fmt"
+)
+
+// changeWatcher reads PRAGMA data_version, which only changes when a connection other
+// than the one reading it commits. The handler's pool is a single connection, so that
+// means another process.
+type changeWatcher struct {
+	db DBTX
+}
+
+func (w *changeWatcher) DataVersion(ctx context.Context) (int64, error) {
+	var v int64
+	if err := w.db.QueryRowContext(ctx, "PRAGMA data_version").Scan(&v); err != nil {
+		return 0, fmt.Errorf("reading data version: %w", err)
+	}
+
+	return v, nil
+}
@@ -6,9 +6,13 @@ import (
 )
 
 func (m *model) createTopBox() *fbox.Box {
+	searching := m.currentFocus == focusSearch
 	return fbox.New(fbox.Horizontal, 4).
 		AddTitleBox(m.createProjectsBox(), projViewName, 1, fbox.FixedSize(m.projBoxW), nil, nil).
-		AddTitleBox(m.createTasksBox(), taskViewName, 8, nil, nil, nil)
+		AddTitleBox(m.createTasksBox(), taskViewName, 8, nil, nil, func() bool { return !m.showTrash && !searching }).
+		AddTitleBox(m.createTrashBox(), trashViewName, 8, nil, nil, func() bool { return m.showTrash && !searching }).
+		AddTitleBox(m.createSearchBox(), searchName, 8, nil, nil, func() bool { return searching }).
+		AddTitleBox(m.createDetailBox(), detailName, 5, nil, nil, func() bool { return m.showDetail && !m.showTrash && !searching })
 }
 
 func (m *model) createProjectsBox() titlebox.Box {
@@ -40,6 +44,15 @@ func (m *model) createTasksBox() titlebox.Box {
 
 	border := boxStyle.GetBorderStyle().Top
 	title := "[2]" + border + "tasks"
+	if m.nextActions {
+		title += border + "next actions"
+	}
+	if m.taskFilter != nil {
+		title += border + m.taskFilter.String()
+	}
+	if m.tagFilter.active() {
+		title += border + m.tagFilter.String()
+	}
 
 	return titlebox.New().
 		SetTitle(title).
@@ -50,19 +63,46 @@ func (m *model) createTasksBox() titlebox.Box {
 }
 
 func (m *model) createTaskEntryBox() titlebox.Box {
+	title, body := "new task", m.taskEntryForm.View()
+	switch {
+	case m.editingTask != nil:
+		title, body = "edit task", m.taskEditForm.View()
+	case m.newTaskParent != nil:
+		title = "new subtask of " + m.newTaskParent.Title
+	}
+
 	return titlebox.New().
-		SetTitle("new task").
-		SetBody(m.taskEntryForm.View()).
+		SetTitle(title).
+		SetBody(body).
 		SetTitleAlignment(titlebox.AlignLeft).
 		SetBoxStyle(allStyles.focusedBoxStyle).
 		SetTitleStyle(allStyles.focusedBoxTitleStyle)
 }
 
 func (m *model) createProjectEntryBox() titlebox.Box {
+	title := "new project"
+	if m.editingProject != nil {
+		title = "edit project"
+	}
+
 	return titlebox.New().
-		SetTitle("new project").
+		SetTitle(title).
 		SetBody(m.projectEntryForm.View()).
 		SetTitleAlignment(titlebox.AlignLeft).
 		SetBoxStyle(allStyles.focusedBoxStyle).
 		SetTitleStyle(allStyles.focusedBoxTitleStyle)
 }
+
+func (m *model) createTagEntryBox() titlebox.Box {
+	title := "filter by tags (a b: all, a|b: any)"
+	if m.currentFocus == focusTagEntry && m.taggingTask != nil {
+		title = "tags for " + m.taggingTask.Title
+	}
+
+	return titlebox.New().
+		SetTitle(title).
+		SetBody(m.tagForm.View()).
+		SetTitleAlignment(titlebox.AlignLeft).
+		SetBoxStyle(allStyles.focusedBoxStyle).
+		SetTitleStyle(allStyles.focusedBoxTitleStyle)
+}
@@ -0,0 +1,85 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dsrosen6/yata/models"
+)
+
+// completedDayWidth fits the longest day label, like "Jan 12 2025".
+const completedDayWidth = 11
+
+// completedToItems lists completed tasks by the day they were finished, most recent
+// first, labeling the first task of each day. Subtasks are listed on their own, since
+// they're often finished on a different day to their parent.
+func completedToItems(tasks []*models.Task, meta taskMeta) []list.Item {
+	now := time.Now()
+	items := make([]list.Item, 0, len(tasks))
+	prev := ""
+	for _, t := range tasks {
+		day := "undated"
+		if t.CompletedAt != nil {
+			day = completedDayLabel(now, *t.CompletedAt)
+		}
+
+		item := taskItem{
+			Task:      t,
+			byDay:     true,
+			tags:      meta.tags[t.ID],
+			blockedBy: meta.blockedBy[t.ID],
+			blocked:   meta.blocked[t.ID],
+		}
+		if day != prev {
+			item.day = day
+			prev = day
+		}
+
+		items = append(items, item)
+	}
+
+	return items
+}
+
+// completedDayLabel names the local day of a completion time, relative to now when
+// it's recent.
+func completedDayLabel(now, t time.Time) string {
+	lt := t.In(time.Local)
+	switch days := daysUntil(now, t); {
+	case days == 0:
+		return "today"
+	case days == -1:
+		return "yesterday"
+	case lt.Year() == now.In(time.Local).Year():
+		return lt.Format("Mon Jan 2")
+	default:
+		return lt.Format("Jan 2 2006")
+	}
+}
+
+// archiveCompleted archives the tasks completed more than the configured number of days
+// ago, once confirmed.
+func (m *model) archiveCompleted() {
+	days := m.cfg.ArchiveAfterDays
+	body := fmt.Sprintf("Archive tasks completed more than %d %s ago?", days, plural(days, "day"))
+	if days == 0 {
+		body = "Archive every completed task?"
+	}
+	body += " They'll still be listed in the completed view."
+
+	m.confirm("archiveCompleted", "archive", body, "archive", func() tea.Msg {
+		now := time.Now()
+		c := &taskArchive{completedBefore: now.AddDate(0, 0, -days), at: now}
+		if err := c.apply(context.Background(), m.stores); err != nil {
+			return storeErrorMsg{fmt.Errorf("archiving completed tasks: %w", err)}
+		}
+
+		if c.count == 0 {
+			return refreshTasksMsg{info: "nothing to archive"}
+		}
+		return appliedMsg{change: c, msg: refreshTasksMsg{info: fmt.Sprintf("archived %d %s", c.count, plural(int(c.count), "task"))}}
+	})
+}
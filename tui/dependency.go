@@ -0,0 +1,108 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dsrosen6/yata/models"
+)
+
+const blockedMarker = "⊘"
+
+// blockSelectedTask links dependencies in two steps: the first call marks the selected task
+// as a blocker, and the second marks the task selected then as blocked by it. Calling it
+// again on the marked task cancels.
+func (m *model) blockSelectedTask() tea.Cmd {
+	sel := m.selectedTask()
+	if sel.Task == nil {
+		return nil
+	}
+
+	if m.blocker == nil {
+		m.blocker = sel.Task
+		return infoStatus(fmt.Sprintf("blocker: %s, press b on the task it blocks", sel.Title))
+	}
+
+	blocker := m.blocker
+	m.blocker = nil
+	if blocker.ID == sel.ID {
+		return infoStatus("cancelled")
+	}
+
+	return func() tea.Msg {
+		if err := m.stores.Tasks.AddDependency(context.Background(), sel.ID, blocker.ID); err != nil {
+			if errors.Is(err, models.ErrDependencyCycle) {
+				return storeErrorMsg{fmt.Errorf("can't block %q on %q: %w", sel.Title, blocker.Title, err)}
+			}
+			return storeErrorMsg{fmt.Errorf("adding dependency of task %d on %d: %w", sel.ID, blocker.ID, err)}
+		}
+
+		return refreshTasksMsg{selectTaskID: sel.ID, info: fmt.Sprintf("%q is blocked by %q", sel.Title, blocker.Title)}
+	}
+}
+
+// clearBlockers removes every dependency of the selected task.
+func (m *model) clearBlockers() tea.Cmd {
+	sel := m.selectedTask()
+	if sel.Task == nil || len(sel.blockedBy) == 0 {
+		return nil
+	}
+
+	return func() tea.Msg {
+		if err := m.stores.Tasks.ClearDependencies(context.Background(), sel.ID); err != nil {
+			return storeErrorMsg{fmt.Errorf("clearing dependencies of task %d: %w", sel.ID, err)}
+		}
+
+		return refreshTasksMsg{selectTaskID: sel.ID, info: "blockers cleared"}
+	}
+}
+
+func (m *model) toggleNextActions() tea.Cmd {
+	m.nextActions = !m.nextActions
+	info := "showing all tasks"
+	if m.nextActions {
+		info = "showing next actions"
+	}
+
+	return tea.Batch(
+		m.getUpdatedTasks(m.currentProjectID, m.selectedTaskID()),
+		infoStatus(info),
+	)
+}
+
+// countUnblocked returns how many tasks were blocked before but aren't now.
+func countUnblocked(before, after []int64) int {
+	still := make(map[int64]bool, len(after))
+	for _, id := range after {
+		still[id] = true
+	}
+
+	n := 0
+	for _, id := range before {
+		if !still[id] {
+			n++
+		}
+	}
+
+	return n
+}
+
+// blockerTitles lists the titles of a task's blockers for the detail pane.
+func (m *model) blockerTitles(ids []int64) string {
+	titles := make([]string, len(ids))
+	for i, id := range ids {
+		titles[i] = m.taskTitle(id)
+	}
+
+	return strings.Join(titles, ", ")
+}
+
+func plural(n int, word string) string {
+	if n == 1 {
+		return word
+	}
+	return word + "s"
+}
@@ -0,0 +1,207 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/dsrosen6/yata/models"
+	"github.com/dsrosen6/yata/tui/render/titlebox"
+)
+
+const (
+	detailTimeLayout = "Mon Jan 2 2006 15:04"
+	defaultEditor    = "vi"
+)
+
+// notesEditedMsg is sent when the editor opened for a task's notes exits.
+type notesEditedMsg struct {
+	taskID int64
+	notes  string
+	err    error
+}
+
+func (m *model) createDetailBox() titlebox.Box {
+	boxStyle := allStyles.unfocusedBoxStyle
+	titleStyle := allStyles.unfocusedBoxTitleStyle
+
+	return titlebox.New().
+		SetTitle("details").
+		SetBody(m.detailView()).
+		SetTitleAlignment(titlebox.AlignLeft).
+		SetBoxStyle(boxStyle.Padding(0, 1)).
+		SetTitleStyle(titleStyle)
+}
+
+// detailView shows the selected task's metadata and notes, wrapped and cut off to fit
+// the detail pane.
+func (m *model) detailView() string {
+	t := m.selectedTask()
+	if t.Task == nil {
+		return allStyles.unfocusedTextStyle.Render("No task selected.")
+	}
+
+	label := allStyles.unfocusedBoxTitleStyle.Render
+	var b strings.Builder
+	b.WriteString(allStyles.focusedTextStyle.Render(t.Title) + "\n\n")
+
+	row := func(name, value string) {
+		if value != "" {
+			b.WriteString(label(name+": ") + value + "\n")
+		}
+	}
+
+	status := "open"
+	switch {
+	case t.ArchivedAt != nil:
+		status = "complete, archived"
+	case t.Complete:
+		status = "complete"
+	}
+	row("status", status)
+
+	if t.ProjectID != nil {
+		row("project", models.ProjectPath(m.projects, *t.ProjectID))
+	}
+
+	if t.ParentTaskID != nil {
+		row("parent", m.taskTitle(*t.ParentTaskID))
+	}
+
+	if t.Priority > models.PriorityNone {
+		row("priority", t.Priority.String())
+	}
+
+	if t.DueAt != nil {
+		l, style := dueLabel(time.Now(), *t.DueAt)
+		row("due", previewDueInput(formatDueInput(t.DueAt))+" "+style.Render("("+l+")"))
+	}
+
+	if len(t.blockedBy) > 0 {
+		row("blocked by", m.blockerTitles(t.blockedBy))
+	}
+
+	if t.Recurrence != "" {
+		row("repeats", formatRepeatInput(t.Recurrence))
+	}
+
+	if len(t.tags) > 0 {
+		row("tags", tagSigil+strings.Join(t.tags, " "+tagSigil))
+	}
+
+	if t.childCount > 0 {
+		row("subtasks", fmt.Sprintf("%d/%d done", t.childDoneCount, t.childCount))
+	}
+
+	row("created", t.CreatedAt.In(time.Local).Format(detailTimeLayout))
+	row("updated", t.UpdatedAt.In(time.Local).Format(detailTimeLayout))
+	if t.CompletedAt != nil {
+		row("completed", t.CompletedAt.In(time.Local).Format(detailTimeLayout))
+	}
+
+	b.WriteString("\n")
+	if t.Notes == "" {
+		b.WriteString(allStyles.unfocusedTextStyle.Render("No notes. Press E to add some."))
+	} else {
+		b.WriteString(t.Notes)
+	}
+
+	// the frame is the border plus one column of padding on each side
+	w := max(0, m.detailLayout.ContentWidth-2)
+	h := m.detailLayout.ContentHeight
+	return lipgloss.NewStyle().Width(w).MaxHeight(h).Render(b.String())
+}
+
+// taskTitle finds the title of a task in the current list, falling back to its ID if it
+// isn't shown.
+func (m *model) taskTitle(id int64) string {
+	for _, item := range m.taskList.Items() {
+		if t, ok := item.(taskItem); ok && t.ID == id {
+			return t.Title
+		}
+	}
+
+	return fmt.Sprintf("task %d", id)
+}
+
+// editTaskNotes suspends the program and opens the task's notes in the user's editor,
+// using a temp file. A notesEditedMsg is sent once the editor exits.
+func editTaskNotes(t *models.Task) tea.Cmd {
+	f, err := os.CreateTemp("", fmt.Sprintf("yata-task-%d-*.md", t.ID))
+	if err != nil {
+		return storeError(fmt.Errorf("creating notes file: %w", err))
+	}
+
+	path := f.Name()
+	_, err = f.WriteString(t.Notes)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		_ = os.Remove(path)
+		return storeError(fmt.Errorf("writing notes file: %w", err))
+	}
+
+	editor := strings.Fields(editorCommand())
+	cmd := exec.Command(editor[0], append(editor[1:], path)...)
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		defer func() { _ = os.Remove(path) }()
+		if err != nil {
+			return notesEditedMsg{taskID: t.ID, err: fmt.Errorf("running editor: %w", err)}
+		}
+
+		notes, err := os.ReadFile(path)
+		if err != nil {
+			return notesEditedMsg{taskID: t.ID, err: fmt.Errorf("reading notes file: %w", err)}
+		}
+
+		return notesEditedMsg{taskID: t.ID, notes: strings.TrimRight(string(notes), "\n")}
+	})
+}
+
+// saveTaskNotes stores edited notes on the latest copy of the task, so changes made
+// while the editor was open aren't overwritten.
+func (m *model) saveTaskNotes(msg notesEditedMsg) tea.Cmd {
+	return func() tea.Msg {
+		if msg.err != nil {
+			return storeErrorMsg{fmt.Errorf("editing notes for task %d: %w", msg.taskID, msg.err)}
+		}
+
+		ctx := context.Background()
+		t, err := m.stores.Tasks.Get(ctx, msg.taskID)
+		if err != nil {
+			return storeErrorMsg{fmt.Errorf("getting task %d: %w", msg.taskID, err)}
+		}
+
+		if t.Notes == msg.notes {
+			return refreshTasksMsg{selectTaskID: t.ID, info: "notes unchanged"}
+		}
+
+		after := *t
+		after.Notes = msg.notes
+		return m.applyChange(ctx, taskUpdate{before: t, after: &after}, refreshTasksMsg{selectTaskID: t.ID, info: "notes saved"})
+	}
+}
+
+// editorCommand returns the user's preferred editor, which may include arguments,
+// like "code --wait".
+func editorCommand() string {
+	for _, env := range []string{"VISUAL", "EDITOR"} {
+		if e := strings.TrimSpace(os.Getenv(env)); e != "" {
+			return e
+		}
+	}
+
+	return defaultEditor
+}
+
+func storeError(err error) tea.Cmd {
+	return func() tea.Msg {
+		return storeErrorMsg{err}
+	}
+}
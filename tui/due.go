@@ -0,0 +1,93 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/dsrosen6/yata/dates"
+)
+
+const (
+	dueInputLayout     = "2006-01-02"
+	dueInputTimeLayout = "2006-01-02 15:04"
+)
+
+// dueParser resolves due date phrases from the task form. It's a variable so the
+// clock can be swapped out.
+var dueParser = dates.NewParser()
+
+// parseDueInput parses a due date typed into the task form, like "tomorrow" or
+// "2025-03-14". An empty string means no due date.
+func parseDueInput(s string) (*time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, nil
+	}
+
+	t, err := dueParser.Parse(s)
+	if err != nil {
+		return nil, err
+	}
+
+	return &t, nil
+}
+
+func validateDueInput(s string) error {
+	_, err := parseDueInput(s)
+	return err
+}
+
+// previewDueInput shows what a due date phrase resolves to, for display next to the input.
+func previewDueInput(s string) string {
+	t, err := parseDueInput(s)
+	if err != nil || t == nil {
+		return ""
+	}
+
+	if dates.HasTime(*t) {
+		return t.Format("Mon Jan 2 2006 15:04")
+	}
+	return t.Format("Mon Jan 2 2006")
+}
+
+func formatDueInput(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+
+	lt := t.In(time.Local)
+	if dates.HasTime(lt) {
+		return lt.Format(dueInputTimeLayout)
+	}
+	return lt.Format(dueInputLayout)
+}
+
+// daysUntil returns the number of calendar days from now until t in the local time zone,
+// which is negative if t is in the past.
+func daysUntil(now, t time.Time) int {
+	y, m, d := now.In(time.Local).Date()
+	today := time.Date(y, m, d, 0, 0, 0, 0, time.Local)
+	y, m, d = t.In(time.Local).Date()
+	due := time.Date(y, m, d, 0, 0, 0, 0, time.Local)
+
+	// rounding handles days that aren't exactly 24 hours around DST changes
+	return int(due.Sub(today).Round(24*time.Hour).Hours() / 24)
+}
+
+// dueLabel returns a short relative label for a due date, like "today", "in 3d" or
+// "2d overdue", along with the style it should be drawn in.
+func dueLabel(now, t time.Time) (string, lipgloss.Style) {
+	days := daysUntil(now, t)
+	switch {
+	case days < 0:
+		return fmt.Sprintf("%dd overdue", -days), allStyles.dueOverdueStyle
+	case days == 0:
+		return "today", allStyles.dueTodayStyle
+	case days == 1:
+		return "tomorrow", allStyles.dueUpcomingStyle
+	default:
+		return fmt.Sprintf("in %dd", days), allStyles.dueUpcomingStyle
+	}
+}
@@ -0,0 +1,80 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dsrosen6/yata/filter"
+	"github.com/dsrosen6/yata/tui/models/form"
+	"github.com/dsrosen6/yata/tui/render/titlebox"
+)
+
+type (
+	// filterAppliedMsg sets the task filter, or clears it if filter is nil.
+	filterAppliedMsg struct{ filter *filter.Filter }
+
+	// filterFailedMsg keeps the filter entry open with an error, for filters that parse
+	// but can't be run, like one with a date that can't be read.
+	filterFailedMsg struct{ err error }
+)
+
+func newFilterEntryForm() (*form.Model, error) {
+	fields := []form.Field{
+		{
+			Key: "filter",
+			Validate: func(s string) error {
+				if strings.TrimSpace(s) == "" {
+					return nil
+				}
+				_, err := filter.Parse(s)
+				return err
+			},
+		},
+	}
+	o := &form.Opts{
+		Fields:           fields,
+		PromptIfOneField: true,
+		FocusedStyle:     allStyles.focusedTextStyle,
+		UnfocusedStyle:   allStyles.unfocusedTextStyle,
+		ErrorStyle:       allStyles.errorTextStyle,
+	}
+
+	f, err := form.InitialInputModel(o)
+	if err != nil {
+		return nil, fmt.Errorf("creating model: %w", err)
+	}
+
+	return f, nil
+}
+
+func (m *model) createFilterEntryBox() titlebox.Box {
+	return titlebox.New().
+		SetTitle("filter tasks in every project (like project:work and due<=fri and not done)").
+		SetBody(m.filterForm.View()).
+		SetTitleAlignment(titlebox.AlignLeft).
+		SetBoxStyle(allStyles.focusedBoxStyle).
+		SetTitleStyle(allStyles.focusedBoxTitleStyle)
+}
+
+// applyFilter runs a filter once before setting it, so a problem with one of its values
+// shows up while it can still be fixed. Empty input clears the filter.
+func (m *model) applyFilter(s string) tea.Cmd {
+	return func() tea.Msg {
+		if s == "" {
+			return filterAppliedMsg{}
+		}
+
+		f, err := filter.Parse(s)
+		if err != nil {
+			return filterFailedMsg{err}
+		}
+
+		if _, err := m.stores.Tasks.Query(context.Background(), f); err != nil {
+			return filterFailedMsg{err}
+		}
+
+		return filterAppliedMsg{filter: f}
+	}
+}
@@ -12,10 +12,17 @@ const (
 	focusProjects
 	focusTaskEntry
 	focusProjectEntry
+	focusMessages
+	focusTagEntry
+	focusTagFilter
+	focusTrash
+	focusSearch
+	focusFilterEntry
+	focusViewEntry
 )
 
 func (f focus) isEntry() bool {
-	return f == focusTaskEntry || f == focusProjectEntry
+	return f == focusTaskEntry || f == focusProjectEntry || f == focusTagEntry || f == focusTagFilter || f == focusSearch || f == focusFilterEntry || f == focusViewEntry
 }
 
 func (f focus) toString() string {
@@ -28,6 +35,20 @@ func (f focus) toString() string {
 		return "taskEntry"
 	case focusProjectEntry:
 		return "projectEntry"
+	case focusMessages:
+		return "messages"
+	case focusTagEntry:
+		return "tagEntry"
+	case focusTagFilter:
+		return "tagFilter"
+	case focusTrash:
+		return "trash"
+	case focusSearch:
+		return "search"
+	case focusFilterEntry:
+		return "filterEntry"
+	case focusViewEntry:
+		return "viewEntry"
 	default:
 		return "unknown"
 	}
@@ -3,6 +3,8 @@ package tui
 import (
 	"fmt"
 	"io"
+	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
@@ -11,8 +13,29 @@ import (
 )
 
 type (
-	taskItem            struct{ *models.Task }
-	taskProjectItem     struct{ *models.Project }
+	taskItem struct {
+		*models.Task
+		depth          int
+		collapsed      bool
+		childCount     int
+		childDoneCount int
+		tags           []string
+		blockedBy      []int64
+		blocked        bool
+
+		// byDay is set in the completed view, where tasks are listed under the day they
+		// were finished. day is only set on the first task of each day.
+		byDay bool
+		day   string
+	}
+	// taskProjectItem is a project in the project list, or a view when view is set.
+	taskProjectItem struct {
+		*models.Project
+		view        *view
+		depth       int
+		hasChildren bool
+		collapsed   bool
+	}
 	taskItemDelegate    struct{}
 	projectItemDelegate struct{ maxWidth int }
 )
@@ -40,13 +63,64 @@ func (d taskItemDelegate) Render(w io.Writer, m list.Model, index int, listItem
 		checked = "󰄵"
 	}
 
-	str := fmt.Sprintf("%s %s", checked, i.Title)
-	fn := allStyles.unfocusedTextStyle.Render
+	marker := " "
+	if i.childCount > 0 {
+		marker = "▾"
+		if i.collapsed {
+			marker = "▸"
+		}
+	}
+
+	indent := strings.Repeat("  ", i.depth)
+	lock := ""
+	if i.blocked {
+		lock = blockedMarker + " "
+	}
+
+	str := fmt.Sprintf("%s%s%s %s%s%s", indent, marker, checked, lock, priorityMarker(i.Priority), i.Title)
+	day := ""
+	if i.byDay {
+		day = allStyles.unfocusedBoxTitleStyle.Render(fmt.Sprintf("%-*s ", completedDayWidth, i.day))
+	}
+	if i.Recurrence != "" {
+		str += " " + recurMarker
+	}
+
+	if i.childCount > 0 {
+		str += fmt.Sprintf(" (%d/%d done)", i.childDoneCount, i.childCount)
+	}
+
+	style := allStyles.unfocusedTextStyle
 	if index == m.Index() {
-		fn = allStyles.focusedTextStyle.Render
+		style = allStyles.focusedTextStyle
 	}
 
-	_, _ = fmt.Fprint(w, fn(str))
+	if i.blocked || i.ArchivedAt != nil {
+		style = style.Faint(true)
+	}
+
+	str = day + style.Render(str)
+	for _, t := range i.tags {
+		str += " " + allStyles.tagStyle.Render(tagSigil+t)
+	}
+
+	if i.DueAt != nil {
+		label, style := dueLabel(time.Now(), *i.DueAt)
+		if i.Complete {
+			style = allStyles.unfocusedTextStyle
+		}
+		str += " " + style.Render(label)
+	}
+
+	_, _ = fmt.Fprint(w, str)
+}
+
+// priorityMarker shows one ! per priority level above none.
+func priorityMarker(p models.Priority) string {
+	if p <= models.PriorityNone {
+		return ""
+	}
+	return strings.Repeat("!", int(p)) + " "
 }
 
 func (t taskItem) FilterValue() string {
@@ -77,7 +151,24 @@ func (d projectItemDelegate) Render(w io.Writer, m list.Model, index int, listIt
 		prepend = ">"
 		fn = allStyles.focusedTextStyle.Render
 	}
-	str := fmt.Sprintf("%s%s", prepend, i.Title)
+
+	marker := " "
+	if i.hasChildren {
+		marker = "▾"
+		if i.collapsed {
+			marker = "▸"
+		}
+	}
+
+	var title string
+	if i.view != nil {
+		marker, title = viewMarker, i.view.name
+	} else {
+		title = i.Title
+	}
+
+	indent := strings.Repeat(" ", i.depth)
+	str := fmt.Sprintf("%s%s%s%s", prepend, indent, marker, title)
 	if lipgloss.Width(str) > d.maxWidth {
 		if d.maxWidth < 4 {
 			// too narrow for ellipsis and prepend string, just truncate
@@ -90,6 +181,9 @@ func (d projectItemDelegate) Render(w io.Writer, m list.Model, index int, listIt
 }
 
 func (p taskProjectItem) FilterValue() string {
+	if p.view != nil {
+		return p.view.name
+	}
 	return p.Title
 }
 
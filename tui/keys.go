@@ -15,15 +15,48 @@ type navigationKeys struct {
 	toggleHelp         key.Binding
 	focusProjects      key.Binding
 	focusTasks         key.Binding
+	focusMessages      key.Binding
+	toggleMessages     key.Binding
 	delete             key.Binding
 	newTask            key.Binding
 	newProject         key.Binding
+	edit               key.Binding
+	newSubItem         key.Binding
+	toggleCollapsed    key.Binding
+	toggleSubprojects  key.Binding
+	indent             key.Binding
+	raisePriority      key.Binding
+	editTags           key.Binding
+	block              key.Binding
+	clearBlockers      key.Binding
+	toggleNextActions  key.Binding
+	editNotes          key.Binding
+	toggleDetail       key.Binding
+	filterTags         key.Binding
+	filterTasks        key.Binding
+	newView            key.Binding
+	lowerPriority      key.Binding
+	cycleSort          key.Binding
+	reverseSort        key.Binding
+	outdent            key.Binding
 	toggleTaskComplete key.Binding
+	toggleTrash        key.Binding
+	restore            key.Binding
+	emptyTrash         key.Binding
+	moveTask           key.Binding
+	moveUp             key.Binding
+	archive            key.Binding
+	moveDown           key.Binding
+	undo               key.Binding
+	redo               key.Binding
+	search             key.Binding
 }
 
 type entryKeys struct {
 	cancelEntry key.Binding
 	submit      key.Binding
+	prevResult  key.Binding
+	nextResult  key.Binding
 }
 
 var (
@@ -31,7 +64,8 @@ var (
 		navigationKeys: defaultNavKeys,
 		entryKeys:      defaultEntryKeys,
 	}
-	helpStyle = lipgloss.NewStyle().Padding(0, 1).AlignHorizontal(lipgloss.Center)
+	helpStyle   = lipgloss.NewStyle().Padding(0, 1).AlignHorizontal(lipgloss.Center)
+	statusStyle = lipgloss.NewStyle().Padding(0, 1)
 )
 
 var defaultNavKeys = navigationKeys{
@@ -51,9 +85,17 @@ var defaultNavKeys = navigationKeys{
 		key.WithKeys("2", "focus tasks"),
 		key.WithHelp("2", "focus tasks"),
 	),
+	focusMessages: key.NewBinding(
+		key.WithKeys("3"),
+		key.WithHelp("3", "focus messages"),
+	),
+	toggleMessages: key.NewBinding(
+		key.WithKeys("M"),
+		key.WithHelp("M", "messages"),
+	),
 	delete: key.NewBinding(
 		key.WithKeys("x"),
-		key.WithHelp("x", "delete"),
+		key.WithHelp("x", "trash"),
 	),
 	newTask: key.NewBinding(
 		key.WithKeys("n"),
@@ -63,10 +105,126 @@ var defaultNavKeys = navigationKeys{
 		key.WithKeys("N"),
 		key.WithHelp("N", "new project"),
 	),
+	edit: key.NewBinding(
+		key.WithKeys("e"),
+		key.WithHelp("e", "edit"),
+	),
+	newSubItem: key.NewBinding(
+		key.WithKeys("a"),
+		key.WithHelp("a", "new sub-item"),
+	),
+	toggleCollapsed: key.NewBinding(
+		key.WithKeys("tab"),
+		key.WithHelp("tab", "expand/collapse"),
+	),
+	toggleSubprojects: key.NewBinding(
+		key.WithKeys("s"),
+		key.WithHelp("s", "sub-project tasks"),
+	),
+	cycleSort: key.NewBinding(
+		key.WithKeys("o"),
+		key.WithHelp("o", "sort"),
+	),
+	reverseSort: key.NewBinding(
+		key.WithKeys("O"),
+		key.WithHelp("O", "reverse sort"),
+	),
+	editNotes: key.NewBinding(
+		key.WithKeys("E"),
+		key.WithHelp("E", "notes"),
+	),
+	toggleDetail: key.NewBinding(
+		key.WithKeys("D"),
+		key.WithHelp("D", "details"),
+	),
+	block: key.NewBinding(
+		key.WithKeys("b"),
+		key.WithHelp("b", "blocked by"),
+	),
+	clearBlockers: key.NewBinding(
+		key.WithKeys("B"),
+		key.WithHelp("B", "clear blockers"),
+	),
+	toggleNextActions: key.NewBinding(
+		key.WithKeys("w"),
+		key.WithHelp("w", "next actions"),
+	),
+	editTags: key.NewBinding(
+		key.WithKeys("t"),
+		key.WithHelp("t", "tags"),
+	),
+	filterTags: key.NewBinding(
+		key.WithKeys("f"),
+		key.WithHelp("f", "filter tags"),
+	),
+	filterTasks: key.NewBinding(
+		key.WithKeys("F"),
+		key.WithHelp("F", "filter"),
+	),
+	newView: key.NewBinding(
+		key.WithKeys("V"),
+		key.WithHelp("V", "new view"),
+	),
+	raisePriority: key.NewBinding(
+		key.WithKeys("+", "="),
+		key.WithHelp("+/-", "priority"),
+	),
+	lowerPriority: key.NewBinding(
+		key.WithKeys("-"),
+		key.WithHelp("-", "lower priority"),
+	),
+	indent: key.NewBinding(
+		key.WithKeys(">"),
+		key.WithHelp(">/<", "indent"),
+	),
+	outdent: key.NewBinding(
+		key.WithKeys("<"),
+		key.WithHelp("<", "outdent"),
+	),
 	toggleTaskComplete: key.NewBinding(
 		key.WithKeys(" "),
 		key.WithHelp("space", "complete"),
 	),
+	toggleTrash: key.NewBinding(
+		key.WithKeys("T"),
+		key.WithHelp("T", "trash"),
+	),
+	restore: key.NewBinding(
+		key.WithKeys("r"),
+		key.WithHelp("r", "restore"),
+	),
+	emptyTrash: key.NewBinding(
+		key.WithKeys("X"),
+		key.WithHelp("X", "empty trash"),
+	),
+	moveTask: key.NewBinding(
+		key.WithKeys("m"),
+		key.WithHelp("m", "move"),
+	),
+	archive: key.NewBinding(
+		key.WithKeys("A"),
+		key.WithHelp("A", "archive completed"),
+	),
+	moveUp: key.NewBinding(
+		key.WithKeys("K"),
+		key.WithHelp("K/J", "reorder"),
+	),
+	moveDown: key.NewBinding(
+		key.WithKeys("J"),
+		key.WithHelp("J", "move down"),
+	),
+	undo: key.NewBinding(
+		key.WithKeys("u"),
+		key.WithHelp("u/ctrl+r", "undo/redo"),
+	),
+	redo: key.NewBinding(
+		key.WithKeys("ctrl+r"),
+		key.WithHelp("ctrl+r", "redo"),
+	),
+	search: key.NewBinding(
+		key.WithKeys("/"),
+		key.WithHelp("/", "search"),
+	),
 }
 
 var defaultEntryKeys = entryKeys{
@@ -78,6 +236,14 @@ var defaultEntryKeys = entryKeys{
 		key.WithKeys("enter"),
 		key.WithHelp("enter", "submit"),
 	),
+	prevResult: key.NewBinding(
+		key.WithKeys("up", "ctrl+p"),
+		key.WithHelp("↑/↓", "select"),
+	),
+	nextResult: key.NewBinding(
+		key.WithKeys("down", "ctrl+n"),
+		key.WithHelp("↓", "next"),
+	),
 }
 
 func (k navigationKeys) ShortHelp() []key.Binding {
@@ -91,21 +257,61 @@ func (k navigationKeys) FullHelp() [][]key.Binding {
 }
 
 func (m *model) helpKeys() []key.Binding {
+	if m.modal != nil {
+		return []key.Binding{helpWithDesc(m.keys.submit, "choose"), m.keys.cancelEntry}
+	}
+
+	if m.currentFocus == focusSearch {
+		return []key.Binding{m.keys.cancelEntry, helpWithDesc(m.keys.submit, "go to task"), m.keys.prevResult}
+	}
+
 	if m.currentFocus.isEntry() {
 		return []key.Binding{m.keys.cancelEntry, m.keys.submit}
 	}
 
 	k := []key.Binding{m.keys.newTask, m.keys.newProject}
 	switch m.currentFocus {
+	case focusMessages:
+		return []key.Binding{m.keys.focusProjects, m.keys.focusTasks, m.keys.toggleMessages}
+	case focusTrash:
+		if len(m.trashList.Items()) > 0 {
+			k = append(k, m.keys.restore, helpWithDesc(m.keys.delete, "delete forever"), m.keys.emptyTrash)
+		}
+		return append(k, helpWithDesc(m.keys.toggleTrash, "close trash"))
 	case focusProjects:
+		if v := m.selectedView(); v != nil && v.saved() {
+			k = append(k, m.keys.edit, helpWithDesc(m.keys.delete, "delete"))
+		}
 		if m.selectedProjectID() != 0 {
-			k = append(k, m.keys.delete)
+			k = append(k, helpWithDesc(m.keys.newSubItem, "new sub-project"), m.keys.edit, m.keys.delete, m.keys.moveUp)
+			if m.selectedProject().hasChildren {
+				k = append(k, m.keys.toggleCollapsed)
+			}
+		}
+		k = append(k, m.keys.newView, m.keys.toggleSubprojects, m.keys.search)
+		if len(m.history.undo) > 0 || len(m.history.redo) > 0 {
+			k = append(k, m.keys.undo)
 		}
 	case focusTasks:
 		if m.selectedTaskID() != 0 {
 			tc := taskCompleteHelp(m.keys.toggleTaskComplete, m.selectedTask().Complete)
-			k = append(k, tc, m.keys.delete)
+			k = append(k, tc, helpWithDesc(m.keys.newSubItem, "new subtask"), m.keys.edit, m.keys.delete, m.keys.moveTask, m.keys.moveUp, m.keys.raisePriority, m.keys.editTags, m.keys.editNotes, m.keys.block)
+			if m.selectedTask().childCount > 0 {
+				k = append(k, m.keys.toggleCollapsed)
+			}
+			if len(m.selectedTask().blockedBy) > 0 {
+				k = append(k, m.keys.clearBlockers)
+			}
+			k = append(k, m.keys.indent)
+		}
+		if len(m.history.undo) > 0 || len(m.history.redo) > 0 {
+			k = append(k, m.keys.undo)
+		}
+		k = append(k, m.keys.search, m.keys.cycleSort, m.keys.filterTags, m.keys.filterTasks)
+		if m.taskFilter != nil {
+			k = append(k, helpWithDesc(m.keys.newView, "save view"))
 		}
+		k = append(k, m.keys.toggleNextActions, m.keys.archive, m.keys.toggleDetail, m.keys.toggleTrash)
 	}
 
 	return k
@@ -125,3 +331,12 @@ func taskCompleteHelp(original key.Binding, complete bool) key.Binding {
 		key.WithHelp(original.Help().Key, c),
 	)
 }
+
+// helpWithDesc copies a key binding with a different help description, for bindings
+// whose action depends on what is focused.
+func helpWithDesc(original key.Binding, desc string) key.Binding {
+	return key.NewBinding(
+		key.WithKeys(original.Keys()...),
+		key.WithHelp(original.Help().Key, desc),
+	)
+}
@@ -6,7 +6,7 @@ import (
 )
 
 func initialTaskList(tasks []*models.Task) list.Model {
-	items := tasksToItems(tasks)
+	items := tasksToItems(tasks, nil, taskMeta{})
 	ls := list.New(items, taskItemDelegate{}, 10, 10)
 	ls.SetShowStatusBar(false)
 	ls.SetShowTitle(false)
@@ -15,8 +15,8 @@ func initialTaskList(tasks []*models.Task) list.Model {
 	return ls
 }
 
-func initialProjectList(projects []*models.Project) list.Model {
-	items := projectsToItems(projects)
+func initialProjectList(views []view, projects []*models.Project) list.Model {
+	items := projectsToItems(views, projects, nil)
 	ls := list.New(items, projectItemDelegate{maxWidth: 20}, 10, 10)
 	ls.SetShowStatusBar(false)
 	ls.SetShowTitle(false)
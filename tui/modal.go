@@ -0,0 +1,57 @@
+package tui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dsrosen6/yata/tui/models/modal"
+)
+
+const (
+	maxModalWidth   = 60
+	noProjectOption = "(no project)"
+)
+
+// showModalMsg opens a modal from a command, for when what to ask depends on the store.
+type showModalMsg struct {
+	modal    *modal.Model
+	onChoose func(modal.ResultMsg) tea.Cmd
+}
+
+// openModal shows a modal on top of everything else. onChoose runs with the result
+// unless the modal is cancelled.
+func (m *model) openModal(md *modal.Model, onChoose func(modal.ResultMsg) tea.Cmd) {
+	m.modal = md
+	m.onModalChoose = onChoose
+}
+
+// closeModal handles a modal's result, ignoring results from one that's no longer open.
+func (m *model) closeModal(msg modal.ResultMsg) tea.Cmd {
+	if m.modal == nil || msg.ID != m.modal.ID {
+		return nil
+	}
+
+	onChoose := m.onModalChoose
+	m.modal = nil
+	m.onModalChoose = nil
+	if msg.Cancelled || onChoose == nil {
+		return infoStatus("cancelled")
+	}
+
+	return onChoose(msg)
+}
+
+// confirm asks before running an action. The label names the action on its button.
+func (m *model) confirm(id, title, body, label string, action tea.Cmd) {
+	md := modal.NewConfirm(id, title, body, label, m.modalOpts())
+	m.openModal(md, func(modal.ResultMsg) tea.Cmd { return action })
+}
+
+func (m *model) modalOpts() modal.Opts {
+	return modal.Opts{
+		BoxStyle:       allStyles.focusedBoxStyle,
+		TitleStyle:     allStyles.focusedBoxTitleStyle,
+		FocusedStyle:   allStyles.focusedTextStyle,
+		UnfocusedStyle: allStyles.unfocusedTextStyle,
+		MaxWidth:       min(m.windowW, maxModalWidth),
+		MaxHeight:      m.windowH,
+	}
+}
@@ -3,38 +3,90 @@ package tui
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/dsrosen6/yata/config"
+	"github.com/dsrosen6/yata/filter"
 	"github.com/dsrosen6/yata/models"
 	"github.com/dsrosen6/yata/tui/models/form"
+	"github.com/dsrosen6/yata/tui/models/modal"
 	fbox "github.com/dsrosen6/yata/tui/render/flexbox"
+	"github.com/dsrosen6/yata/tui/render/overlay"
 )
 
 const (
-	topBoxName    = "topBox"
-	taskViewName  = "taskView"
-	taskEntryName = "taskEntry"
-	projViewName  = "projectView"
-	projEntryName = "projectEntry"
-	helpViewName  = "helpView"
+	topBoxName      = "topBox"
+	taskViewName    = "taskView"
+	trashViewName   = "trashView"
+	taskEntryName   = "taskEntry"
+	projViewName    = "projectView"
+	projEntryName   = "projectEntry"
+	tagEntryName    = "tagEntry"
+	filterEntryName = "filterEntry"
+	viewEntryName   = "viewEntry"
+	searchName      = "search"
+	searchEntryName = "searchEntry"
+	messagesName    = "messages"
+	detailName      = "detail"
+	statusName      = "status"
+	helpViewName    = "helpView"
 )
 
 type (
 	model struct {
-		stores           *models.AllRepos
-		keys             keyMap
-		help             help.Model
-		showHelp         bool
-		taskList         list.Model
-		projectList      list.Model
-		taskEntryForm    *form.Model
-		projectEntryForm *form.Model
-		sortParams       *models.SortParams
-		currentFocus     focus
-		currentProjectID int64
+		cfg                *config.Config
+		stores             *models.AllRepos
+		keys               keyMap
+		help               help.Model
+		showHelp           bool
+		taskList           list.Model
+		projectList        list.Model
+		taskEntryForm      *form.Model
+		taskEditForm       *form.Model
+		projectEntryForm   *form.Model
+		editingTask        *models.Task
+		editingTaskValues  form.Result
+		newTaskParent      *models.Task
+		collapsedTasks     map[int64]bool
+		editingProject     *models.Project
+		tagForm            *form.Model
+		taggingTask        *taskItem
+		tagFilter          tagFilter
+		filterForm         *form.Model
+		taskFilter         *filter.Filter
+		viewEntryForm      *form.Model
+		editingView        *view
+		currentView        *view
+		blocker            *models.Task
+		nextActions        bool
+		projects           []*models.Project
+		collapsedProjects  map[int64]bool
+		includeSubprojects bool
+		sortParams         *models.SortParams
+		currentFocus       focus
+		currentProjectID   int64
+		status             *statusEntry
+		statusID           int
+		messages           []statusEntry
+		messageView        viewport.Model
+		showMessages       bool
+		showDetail         bool
+		trashList          list.Model
+		showTrash          bool
+		history            history
+		modal              *modal.Model
+		onModalChoose      func(modal.ResultMsg) tea.Cmd
+		searchForm         *form.Model
+		searchList         list.Model
+		searchQuery        string
+		dataVersion        int64
+		seenDataVersion    bool
 
 		dimensions
 	}
@@ -42,17 +94,42 @@ type (
 	dimensionsCalculatedMsg struct{ dimensions }
 )
 
-func initialModel(stores *models.AllRepos) (*model, error) {
+func initialModel(cfg *config.Config, stores *models.AllRepos) (*model, error) {
 	te, err := newTaskEntryForm()
 	if err != nil {
 		return nil, fmt.Errorf("creating task entry form: %w", err)
 	}
 
+	tef, err := newTaskEditForm()
+	if err != nil {
+		return nil, fmt.Errorf("creating task edit form: %w", err)
+	}
+
 	pe, err := newProjectEntryForm()
 	if err != nil {
 		return nil, fmt.Errorf("creating project entry form: %w", err)
 	}
 
+	tf, err := newTagEntryForm()
+	if err != nil {
+		return nil, fmt.Errorf("creating tag entry form: %w", err)
+	}
+
+	ff, err := newFilterEntryForm()
+	if err != nil {
+		return nil, fmt.Errorf("creating filter entry form: %w", err)
+	}
+
+	sf, err := newSearchForm()
+	if err != nil {
+		return nil, fmt.Errorf("creating search form: %w", err)
+	}
+
+	vf, err := newViewEntryForm()
+	if err != nil {
+		return nil, fmt.Errorf("creating view entry form: %w", err)
+	}
+
 	tasks, err := stores.Tasks.ListAll(context.Background())
 	if err != nil {
 		return nil, fmt.Errorf("getting initial tasks: %w", err)
@@ -63,25 +140,52 @@ func initialModel(stores *models.AllRepos) (*model, error) {
 		return nil, fmt.Errorf("getting initial projects: %w", err)
 	}
 
+	views, err := stores.Views.ListAll(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("getting saved views: %w", err)
+	}
+
+	// the project list starts on the all view, which lists the same tasks as above
+	current := allView
+
 	return &model{
-		stores:           stores,
-		keys:             defaultKeyMap,
-		help:             help.New(),
-		showHelp:         true,
-		taskList:         initialTaskList(tasks),
-		projectList:      initialProjectList(projects),
-		taskEntryForm:    te,
-		projectEntryForm: pe,
-		sortParams:       &models.SortParams{SortBy: models.SortByComplete},
+		cfg:               cfg,
+		stores:            stores,
+		keys:              defaultKeyMap,
+		help:              help.New(),
+		showHelp:          true,
+		taskList:          initialTaskList(tasks),
+		projectList:       initialProjectList(allViews(views), projects),
+		taskEntryForm:     te,
+		taskEditForm:      tef,
+		projectEntryForm:  pe,
+		tagForm:           tf,
+		filterForm:        ff,
+		viewEntryForm:     vf,
+		currentView:       &current,
+		projects:          projects,
+		collapsedProjects: make(map[int64]bool),
+		collapsedTasks:    make(map[int64]bool),
+		sortParams:        &models.SortParams{SortBy: models.SortByComplete},
+		messageView:       newMessageView(),
+		showDetail:        true,
+		trashList:         initialTrashList(),
+		searchForm:        sf,
+		searchList:        initialSearchList(),
 	}, nil
 }
 
 func (m *model) Init() tea.Cmd {
-	return nil
+	return m.watchChanges()
 }
 
 func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
+	if k, ok := msg.(tea.KeyMsg); ok && m.modal != nil {
+		m.modal, cmd = m.modal.Update(k)
+		return m, cmd
+	}
+
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		return m, m.calculateDimensions(msg.Width, msg.Height)
@@ -90,9 +194,45 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.projectList.SetDelegate(projectItemDelegate{maxWidth: m.projDelegMaxW})
 		m.projectList.SetHeight(m.listsH)
 		m.taskList.SetHeight(m.listsH)
+		m.trashList.SetHeight(m.listsH)
+		m.searchList.SetHeight(m.listsH)
+		m.messageView.Width = max(0, m.messagesLayout.ContentWidth-2)
+		m.messageView.Height = m.messagesLayout.ContentHeight
+		// lets the help line cut off with an ellipsis rather than wrapping
+		m.help.Width = max(0, m.windowW-helpStyle.GetHorizontalPadding())
+		if m.modal != nil {
+			m.modal.SetMaxSize(min(m.windowW, maxModalWidth), m.windowH)
+		}
 		m.logDimensions()
+	case storeErrorMsg:
+		return m, m.handleStoreError(msg.error)
+	case statusMsg:
+		return m, m.pushStatus(msg.statusEntry)
+	case clearStatusMsg:
+		return m, m.clearStatus(msg.id)
+	case notesEditedMsg:
+		return m, m.saveTaskNotes(msg)
+	case showModalMsg:
+		m.openModal(msg.modal, msg.onChoose)
+		return m, nil
+	case modal.ResultMsg:
+		return m, m.closeModal(msg)
+	case appliedMsg:
+		m.history.push(msg.change)
+		next := msg.msg
+		return m, func() tea.Msg { return next }
+	case undoneMsg:
+		m.history.redo = append(m.history.redo, msg.change)
+		return m, func() tea.Msg { return refreshAllMsg{info: "undid " + msg.change.String()} }
+	case redoneMsg:
+		m.history.undo = append(m.history.undo, msg.change)
+		return m, func() tea.Msg { return refreshAllMsg{info: "redid " + msg.change.String()} }
 	case changeFocusMsg:
 		m.currentFocus = msg.focus
+		if m.currentFocus == focusTasks && m.showTrash {
+			// the trash view takes the task list's place
+			m.currentFocus = focusTrash
+		}
 		return m, m.calculateDimensions(m.windowW, m.windowH)
 
 	case tea.KeyMsg:
@@ -110,12 +250,18 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			switch m.currentFocus {
 			case focusTasks:
 				if len(m.taskList.Items()) > 0 {
-					return m, m.deleteTask(m.selectedTaskID())
+					return m, m.trashTask(m.selectedTask().Task)
 				}
+			case focusTrash:
+				m.purgeTrashItem()
+				return m, nil
 			case focusProjects:
-				// Don't allow deleting the "all" entry (which has ID 0)
+				if v := m.selectedView(); v != nil && v.saved() {
+					m.deleteView(*v)
+					return m, nil
+				}
 				if len(m.projectList.Items()) > 0 && m.selectedProjectID() != 0 {
-					return m, m.deleteProject(m.selectedProjectID())
+					return m, m.trashProject(m.selectedProject().Project)
 				}
 			}
 		case key.Matches(msg, m.keys.focusProjects):
@@ -126,6 +272,22 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if !m.currentFocus.isEntry() {
 				return m, changeFocus(focusTasks)
 			}
+		case key.Matches(msg, m.keys.focusMessages):
+			if !m.currentFocus.isEntry() && m.showMessages {
+				return m, changeFocus(focusMessages)
+			}
+		case key.Matches(msg, m.keys.toggleMessages):
+			if !m.currentFocus.isEntry() {
+				m.showMessages = !m.showMessages
+				if m.showMessages {
+					m.updateMessageView()
+					return m, changeFocus(focusMessages)
+				}
+				if m.currentFocus == focusMessages {
+					return m, changeFocus(focusTasks)
+				}
+				return m, m.calculateDimensions(m.windowW, m.windowH)
+			}
 		case key.Matches(msg, m.keys.newTask):
 			if !m.currentFocus.isEntry() {
 				return m, tea.Batch(m.taskEntryForm.Init(), changeFocus(focusTaskEntry))
@@ -134,11 +296,122 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if !m.currentFocus.isEntry() {
 				return m, tea.Batch(m.projectEntryForm.Init(), changeFocus(focusProjectEntry))
 			}
+		case key.Matches(msg, m.keys.newView):
+			if m.currentFocus == focusTasks || m.currentFocus == focusProjects {
+				return m, m.newView()
+			}
+		case key.Matches(msg, m.keys.edit):
+			switch m.currentFocus {
+			case focusTasks:
+				if m.selectedTaskID() != 0 {
+					m.editingTask = m.selectedTask().Task
+					m.editingTaskValues = m.taskToInputValues(m.editingTask)
+					m.taskEditForm.SetValues(m.editingTaskValues)
+					return m, tea.Batch(m.taskEditForm.Init(), changeFocus(focusTaskEntry))
+				}
+			case focusProjects:
+				if v := m.selectedView(); v != nil && v.saved() {
+					return m, m.editView(*v)
+				}
+				if p := m.selectedProject(); p != nil {
+					m.editingProject = p.Project
+					m.projectEntryForm.SetValues(projectToInputValues(m.editingProject, m.projects))
+					return m, tea.Batch(m.projectEntryForm.Init(), changeFocus(focusProjectEntry))
+				}
+			}
+		case key.Matches(msg, m.keys.newSubItem):
+			switch m.currentFocus {
+			case focusTasks:
+				if m.selectedTaskID() != 0 {
+					m.newTaskParent = m.selectedTask().Task
+					return m, tea.Batch(m.taskEntryForm.Init(), changeFocus(focusTaskEntry))
+				}
+			case focusProjects:
+				if m.selectedProjectID() != 0 {
+					m.projectEntryForm.SetValues(form.Result{"parent": models.ProjectPath(m.projects, m.selectedProjectID())})
+					return m, tea.Batch(m.projectEntryForm.Init(), changeFocus(focusProjectEntry))
+				}
+			}
+		case key.Matches(msg, m.keys.toggleCollapsed):
+			switch m.currentFocus {
+			case focusTasks:
+				return m, m.toggleTaskCollapsed()
+			case focusProjects:
+				return m, m.toggleProjectCollapsed()
+			}
+		case key.Matches(msg, m.keys.editTags):
+			if m.currentFocus == focusTasks && m.selectedTaskID() != 0 {
+				t := m.selectedTask()
+				m.taggingTask = &t
+				m.tagForm.SetValues(form.Result{"tags": strings.Join(t.tags, " ")})
+				return m, tea.Batch(m.tagForm.Init(), changeFocus(focusTagEntry))
+			}
+		case key.Matches(msg, m.keys.filterTags):
+			if m.currentFocus == focusTasks {
+				m.tagForm.SetValues(form.Result{"tags": m.tagFilterInput()})
+				return m, tea.Batch(m.tagForm.Init(), changeFocus(focusTagFilter))
+			}
+		case key.Matches(msg, m.keys.filterTasks):
+			if m.currentFocus == focusTasks {
+				if m.taskFilter != nil {
+					m.filterForm.SetValues(form.Result{"filter": m.taskFilter.String()})
+				}
+				return m, tea.Batch(m.filterForm.Init(), changeFocus(focusFilterEntry))
+			}
+		case key.Matches(msg, m.keys.editNotes):
+			if m.currentFocus == focusTasks && m.selectedTaskID() != 0 {
+				return m, editTaskNotes(m.selectedTask().Task)
+			}
+		case key.Matches(msg, m.keys.toggleDetail):
+			if !m.currentFocus.isEntry() {
+				m.showDetail = !m.showDetail
+				return m, m.calculateDimensions(m.windowW, m.windowH)
+			}
+		case key.Matches(msg, m.keys.block):
+			if m.currentFocus == focusTasks {
+				return m, m.blockSelectedTask()
+			}
+		case key.Matches(msg, m.keys.clearBlockers):
+			if m.currentFocus == focusTasks {
+				return m, m.clearBlockers()
+			}
+		case key.Matches(msg, m.keys.toggleNextActions):
+			if m.currentFocus == focusTasks {
+				return m, m.toggleNextActions()
+			}
+		case key.Matches(msg, m.keys.undo):
+			if !m.currentFocus.isEntry() {
+				return m, m.undo()
+			}
+		case key.Matches(msg, m.keys.redo):
+			if !m.currentFocus.isEntry() {
+				return m, m.redo()
+			}
+		case key.Matches(msg, m.keys.toggleTrash):
+			if !m.currentFocus.isEntry() {
+				return m, m.toggleTrash()
+			}
+		case key.Matches(msg, m.keys.search):
+			if !m.currentFocus.isEntry() {
+				return m, tea.Batch(m.searchForm.Init(), changeFocus(focusSearch))
+			}
+		case key.Matches(msg, m.keys.toggleSubprojects):
+			if m.currentFocus == focusProjects {
+				m.includeSubprojects = !m.includeSubprojects
+				return m, m.getUpdatedTasks(m.currentProjectID, m.selectedTaskID())
+			}
 		}
 	case refreshTasksMsg:
-		return m, m.getUpdatedTasks(m.currentProjectID, msg.selectTaskID)
+		cmd = m.getUpdatedTasks(m.currentProjectID, msg.selectTaskID)
+		if msg.info != "" {
+			cmd = tea.Batch(cmd, infoStatus(msg.info))
+		}
+		return m, cmd
 
 	case gotUpdatedTasksMsg:
+		if msg.keepSelection {
+			msg.selectTaskID = m.selectedTaskID()
+		}
 		cmds := []tea.Cmd{
 			m.taskList.SetItems(msg.tasks),
 			m.adjustTaskListIndex(),
@@ -153,20 +426,85 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// selected project ID. This is for cases like adding a project.
 		// The project ID is passed down the command chain and then it is
 		// set as the selected project once it reaches the end.
-		return m, m.refreshProjects(msg.selectProjectID)
+		cmd = m.refreshProjects(msg.selectProjectID)
+		if msg.info != "" {
+			cmd = tea.Batch(cmd, infoStatus(msg.info))
+		}
+		return m, cmd
+
+	case refreshAllMsg:
+		cmd = tea.Batch(
+			m.getTrash(),
+			m.refreshProjects(m.currentProjectID),
+			m.getUpdatedTasks(m.currentProjectID, m.selectedTaskID()),
+		)
+		if msg.info != "" {
+			cmd = tea.Batch(cmd, infoStatus(msg.info))
+		}
+		return m, cmd
+
+	case checkChangesMsg:
+		return m, m.checkDataVersion()
+	case dataVersionMsg:
+		return m, m.handleDataVersion(msg)
+
+	case filterAppliedMsg:
+		m.taskFilter = msg.filter
+		return m, tea.Batch(
+			m.getUpdatedTasks(m.currentProjectID, m.selectedTaskID()),
+			m.filterForm.Reset(),
+			changeFocus(focusTasks),
+		)
+
+	case filterFailedMsg:
+		m.filterForm.Error = msg.err
+		return m, nil
+
+	case gotSearchResultsMsg:
+		if msg.query != m.searchQuery {
+			return m, nil
+		}
+		m.searchList.Select(0)
+		return m, m.searchList.SetItems(msg.items)
+
+	case jumpToTaskMsg:
+		return m, m.jumpToTask(msg)
+
+	case gotTrashMsg:
+		return m, tea.Batch(m.trashList.SetItems(msg.items), m.calculateDimensions(m.windowW, m.windowH))
 
 	case gotUpdatedProjectsMsg:
+		if msg.keepSelection && m.currentView == nil {
+			msg.selectProjectID = m.currentProjectID
+		}
+		m.projects = msg.allProjects
 		cmds := []tea.Cmd{
 			m.projectList.SetItems(msg.projects),
-			m.checkProjectChanged(),
 			m.adjustProjectListIndex(),
 		}
 
-		if msg.selectProjectID != 0 {
+		switch {
+		case msg.selectProjectID != 0:
 			cmds = append(cmds, m.selectProject(msg.selectProjectID))
+		case m.currentView != nil:
+			// views come before the projects, so adding or removing one moves the rest
+			m.selectView(*m.currentView)
 		}
 
+		// only once the selection has settled, or a project that moved would look changed
+		cmds = append(cmds, m.checkProjectChanged())
 		return m, tea.Batch(cmds...)
+
+	case viewSavedMsg:
+		// a saved view replaces the filter it was made from
+		m.currentView = &msg.view
+		m.currentProjectID = 0
+		m.taskFilter = nil
+		return m, tea.Batch(
+			m.refreshProjects(0),
+			m.getUpdatedTasks(0, 0),
+			infoStatus(msg.info),
+		)
 	}
 
 	switch m.currentFocus {
@@ -178,6 +516,32 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				if len(m.taskList.Items()) > 0 {
 					return m, m.toggleTaskComplete(m.selectedTask())
 				}
+			case key.Matches(msg, m.keys.cycleSort):
+				return m, m.cycleSort(false)
+			case key.Matches(msg, m.keys.reverseSort):
+				return m, m.cycleSort(true)
+			case key.Matches(msg, m.keys.raisePriority):
+				if t := m.selectedTask(); t.Task != nil {
+					return m, m.setTaskPriority(t.Task, t.Priority.Raise())
+				}
+			case key.Matches(msg, m.keys.lowerPriority):
+				if t := m.selectedTask(); t.Task != nil {
+					return m, m.setTaskPriority(t.Task, t.Priority.Lower())
+				}
+			case key.Matches(msg, m.keys.moveTask):
+				m.pickTaskProject()
+				return m, nil
+			case key.Matches(msg, m.keys.archive):
+				m.archiveCompleted()
+				return m, nil
+			case key.Matches(msg, m.keys.moveUp):
+				return m, m.reorderTask(-1)
+			case key.Matches(msg, m.keys.moveDown):
+				return m, m.reorderTask(1)
+			case key.Matches(msg, m.keys.indent):
+				return m, m.indentTask()
+			case key.Matches(msg, m.keys.outdent):
+				return m, m.outdentTask()
 			}
 			m.taskList, cmd = m.taskList.Update(msg)
 			return m, cmd
@@ -186,11 +550,42 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case focusProjects:
 		switch msg := msg.(type) {
 		case tea.KeyMsg:
+			switch {
+			case key.Matches(msg, m.keys.moveUp):
+				return m, m.reorderProject(-1)
+			case key.Matches(msg, m.keys.moveDown):
+				return m, m.reorderProject(1)
+			}
 			m.projectList, cmd = m.projectList.Update(msg)
 			return m, tea.Batch(cmd, m.checkProjectChanged())
 		}
 
+	case focusTrash:
+		switch msg := msg.(type) {
+		case tea.KeyMsg:
+			switch {
+			case key.Matches(msg, m.keys.restore):
+				return m, m.restoreTrashItem()
+			case key.Matches(msg, m.keys.emptyTrash):
+				m.emptyTrash()
+				return m, nil
+			}
+			m.trashList, cmd = m.trashList.Update(msg)
+			return m, cmd
+		}
+
+	case focusMessages:
+		switch msg := msg.(type) {
+		case tea.KeyMsg:
+			m.messageView, cmd = m.messageView.Update(msg)
+			return m, cmd
+		}
+
 	case focusTaskEntry:
+		if m.editingTask != nil {
+			return m, m.updateTaskEditForm(msg)
+		}
+
 		f, cmd := m.taskEntryForm.Update(msg)
 		m.taskEntryForm = f.(*form.Model)
 
@@ -198,19 +593,127 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case tea.KeyMsg:
 			switch {
 			case key.Matches(msg, m.keys.cancelEntry):
+				m.newTaskParent = nil
 				return m, tea.Batch(m.taskEntryForm.Reset(), changeFocus(focusTasks))
 			}
 
 		case form.ResultMsg:
 			t := taskFromInputResult(msg.Result)
+			if strings.TrimSpace(t.Title) == "" {
+				// the title was all quick-add metadata, so leave it in the form to fix
+				return m, errorStatus("the title can't be empty")
+			}
+
+			projectID := m.currentProjectID
+			if m.newTaskParent != nil {
+				// subtasks always live in their parent's project
+				t.ParentTaskID = &m.newTaskParent.ID
+				if t.project != "" {
+					t.unresolved = append(t.unresolved, "#"+t.project)
+					t.project = ""
+				}
+				projectID = 0
+				if m.newTaskParent.ProjectID != nil {
+					projectID = *m.newTaskParent.ProjectID
+				}
+				m.newTaskParent = nil
+			}
+
 			return m, tea.Batch(
-				m.insertTask(t, m.currentProjectID),
+				m.insertTask(t, projectID),
 				m.taskEntryForm.Reset(),
 				changeFocus(focusTasks),
 			)
 		}
 		return m, cmd
 
+	case focusTagEntry, focusTagFilter:
+		f, cmd := m.tagForm.Update(msg)
+		m.tagForm = f.(*form.Model)
+
+		switch msg := msg.(type) {
+		case tea.KeyMsg:
+			switch {
+			case key.Matches(msg, m.keys.cancelEntry):
+				m.taggingTask = nil
+				return m, tea.Batch(m.tagForm.Reset(), changeFocus(focusTasks))
+			}
+
+		case form.ResultMsg:
+			if m.currentFocus == focusTagFilter {
+				m.tagFilter = parseTagFilter(msg.Result["tags"])
+				return m, tea.Batch(
+					m.getUpdatedTasks(m.currentProjectID, m.selectedTaskID()),
+					m.tagForm.Reset(),
+					changeFocus(focusTasks),
+				)
+			}
+
+			t := m.taggingTask
+			m.taggingTask = nil
+			return m, tea.Batch(
+				m.setTaskTags(t, parseTagList(msg.Result["tags"])),
+				m.tagForm.Reset(),
+				changeFocus(focusTasks),
+			)
+		}
+		return m, cmd
+
+	case focusFilterEntry:
+		f, cmd := m.filterForm.Update(msg)
+		m.filterForm = f.(*form.Model)
+
+		switch msg := msg.(type) {
+		case tea.KeyMsg:
+			if key.Matches(msg, m.keys.cancelEntry) {
+				return m, tea.Batch(m.filterForm.Reset(), changeFocus(focusTasks))
+			}
+
+		case form.ResultMsg:
+			return m, m.applyFilter(msg.Result["filter"])
+		}
+		return m, cmd
+
+	case focusSearch:
+		switch msg := msg.(type) {
+		case tea.KeyMsg:
+			switch {
+			case key.Matches(msg, m.keys.cancelEntry):
+				return m, m.closeSearch()
+			case key.Matches(msg, m.keys.prevResult):
+				m.searchList.CursorUp()
+				return m, nil
+			case key.Matches(msg, m.keys.nextResult):
+				m.searchList.CursorDown()
+				return m, nil
+			}
+
+		case form.ResultMsg:
+			return m, m.jumpToMatch()
+		}
+
+		f, cmd := m.searchForm.Update(msg)
+		m.searchForm = f.(*form.Model)
+		return m, tea.Batch(cmd, m.updateSearch())
+
+	case focusViewEntry:
+		f, cmd := m.viewEntryForm.Update(msg)
+		m.viewEntryForm = f.(*form.Model)
+
+		switch msg := msg.(type) {
+		case tea.KeyMsg:
+			if key.Matches(msg, m.keys.cancelEntry) {
+				m.editingView = nil
+				return m, tea.Batch(m.viewEntryForm.Reset(), changeFocus(focusProjects))
+			}
+
+		case form.ResultMsg:
+			save := m.saveView(msg.Result)
+			m.editingView = nil
+			return m, tea.Batch(save, m.viewEntryForm.Reset(), changeFocus(focusProjects))
+		}
+		return m, cmd
+
 	case focusProjectEntry:
 		f, cmd := m.projectEntryForm.Update(msg)
 		m.projectEntryForm = f.(*form.Model)
@@ -219,13 +722,25 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case tea.KeyMsg:
 			switch {
 			case key.Matches(msg, m.keys.cancelEntry):
+				m.editingProject = nil
 				return m, tea.Batch(m.projectEntryForm.Reset(), changeFocus(focusProjects))
 			}
 
 		case form.ResultMsg:
+			if m.editingProject != nil {
+				orig := m.editingProject
+				p := editedProjectFromInputResult(orig, msg.Result)
+				m.editingProject = nil
+				return m, tea.Batch(
+					m.updateProject(orig, p, msg.Result["parent"]),
+					m.projectEntryForm.Reset(),
+					changeFocus(focusProjects),
+				)
+			}
+
 			p := projectFromInputResult(msg.Result)
 			return m, tea.Batch(
-				m.insertProject(p),
+				m.insertProject(p, msg.Result["parent"]),
 				m.projectEntryForm.Reset(),
 				changeFocus(focusProjects),
 			)
@@ -241,14 +756,28 @@ func (m *model) View() string {
 		return "Initializing..."
 	}
 
-	return m.createFlexbox().Render(m.windowW, m.windowH)
+	v := m.createFlexbox().Render(m.windowW, m.windowH)
+	if m.modal != nil {
+		v = overlay.Center(v, m.modal.View(), m.windowW, m.windowH)
+	}
+	return v
 }
 
 func (m *model) createFlexbox() *fbox.Box {
-	hv := m.help.ShortHelpView(m.helpKeys())
+	// the help model can still overshoot its width by an item when there's no room left
+	// for its ellipsis, so cut it off here as well
+	hv := lipgloss.NewStyle().MaxWidth(m.help.Width).Render(m.help.ShortHelpView(m.helpKeys()))
 	return fbox.New(fbox.Vertical, 1).
 		AddFlexBox(m.createTopBox(), topBoxName, 7, nil, nil, nil).
+		AddTitleBox(m.createMessagesBox(), messagesName, 3, nil, nil, func() bool { return m.showMessages }).
 		AddTitleBox(m.createTaskEntryBox(), taskEntryName, 1, nil, nil, func() bool { return m.currentFocus == focusTaskEntry }).
 		AddTitleBox(m.createProjectEntryBox(), projEntryName, 1, nil, nil, func() bool { return m.currentFocus == focusProjectEntry }).
+		AddTitleBox(m.createTagEntryBox(), tagEntryName, 1, nil, nil, func() bool {
+			return m.currentFocus == focusTagEntry || m.currentFocus == focusTagFilter
+		}).
+		AddTitleBox(m.createFilterEntryBox(), filterEntryName, 1, nil, nil, func() bool { return m.currentFocus == focusFilterEntry }).
+		AddTitleBox(m.createViewEntryBox(), viewEntryName, 1, nil, nil, func() bool { return m.currentFocus == focusViewEntry }).
+		AddTitleBox(m.createSearchEntryBox(), searchEntryName, 1, nil, nil, func() bool { return m.currentFocus == focusSearch }).
+		AddStyleBox(statusStyle, statusName, m.statusView(), 1, nil, fbox.FixedSize(1), func() bool { return m.status != nil }).
 		AddStyleBox(helpStyle, helpViewName, hv, 1, nil, fbox.FixedSize(1), func() bool { return m.showHelp })
 }
@@ -25,6 +25,9 @@ type (
 		Key      string
 		Required bool
 		Validate textinput.ValidateFunc // func(string) error
+
+		// Preview, if set, is shown next to the input while it has a valid, non-empty value.
+		Preview func(string) string
 	}
 
 	Model struct {
@@ -106,16 +109,10 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			s := msg.String()
 
 			if s == "enter" && m.focusIndex == len(m.Inputs)-1 {
-				in := m.Inputs[m.focusIndex]
-				f := m.Fields[m.focusIndex]
-				if f.Required && strings.TrimSpace(in.Value()) == "" {
-					m.Error = fmt.Errorf("%s is required", f.Key)
-					return m, nil
-				}
-
-				if in.Err != nil {
-					m.Error = in.Err
-					return m, nil
+				if i, err := m.validate(); err != nil {
+					m.Error = err
+					m.focusIndex = i
+					return m, m.updateFocus()
 				}
 				return m, m.inputResultCmd()
 			}
@@ -126,22 +123,7 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.focusIndexUp()
 			}
 
-			cmds := make([]tea.Cmd, len(m.Inputs))
-			for i := 0; i <= len(m.Inputs)-1; i++ {
-				// if focused index, give focused style
-				if i == m.focusIndex {
-					cmds[i] = m.Inputs[i].Focus()
-					m.Inputs[i].PromptStyle = m.focusedStyle
-					m.Inputs[i].TextStyle = m.focusedStyle
-					continue
-				}
-				// otherwise, remove style
-				m.Inputs[i].Blur()
-				m.Inputs[i].PromptStyle = m.unfocusedStyle
-				m.Inputs[i].TextStyle = m.unfocusedStyle
-			}
-
-			return m, tea.Batch(cmds...)
+			return m, m.updateFocus()
 		}
 	}
 
@@ -155,6 +137,9 @@ func (m *Model) View() string {
 
 	for i := range m.Inputs {
 		b.WriteString(m.Inputs[i].View())
+		if p := m.preview(i); p != "" {
+			b.WriteString(m.unfocusedStyle.Render("  → " + p))
+		}
 		if i < len(m.Inputs)-1 {
 			b.WriteRune('\n')
 		}
@@ -173,7 +158,66 @@ func (m *Model) Reset() tea.Cmd {
 	}
 	m.Error = nil
 	m.focusIndex = 0
-	return nil
+	return m.updateFocus()
+}
+
+// updateFocus focuses the input at the current focus index and blurs the rest.
+func (m *Model) updateFocus() tea.Cmd {
+	cmds := make([]tea.Cmd, len(m.Inputs))
+	for i := 0; i <= len(m.Inputs)-1; i++ {
+		// if focused index, give focused style
+		if i == m.focusIndex {
+			cmds[i] = m.Inputs[i].Focus()
+			m.Inputs[i].PromptStyle = m.focusedStyle
+			m.Inputs[i].TextStyle = m.focusedStyle
+			continue
+		}
+		// otherwise, remove style
+		m.Inputs[i].Blur()
+		m.Inputs[i].PromptStyle = m.unfocusedStyle
+		m.Inputs[i].TextStyle = m.unfocusedStyle
+	}
+
+	return tea.Batch(cmds...)
+}
+
+// validate checks every field, returning the index and error of the first invalid one.
+func (m *Model) validate() (int, error) {
+	for i, in := range m.Inputs {
+		f := m.Fields[i]
+		if f.Required && strings.TrimSpace(in.Value()) == "" {
+			return i, fmt.Errorf("%s is required", f.Key)
+		}
+
+		if in.Err != nil {
+			return i, fmt.Errorf("%s: %w", f.Key, in.Err)
+		}
+	}
+
+	return 0, nil
+}
+
+func (m *Model) preview(i int) string {
+	f := m.Fields[i]
+	in := m.Inputs[i]
+	if f.Preview == nil || in.Err != nil || strings.TrimSpace(in.Value()) == "" {
+		return ""
+	}
+
+	return f.Preview(strings.TrimSpace(in.Value()))
+}
+
+// SetValues seeds the inputs with initial values, keyed by field key. Inputs for fields
+// without a value in r are left as they are.
+func (m *Model) SetValues(r Result) {
+	for i, f := range m.Fields {
+		v, ok := r[f.Key]
+		if !ok {
+			continue
+		}
+		m.Inputs[i].SetValue(v)
+		m.Inputs[i].CursorEnd()
+	}
 }
 
 func (m *Model) updateInputs(msg tea.Msg) tea.Cmd {
@@ -209,7 +253,7 @@ func (m *Model) inputResultCmd() tea.Cmd {
 }
 
 func (m *Model) focusIndexDown() {
-	top := len(m.Inputs)
+	top := len(m.Inputs) - 1
 	if m.focusIndex > 0 {
 		m.focusIndex--
 	} else {
@@ -218,7 +262,7 @@ func (m *Model) focusIndexDown() {
 }
 
 func (m *Model) focusIndexUp() {
-	top := len(m.Inputs)
+	top := len(m.Inputs) - 1
 	if m.focusIndex < top {
 		m.focusIndex++
 	} else {
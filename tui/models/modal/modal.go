@@ -0,0 +1,251 @@
+package modal
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/dsrosen6/yata/tui/render/titlebox"
+)
+
+const (
+	cancelLabel = "cancel"
+	minWidth    = 24
+)
+
+type (
+	Opts struct {
+		BoxStyle       lipgloss.Style
+		TitleStyle     lipgloss.Style
+		FocusedStyle   lipgloss.Style
+		UnfocusedStyle lipgloss.Style
+
+		// MaxWidth and MaxHeight limit the size of the rendered box, borders included.
+		MaxWidth  int
+		MaxHeight int
+	}
+
+	// Model is a dialog that takes all keys while it's open. It's either a confirmation,
+	// with its options side by side, or a picker, with a list of options to choose from.
+	// Either way, a ResultMsg is sent when it closes.
+	Model struct {
+		ID      string
+		title   string
+		body    string
+		options []string
+		cursor  int
+		confirm bool
+		opts    Opts
+
+		// offset is the first option shown when a picker has more than fit
+		offset int
+	}
+
+	// ResultMsg is sent when a modal closes, with the ID it was created with. Index and
+	// Value are the chosen option, unless Cancelled is set.
+	ResultMsg struct {
+		ID        string
+		Index     int
+		Value     string
+		Cancelled bool
+	}
+)
+
+// NewConfirm creates a yes or no question. The confirm label names the action, like
+// "delete", and is the only option that doesn't cancel. Cancel is selected at first, so
+// enter alone can't do any damage.
+func NewConfirm(id, title, body, confirmLabel string, o Opts) *Model {
+	return &Model{
+		ID:      id,
+		title:   title,
+		body:    body,
+		options: []string{confirmLabel, cancelLabel},
+		cursor:  1,
+		confirm: true,
+		opts:    o,
+	}
+}
+
+// NewPicker creates a list of options with the one at index selected.
+func NewPicker(id, title string, options []string, selected int, o Opts) *Model {
+	m := &Model{
+		ID:      id,
+		title:   title,
+		options: options,
+		opts:    o,
+	}
+	m.setCursor(selected)
+	return m
+}
+
+// SetMaxSize changes the space the modal can take, such as when the window is resized.
+func (m *Model) SetMaxSize(w, h int) {
+	m.opts.MaxWidth = w
+	m.opts.MaxHeight = h
+	m.setCursor(m.cursor)
+}
+
+func (m *Model) Update(msg tea.Msg) (*Model, tea.Cmd) {
+	k, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	switch k.String() {
+	case "esc", "q":
+		return m, m.cancel()
+	case "enter":
+		return m, m.choose(m.cursor)
+	}
+
+	if m.confirm {
+		switch k.String() {
+		case "y":
+			return m, m.choose(0)
+		case "n":
+			return m, m.cancel()
+		case "left", "right", "h", "l", "tab", "shift+tab":
+			m.cursor = 1 - m.cursor
+		}
+		return m, nil
+	}
+
+	switch k.String() {
+	case "up", "k", "shift+tab":
+		m.setCursor(m.cursor - 1)
+	case "down", "j", "tab":
+		m.setCursor(m.cursor + 1)
+	case "home", "g":
+		m.setCursor(0)
+	case "end", "G":
+		m.setCursor(len(m.options) - 1)
+	}
+
+	return m, nil
+}
+
+func (m *Model) View() string {
+	w := m.width()
+	lines := m.bodyLines(w)
+	if m.confirm {
+		if len(lines) > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, m.buttonsView())
+	} else {
+		lines = append(lines, m.optionsView(w)...)
+	}
+
+	return titlebox.New().
+		SetTitle(m.title).
+		SetBody(strings.Join(lines, "\n")).
+		SetTitleAlignment(titlebox.AlignLeft).
+		SetBoxStyle(m.opts.BoxStyle.Padding(0, 1)).
+		SetTitleStyle(m.opts.TitleStyle).
+		Render(w+2, len(lines))
+}
+
+// width is the width of the box's content, which is as wide as the widest line would
+// like to be, within the max width.
+func (m *Model) width() int {
+	w := max(minWidth, lipgloss.Width(m.opts.TitleStyle.Render(m.title))+2)
+	for _, l := range strings.Split(m.body, "\n") {
+		w = max(w, lipgloss.Width(l))
+	}
+
+	if m.confirm {
+		w = max(w, lipgloss.Width(m.buttonsView()))
+	} else {
+		for _, o := range m.options {
+			w = max(w, lipgloss.Width(o)+2)
+		}
+	}
+
+	// the border and one column of padding on each side
+	if m.opts.MaxWidth > 0 {
+		w = min(w, m.opts.MaxWidth-4)
+	}
+	return max(0, w)
+}
+
+func (m *Model) bodyLines(w int) []string {
+	if m.body == "" {
+		return nil
+	}
+
+	wrapped := m.opts.UnfocusedStyle.Width(w).Render(m.body)
+	return strings.Split(wrapped, "\n")
+}
+
+func (m *Model) buttonsView() string {
+	buttons := make([]string, len(m.options))
+	for i, o := range m.options {
+		style := m.opts.UnfocusedStyle
+		if i == m.cursor {
+			style = m.opts.FocusedStyle.Reverse(true)
+		}
+		buttons[i] = style.Render(" " + o + " ")
+	}
+
+	return strings.Join(buttons, "  ")
+}
+
+func (m *Model) optionsView(w int) []string {
+	end := min(len(m.options), m.offset+m.visibleOptions())
+	lines := make([]string, 0, end-m.offset)
+	for i := m.offset; i < end; i++ {
+		prefix, style := "  ", m.opts.UnfocusedStyle
+		if i == m.cursor {
+			prefix, style = "> ", m.opts.FocusedStyle
+		}
+		lines = append(lines, style.MaxWidth(w).Render(prefix+m.options[i]))
+	}
+
+	return lines
+}
+
+// visibleOptions is how many picker options fit in the max height, after the borders.
+func (m *Model) visibleOptions() int {
+	if m.opts.MaxHeight <= 0 {
+		return len(m.options)
+	}
+	return max(1, m.opts.MaxHeight-2-len(m.bodyLines(m.width())))
+}
+
+// setCursor moves the cursor, keeping it within the options and in view.
+func (m *Model) setCursor(i int) {
+	if len(m.options) == 0 {
+		m.cursor = 0
+		return
+	}
+
+	m.cursor = max(0, min(i, len(m.options)-1))
+	if m.confirm {
+		return
+	}
+
+	n := m.visibleOptions()
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	} else if m.cursor >= m.offset+n {
+		m.offset = m.cursor - n + 1
+	}
+}
+
+func (m *Model) choose(i int) tea.Cmd {
+	if len(m.options) == 0 || (m.confirm && m.options[i] == cancelLabel) {
+		return m.cancel()
+	}
+
+	id, v := m.ID, m.options[i]
+	return func() tea.Msg {
+		return ResultMsg{ID: id, Index: i, Value: v}
+	}
+}
+
+func (m *Model) cancel() tea.Cmd {
+	id := m.ID
+	return func() tea.Msg {
+		return ResultMsg{ID: id, Cancelled: true}
+	}
+}
@@ -2,4 +2,8 @@ package tui
 
 type (
 	storeErrorMsg struct{ error }
+
+	// refreshAllMsg reloads the tasks, projects and trash, for changes that can touch
+	// any of them, like trashing or undoing.
+	refreshAllMsg struct{ info string }
 )
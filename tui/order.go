@@ -0,0 +1,215 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dsrosen6/yata/models"
+)
+
+// positioned is an item's ID and position, for working out where it moves among its
+// siblings.
+type positioned struct {
+	id       int64
+	position float64
+}
+
+// movePositions moves the sibling at index i by delta places, returning the new position
+// of each sibling that changes. Siblings are in list order, where positions go up by step,
+// which is negative when the list is in descending order. The moved item normally takes a
+// position between its new neighbors, so it's the only one that changes, but when there's
+// no room left between them every sibling in all is renumbered. That's all of them from
+// the database in ascending order, since the list can hide some, like completed tasks or
+// ones that don't match a filter. It returns nil if the item can't move that far.
+func movePositions(sibs, all []positioned, i, delta int, step float64) map[int64]float64 {
+	j := i + delta
+	if i < 0 || j < 0 || j >= len(sibs) || j == i {
+		return nil
+	}
+
+	order := slices.Clone(sibs)
+	moved := order[i]
+	order = slices.Insert(slices.Delete(order, i, i+1), j, moved)
+
+	var pos float64
+	switch {
+	case j == 0:
+		pos = order[1].position - step
+	case j == len(order)-1:
+		pos = order[j-1].position + step
+	default:
+		pos = (order[j-1].position + order[j+1].position) / 2
+	}
+
+	// the halves eventually run out of precision, and positions can tie, so check the
+	// new one really does sort between its neighbors
+	fits := (j == 0 || (pos-order[j-1].position)*step > 0) &&
+		(j == len(order)-1 || (order[j+1].position-pos)*step > 0)
+	if fits {
+		return map[int64]float64{moved.id: pos}
+	}
+
+	// the hidden siblings keep their places, with the moved one put next to the neighbor
+	// it moved beside
+	full := slices.Clone(all)
+	if step < 0 {
+		slices.Reverse(full)
+	}
+	indexOf := func(id int64) int {
+		return slices.IndexFunc(full, func(p positioned) bool { return p.id == id })
+	}
+
+	k := indexOf(moved.id)
+	if k < 0 {
+		return nil
+	}
+	moved = full[k]
+	full = slices.Delete(full, k, k+1)
+
+	at := -1
+	if j > 0 {
+		if prev := indexOf(order[j-1].id); prev >= 0 {
+			at = prev + 1
+		}
+	} else {
+		at = indexOf(order[1].id)
+	}
+	if at < 0 {
+		// the list is behind the database, and the refresh after a change will catch it up
+		return nil
+	}
+	full = slices.Insert(full, at, moved)
+
+	changed := make(map[int64]float64, len(full))
+	for k, p := range full {
+		renumbered := float64(k + 1)
+		if step < 0 {
+			renumbered = float64(len(full) - k)
+		}
+		if renumbered != p.position {
+			changed[p.id] = renumbered
+		}
+	}
+
+	return changed
+}
+
+// positionsOf returns the current positions of the siblings with the provided IDs.
+func positionsOf(sibs []positioned, ids map[int64]float64) map[int64]float64 {
+	before := make(map[int64]float64, len(ids))
+	for _, s := range sibs {
+		if _, ok := ids[s.id]; ok {
+			before[s.id] = s.position
+		}
+	}
+
+	return before
+}
+
+// reorderTask moves the selected task up or down among the tasks with the same parent
+// and project. Moving only makes sense in manual order, so in any other order it switches
+// to manual first.
+func (m *model) reorderTask(delta int) tea.Cmd {
+	sel := m.selectedTask()
+	if sel.Task == nil {
+		return nil
+	}
+
+	if m.sortParams.SortBy != models.SortByManual {
+		m.sortParams.SortBy = models.SortByManual
+		return tea.Batch(
+			m.getUpdatedTasks(m.currentProjectID, sel.ID),
+			infoStatus(fmt.Sprintf("sorted by %s, %s", m.sortParams.SortBy, m.sortParams.SortOrder)),
+		)
+	}
+
+	var (
+		sibs []positioned
+		i    = -1
+	)
+	for _, item := range m.taskList.Items() {
+		t, ok := item.(taskItem)
+		if !ok || !models.SameID(t.ParentTaskID, sel.ParentTaskID) || !models.SameID(t.ProjectID, sel.ProjectID) {
+			continue
+		}
+		if t.ID == sel.ID {
+			i = len(sibs)
+		}
+		sibs = append(sibs, positioned{id: t.ID, position: t.Position})
+	}
+
+	step := 1.0
+	if m.sortParams.SortOrder == models.SortOrderDesc {
+		step = -1
+	}
+
+	stores := m.stores
+	return func() tea.Msg {
+		ctx := context.Background()
+		ts, err := stores.Tasks.ListSiblings(ctx, sel.ParentTaskID, sel.ProjectID)
+		if err != nil {
+			return storeErrorMsg{fmt.Errorf("listing tasks to reorder: %w", err)}
+		}
+
+		all := make([]positioned, 0, len(ts))
+		for _, t := range ts {
+			all = append(all, positioned{id: t.ID, position: t.Position})
+		}
+
+		after := movePositions(sibs, all, i, delta, step)
+		if after == nil {
+			return nil
+		}
+
+		c := taskReorder{title: sel.Title, before: positionsOf(all, after), after: after}
+		return m.applyChange(ctx, c, refreshTasksMsg{selectTaskID: sel.ID})
+	}
+}
+
+// reorderProject moves the selected project up or down among its siblings. Views always
+// stay where they are.
+func (m *model) reorderProject(delta int) tea.Cmd {
+	sel := m.selectedProject()
+	if sel == nil {
+		return nil
+	}
+
+	var (
+		sibs []positioned
+		i    = -1
+	)
+	for _, item := range m.projectList.Items() {
+		p, ok := item.(taskProjectItem)
+		if !ok || p.Project == nil || !models.SameID(p.ParentID, sel.ParentID) {
+			continue
+		}
+		if p.ID == sel.ID {
+			i = len(sibs)
+		}
+		sibs = append(sibs, positioned{id: p.ID, position: p.Position})
+	}
+
+	stores := m.stores
+	return func() tea.Msg {
+		ctx := context.Background()
+		ps, err := stores.Projects.ListSiblings(ctx, sel.ParentID)
+		if err != nil {
+			return storeErrorMsg{fmt.Errorf("listing projects to reorder: %w", err)}
+		}
+
+		all := make([]positioned, 0, len(ps))
+		for _, p := range ps {
+			all = append(all, positioned{id: p.ID, position: p.Position})
+		}
+
+		after := movePositions(sibs, all, i, delta, 1)
+		if after == nil {
+			return nil
+		}
+
+		c := projectReorder{title: sel.Title, before: positionsOf(all, after), after: after}
+		return m.applyChange(ctx, c, refreshProjectsMsg{selectProjectID: sel.ID})
+	}
+}
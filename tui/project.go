@@ -2,38 +2,59 @@ package tui
 
 import (
 	"context"
-	"errors"
 	"fmt"
+	"maps"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/dsrosen6/yata/models"
 	"github.com/dsrosen6/yata/tui/models/form"
+	"github.com/dsrosen6/yata/tui/models/modal"
 )
 
 type (
-	refreshProjectsMsg    struct{ selectProjectID int64 }
+	refreshProjectsMsg struct {
+		selectProjectID int64
+		info            string
+	}
 	gotUpdatedProjectsMsg struct {
 		projects        []list.Item
+		allProjects     []*models.Project
 		selectProjectID int64
+		keepSelection   bool
 	}
 )
 
+// checkProjectChanged refreshes the tasks when a different project or view is selected.
+// It changes the model, so it does its checking right away rather than in the command.
 func (m *model) checkProjectChanged() tea.Cmd {
-	return func() tea.Msg {
-		sel := m.selectedProjectID()
-		if m.currentProjectID != sel {
-			m.currentProjectID = sel
-			return refreshTasksMsg{selectTaskID: 0}
+	sel, ok := m.projectList.SelectedItem().(taskProjectItem)
+	if !ok {
+		return nil
+	}
+
+	refresh := func() tea.Msg { return refreshTasksMsg{selectTaskID: 0} }
+	if sel.view != nil {
+		if m.currentView == nil || *m.currentView != *sel.view {
+			m.currentView = sel.view
+			m.currentProjectID = 0
+			return refresh
 		}
 		return nil
 	}
+
+	if m.currentView != nil || m.currentProjectID != sel.ID {
+		m.currentView = nil
+		m.currentProjectID = sel.ID
+		return refresh
+	}
+	return nil
 }
 
 func (m *model) selectProject(id int64) tea.Cmd {
 	for i, item := range m.projectList.Items() {
-		if p, ok := item.(taskProjectItem); ok && p.ID == id {
+		if p, ok := item.(taskProjectItem); ok && p.Project != nil && p.ID == id {
 			m.projectList.Select(i)
 			break
 		}
@@ -50,37 +71,119 @@ func (m *model) adjustProjectListIndex() tea.Cmd {
 }
 
 func (m *model) refreshProjects(selectProjectID int64) tea.Cmd {
+	// toggling a project in Update while this runs would otherwise race on the map
+	collapsed := maps.Clone(m.collapsedProjects)
+	stores := m.stores
+
 	return func() tea.Msg {
-		projects, err := m.stores.Projects.ListAll(context.Background())
+		ctx := context.Background()
+		projects, err := stores.Projects.ListAll(ctx)
+		if err != nil {
+			return storeErrorMsg{fmt.Errorf("listing projects: %w", err)}
+		}
+
+		views, err := stores.Views.ListAll(ctx)
 		if err != nil {
-			return storeErrorMsg{err}
+			return storeErrorMsg{fmt.Errorf("listing views: %w", err)}
 		}
-		items := append([]list.Item{}, projectsToItems(projects)...)
+
+		items := projectsToItems(allViews(views), projects, collapsed)
 		return gotUpdatedProjectsMsg{
 			projects:        items,
+			allProjects:     projects,
 			selectProjectID: selectProjectID,
 		}
 	}
 }
 
-func (m *model) insertProject(p taskProjectItem) tea.Cmd {
+// toggleProjectCollapsed expands or collapses the sub-projects of the selected project.
+func (m *model) toggleProjectCollapsed() tea.Cmd {
+	sel := m.selectedProject()
+	if sel == nil || !sel.hasChildren {
+		return nil
+	}
+
+	m.collapsedProjects[sel.ID] = !m.collapsedProjects[sel.ID]
+	return m.refreshProjects(sel.ID)
+}
+
+// insertProject creates a project. The parent is provided as a path of project titles,
+// which is resolved to an ID when the command runs.
+func (m *model) insertProject(p taskProjectItem, parentPath string) tea.Cmd {
+	return func() tea.Msg {
+		ctx := context.Background()
+		parentID, err := m.stores.ResolveProjectPath(ctx, parentPath)
+		if err != nil {
+			return storeErrorMsg{fmt.Errorf("finding parent project: %w", err)}
+		}
+		p.ParentID = parentID
+
+		c := &projectCreate{project: p.Project}
+		if err := c.apply(ctx, m.stores); err != nil {
+			return storeErrorMsg{fmt.Errorf("creating project: %w", err)}
+		}
+
+		return appliedMsg{change: c, msg: refreshProjectsMsg{selectProjectID: c.project.ID, info: "project created"}}
+	}
+}
+
+func (m *model) updateProject(orig, p *models.Project, parentPath string) tea.Cmd {
 	return func() tea.Msg {
-		created, err := m.stores.Projects.Create(context.Background(), p.Project)
+		ctx := context.Background()
+		parentID, err := m.stores.ResolveProjectPath(ctx, parentPath)
 		if err != nil {
-			return storeErrorMsg{err}
+			return storeErrorMsg{fmt.Errorf("finding parent project: %w", err)}
 		}
+		p.ParentID = parentID
 
-		return refreshProjectsMsg{selectProjectID: created.ID}
+		c := projectUpdate{before: orig, after: p}
+		return m.applyChange(ctx, c, refreshProjectsMsg{selectProjectID: p.ID, info: "project saved"})
 	}
 }
 
-func (m *model) deleteProject(id int64) tea.Cmd {
+// trashProject moves a project to the trash, first asking if anything would go with it.
+func (m *model) trashProject(p *models.Project) tea.Cmd {
+	c := projectTrash{id: p.ID, title: p.Title}
+	trash := func() tea.Msg {
+		return m.applyChange(context.Background(), c, refreshAllMsg{info: "project moved to trash"})
+	}
+
+	// the command runs off the update loop, so it mustn't read the model
+	stores, opts := m.stores, m.modalOpts()
+	body := func(contents []string) string {
+		return fmt.Sprintf("Move %q and its %s to the trash?", p.Title, strings.Join(contents, " and "))
+	}
+
 	return func() tea.Msg {
-		if err := m.stores.Projects.Delete(context.Background(), id); err != nil {
-			return storeErrorMsg{err}
+		ctx := context.Background()
+		tasks, err := stores.Tasks.ListByProjectTree(ctx, p.ID)
+		if err != nil {
+			return storeErrorMsg{fmt.Errorf("listing tasks in project %d: %w", p.ID, err)}
+		}
+
+		ids, err := stores.Projects.ListDescendantIDs(ctx, p.ID)
+		if err != nil {
+			return storeErrorMsg{fmt.Errorf("listing sub-projects of %d: %w", p.ID, err)}
 		}
 
-		return refreshProjectsMsg{selectProjectID: 0}
+		// the project itself is included in its descendants
+		subprojects := len(ids) - 1
+		if len(tasks) == 0 && subprojects == 0 {
+			return trash()
+		}
+
+		var contents []string
+		if len(tasks) > 0 {
+			contents = append(contents, fmt.Sprintf("%d %s", len(tasks), plural(len(tasks), "task")))
+		}
+		if subprojects > 0 {
+			contents = append(contents, fmt.Sprintf("%d %s", subprojects, plural(subprojects, "sub-project")))
+		}
+
+		return showModalMsg{
+			modal:    modal.NewConfirm("trashProject", "trash project", body(contents), "trash", opts),
+			onChoose: func(modal.ResultMsg) tea.Cmd { return trash },
+		}
 	}
 }
 
@@ -108,8 +211,8 @@ func (m *model) selectedProject() *taskProjectItem {
 
 func newProjectEntryForm() (*form.Model, error) {
 	fn := func(s string) error {
-		if strings.TrimSpace(s) == "all" {
-			return errors.New("'all' is a reserved filter and cannot be used")
+		if strings.Contains(s, models.ProjectPathSep) {
+			return fmt.Errorf("project titles cannot contain %q", models.ProjectPathSep)
 		}
 		return nil
 	}
@@ -120,6 +223,9 @@ func newProjectEntryForm() (*form.Model, error) {
 			Required: true,
 			Validate: fn,
 		},
+		{
+			Key: "parent",
+		},
 	}
 	o := &form.Opts{
 		Fields:           fields,
@@ -150,11 +256,72 @@ func projectFromInputResult(r form.Result) taskProjectItem {
 	}
 }
 
-func projectsToItems(projects []*models.Project) []list.Item {
-	// Start with the static "all" entry
-	items := []list.Item{taskProjectItem{&models.Project{Title: "all"}}}
+// editedProjectFromInputResult returns a copy of the original project with the values
+// from the edit form applied.
+func editedProjectFromInputResult(orig *models.Project, r form.Result) *models.Project {
+	p := *orig
+	if title, ok := r["title"]; ok {
+		p.Title = title
+	}
+
+	return &p
+}
+
+// projectToInputValues returns a project's current values for seeding the entry form.
+func projectToInputValues(p *models.Project, projects []*models.Project) form.Result {
+	parent := ""
+	if p.ParentID != nil {
+		parent = models.ProjectPath(projects, *p.ParentID)
+	}
+
+	return form.Result{
+		"title":  p.Title,
+		"parent": parent,
+	}
+}
+
+// projectsToItems lists the views, then flattens the project tree into list items in
+// display order, with each project directly after its parent. Children of collapsed
+// projects are left out.
+func projectsToItems(views []view, projects []*models.Project, collapsed map[int64]bool) []list.Item {
+	items := make([]list.Item, 0, len(views)+len(projects))
+	for _, v := range views {
+		items = append(items, taskProjectItem{view: &v})
+	}
+
+	ids := make(map[int64]bool, len(projects))
+	for _, p := range projects {
+		ids[p.ID] = true
+	}
+
+	// projects whose parent is missing are treated as roots so they're never hidden
+	var roots []*models.Project
+	children := make(map[int64][]*models.Project)
 	for _, p := range projects {
-		items = append(items, taskProjectItem{p})
+		if p.ParentID == nil || !ids[*p.ParentID] {
+			roots = append(roots, p)
+			continue
+		}
+		children[*p.ParentID] = append(children[*p.ParentID], p)
+	}
+
+	var walk func(ps []*models.Project, depth int)
+	walk = func(ps []*models.Project, depth int) {
+		for _, p := range ps {
+			kids := children[p.ID]
+			items = append(items, taskProjectItem{
+				Project:     p,
+				depth:       depth,
+				hasChildren: len(kids) > 0,
+				collapsed:   collapsed[p.ID],
+			})
+
+			if !collapsed[p.ID] {
+				walk(kids, depth+1)
+			}
+		}
 	}
+	walk(roots, 0)
+
 	return items
 }
@@ -0,0 +1,51 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/dsrosen6/yata/models"
+	"github.com/dsrosen6/yata/recur"
+)
+
+const recurMarker = "↻"
+
+func validateRepeatInput(s string) error {
+	if strings.TrimSpace(s) == "" {
+		return nil
+	}
+
+	_, err := recur.Parse(s)
+	return err
+}
+
+func previewRepeatInput(s string) string {
+	if strings.TrimSpace(s) == "" {
+		return ""
+	}
+
+	r, err := recur.Parse(s)
+	if err != nil {
+		return ""
+	}
+	return r.Describe()
+}
+
+// formatRepeatInput turns a stored rule back into a phrase for the task form.
+func formatRepeatInput(rule string) string {
+	if rule == "" {
+		return ""
+	}
+
+	r, err := recur.Parse(rule)
+	if err != nil {
+		return rule
+	}
+	return r.Describe()
+}
+
+// applyRepeatInput sets the task's recurrence from the repeat field. A repeating task
+// without a due date gets its first occurrence from today as the due date.
+func applyRepeatInput(t *models.Task, s string) {
+	// the field is validated by the form, so it's safe to ignore the error
+	_ = t.SetRecurrence(s)
+}
@@ -0,0 +1,42 @@
+package overlay
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// reset clears styling at the edges of the overlay, so neither side's colors bleed
+// into the other.
+const reset = "\x1b[0m"
+
+// Center draws fg over the middle of bg, which is w cells wide and h lines tall.
+func Center(bg, fg string, w, h int) string {
+	fw, fh := lipgloss.Size(fg)
+	return Place(bg, fg, max(0, (w-fw)/2), max(0, (h-fh)/2))
+}
+
+// Place draws fg over bg with its top left corner at column x of line y. Whatever is
+// under fg is replaced, and the rest of bg keeps its styling. Lines of fg that fall
+// below bg are dropped.
+func Place(bg, fg string, x, y int) string {
+	lines := strings.Split(bg, "\n")
+	for i, line := range strings.Split(fg, "\n") {
+		row := y + i
+		if row < 0 || row >= len(lines) {
+			continue
+		}
+
+		under := lines[row]
+		if w := ansi.StringWidth(under); w < x {
+			under += strings.Repeat(" ", x-w)
+		}
+
+		left := ansi.Truncate(under, x, "")
+		right := ansi.TruncateLeft(under, x+ansi.StringWidth(line), "")
+		lines[row] = left + reset + line + reset + right
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,310 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/dsrosen6/yata/models"
+	"github.com/dsrosen6/yata/tui/models/form"
+	"github.com/dsrosen6/yata/tui/render/titlebox"
+)
+
+const maxSearchResults = 200
+
+type (
+	// searchItem is a task found by a search, with the path of its project, which is empty
+	// if it isn't in one.
+	searchItem struct {
+		*models.TaskMatch
+		project string
+	}
+	searchItemDelegate  struct{}
+	gotSearchResultsMsg struct {
+		query string
+		items []list.Item
+	}
+
+	// jumpToTaskMsg shows a task in its project. ancestors are the IDs of the tasks above
+	// it, which are expanded so it's in the list.
+	jumpToTaskMsg struct {
+		task      *models.Task
+		ancestors []int64
+	}
+)
+
+func (s searchItem) FilterValue() string {
+	return s.Task.Title
+}
+
+func (d searchItemDelegate) Height() int {
+	return 1
+}
+
+func (d searchItemDelegate) Spacing() int {
+	return 0
+}
+
+func (d searchItemDelegate) Update(_ tea.Msg, _ *list.Model) tea.Cmd {
+	return nil
+}
+
+func (d searchItemDelegate) Render(w io.Writer, m list.Model, index int, listItem list.Item) {
+	i, ok := listItem.(searchItem)
+	if !ok {
+		return
+	}
+
+	checked := "󰄱"
+	if i.Task.Complete {
+		checked = "󰄵"
+	}
+
+	style := allStyles.unfocusedTextStyle
+	if index == m.Index() {
+		style = allStyles.focusedTextStyle
+	}
+
+	project := "#" + i.project
+	if i.project == "" {
+		project = noProjectOption
+	}
+
+	str := style.Render(checked+" ") + renderMatch(i.Title, style)
+	_, _ = fmt.Fprint(w, str+" "+allStyles.unfocusedTextStyle.Faint(true).Render(project))
+}
+
+// renderMatch renders a title from a search, with its matched terms in bold and underlined.
+func renderMatch(title string, style lipgloss.Style) string {
+	var b strings.Builder
+	for {
+		start := strings.Index(title, models.MatchStart)
+		if start < 0 {
+			break
+		}
+
+		b.WriteString(style.Render(title[:start]))
+		title = title[start+len(models.MatchStart):]
+
+		end := strings.Index(title, models.MatchEnd)
+		if end < 0 {
+			end = len(title)
+		}
+		b.WriteString(style.Bold(true).Underline(true).Render(title[:end]))
+		title = strings.TrimPrefix(title[end:], models.MatchEnd)
+	}
+
+	b.WriteString(style.Render(title))
+	return b.String()
+}
+
+func initialSearchList() list.Model {
+	ls := list.New(nil, searchItemDelegate{}, 10, 10)
+	ls.SetShowStatusBar(false)
+	ls.SetShowTitle(false)
+	ls.SetShowHelp(false)
+	ls.SetFilteringEnabled(false)
+	return ls
+}
+
+func newSearchForm() (*form.Model, error) {
+	fields := []form.Field{
+		{
+			Key: "search",
+		},
+	}
+	o := &form.Opts{
+		Fields:         fields,
+		FocusedStyle:   allStyles.focusedTextStyle,
+		UnfocusedStyle: allStyles.unfocusedTextStyle,
+		ErrorStyle:     allStyles.errorTextStyle,
+	}
+
+	f, err := form.InitialInputModel(o)
+	if err != nil {
+		return nil, fmt.Errorf("creating model: %w", err)
+	}
+
+	return f, nil
+}
+
+// createSearchBox shows the results of a search in place of the task list.
+func (m *model) createSearchBox() titlebox.Box {
+	boxStyle := allStyles.focusedBoxStyle
+	border := boxStyle.GetBorderStyle().Top
+	title := "[2]" + border + "search"
+
+	body := m.searchList.View()
+	switch n := len(m.searchList.Items()); {
+	case strings.TrimSpace(m.searchQuery) == "":
+		body = allStyles.unfocusedTextStyle.Render("Type to search tasks in every project.")
+	case n == 0:
+		body = allStyles.unfocusedTextStyle.Render("No matching tasks.")
+	default:
+		title += border + fmt.Sprintf("%d %s", n, plural(n, "result"))
+	}
+
+	return titlebox.New().
+		SetTitle(title).
+		SetBody(body).
+		SetTitleAlignment(titlebox.AlignLeft).
+		SetBoxStyle(boxStyle.Padding(0, 1)).
+		SetTitleStyle(allStyles.focusedBoxTitleStyle)
+}
+
+func (m *model) createSearchEntryBox() titlebox.Box {
+	return titlebox.New().
+		SetTitle("search all tasks").
+		SetBody(m.searchForm.View()).
+		SetTitleAlignment(titlebox.AlignLeft).
+		SetBoxStyle(allStyles.focusedBoxStyle).
+		SetTitleStyle(allStyles.focusedBoxTitleStyle)
+}
+
+// updateSearch searches again whenever the query changes. Results come back with the
+// query they're for, so ones for an older query can be dropped.
+func (m *model) updateSearch() tea.Cmd {
+	query := m.searchForm.Inputs[0].Value()
+	if query == m.searchQuery {
+		return nil
+	}
+
+	m.searchQuery = query
+	if strings.TrimSpace(query) == "" {
+		return m.searchList.SetItems(nil)
+	}
+
+	projects := m.projects
+	return func() tea.Msg {
+		matches, err := m.stores.Tasks.Search(context.Background(), query, maxSearchResults)
+		if err != nil {
+			return storeErrorMsg{fmt.Errorf("searching tasks: %w", err)}
+		}
+
+		items := make([]list.Item, len(matches))
+		for i, match := range matches {
+			item := searchItem{TaskMatch: match}
+			if match.Task.ProjectID != nil {
+				item.project = models.ProjectPath(projects, *match.Task.ProjectID)
+			}
+			items[i] = item
+		}
+
+		return gotSearchResultsMsg{query: query, items: items}
+	}
+}
+
+func (m *model) closeSearch() tea.Cmd {
+	m.searchQuery = ""
+	return tea.Batch(m.searchList.SetItems(nil), m.searchForm.Reset(), changeFocus(focusTasks))
+}
+
+// jumpToMatch closes the search and shows the selected task in its project. If enter
+// was pressed before any results came back, it goes to the best match.
+func (m *model) jumpToMatch() tea.Cmd {
+	var t *models.Task
+	if item, ok := m.searchList.SelectedItem().(searchItem); ok {
+		t = item.Task
+	}
+
+	query := m.searchQuery
+	if t == nil && strings.TrimSpace(query) == "" {
+		return m.closeSearch()
+	}
+
+	return tea.Batch(m.closeSearch(), func() tea.Msg {
+		if t == nil {
+			matches, err := m.stores.Tasks.Search(context.Background(), query, 1)
+			if err != nil {
+				return storeErrorMsg{fmt.Errorf("searching tasks: %w", err)}
+			}
+			if len(matches) == 0 {
+				return infoStatus(fmt.Sprintf("no tasks match %q", query))()
+			}
+			t = matches[0].Task
+		}
+
+		var ancestors []int64
+		for id := t.ParentTaskID; id != nil; {
+			parent, err := m.stores.Tasks.Get(context.Background(), *id)
+			if err != nil {
+				return storeErrorMsg{fmt.Errorf("getting task %d: %w", *id, err)}
+			}
+
+			ancestors = append(ancestors, parent.ID)
+			id = parent.ParentTaskID
+		}
+
+		return jumpToTaskMsg{task: t, ancestors: ancestors}
+	})
+}
+
+// jumpToTask selects a task's project, or the inbox, and then the task. Anything that would keep it out
+// of the list, like a collapsed parent or a filter, is cleared first.
+func (m *model) jumpToTask(msg jumpToTaskMsg) tea.Cmd {
+	t := msg.task
+	for _, id := range msg.ancestors {
+		delete(m.collapsedTasks, id)
+	}
+
+	var projectID int64
+	if t.ProjectID != nil {
+		projectID = *t.ProjectID
+		m.expandProjectAncestors(projectID)
+	}
+
+	var info string
+	if m.tagFilter.active() || m.nextActions || m.taskFilter != nil {
+		m.tagFilter = tagFilter{}
+		m.nextActions = false
+		m.taskFilter = nil
+		info = fmt.Sprintf("cleared filters to show %q", t.Title)
+	}
+
+	m.showTrash = false
+	m.currentProjectID = projectID
+	m.currentView = nil
+	if projectID != 0 {
+		m.selectProject(projectID)
+	} else {
+		// tasks without a project are all in the inbox
+		inbox := inboxView
+		m.currentView = &inbox
+		m.selectView(inbox)
+	}
+
+	cmds := []tea.Cmd{
+		m.refreshProjects(projectID),
+		m.getUpdatedTasks(projectID, t.ID),
+		changeFocus(focusTasks),
+	}
+	if info != "" {
+		cmds = append(cmds, infoStatus(info))
+	}
+
+	return tea.Batch(cmds...)
+}
+
+// expandProjectAncestors expands every project above the one with the provided ID, so
+// it's shown in the project list.
+func (m *model) expandProjectAncestors(id int64) {
+	byID := make(map[int64]*models.Project, len(m.projects))
+	for _, p := range m.projects {
+		byID[p.ID] = p
+	}
+
+	// the length check guards against looping forever on a cycle in bad data
+	for n := 0; n < len(m.projects); n++ {
+		p, ok := byID[id]
+		if !ok || p.ParentID == nil {
+			return
+		}
+
+		delete(m.collapsedProjects, *p.ParentID)
+		id = *p.ParentID
+	}
+}
@@ -24,33 +24,42 @@ type dimensions struct {
 	topBoxLayout    fbox.ItemLayout
 	taskEntryLayout fbox.ItemLayout
 	projEntryLayout fbox.ItemLayout
+	messagesLayout  fbox.ItemLayout
+	detailLayout    fbox.ItemLayout
+	statusLayout    fbox.ItemLayout
 	helpLayout      fbox.ItemLayout
 }
 
+// calculateDimensions lays out the view at the given size. The layout renders the lists, which
+// Update changes, so it's worked out right away and only the result is sent from the command.
 func (m *model) calculateDimensions(w, h int) tea.Cmd {
-	return func() tea.Msg {
-		d := &dimensions{}
-		d.windowW = w
-		d.windowH = h
+	d := dimensions{}
+	d.windowW = w
+	d.windowH = h
 
-		box := m.createFlexbox()
-		rendered := box.Render(w, h)
+	box := m.createFlexbox()
+	rendered := box.Render(w, h)
 
-		d.topBoxLayout = box.LayoutsHandler.GetLayout(topBoxName)
-		d.taskEntryLayout = box.LayoutsHandler.GetLayout(taskEntryName)
-		d.projEntryLayout = box.LayoutsHandler.GetLayout(projEntryName)
-		d.helpLayout = box.LayoutsHandler.GetLayout(helpViewName)
+	d.topBoxLayout = box.LayoutsHandler.GetLayout(topBoxName)
+	d.taskEntryLayout = box.LayoutsHandler.GetLayout(taskEntryName)
+	d.projEntryLayout = box.LayoutsHandler.GetLayout(projEntryName)
+	d.messagesLayout = box.LayoutsHandler.GetLayout(messagesName)
+	d.statusLayout = box.LayoutsHandler.GetLayout(statusName)
+	d.helpLayout = box.LayoutsHandler.GetLayout(helpViewName)
 
-		d.renderedW = lipgloss.Width(rendered)
-		d.renderedH = lipgloss.Height(rendered)
+	d.renderedW = lipgloss.Width(rendered)
+	d.renderedH = lipgloss.Height(rendered)
 
-		tb := m.createTopBox()
-		d.topBoxMaxFrameW, d.topBoxMaxFrameH = tb.GetMaxItemFrameSize()
-		d.projBoxW = 15
-		d.projDelegMaxW = d.projBoxW - d.topBoxMaxFrameW
-		d.listsH = d.topBoxLayout.ContentHeight - d.topBoxMaxFrameH
-		return dimensionsCalculatedMsg{*d}
-	}
+	tb := m.createTopBox()
+	d.topBoxMaxFrameW, d.topBoxMaxFrameH = tb.GetMaxItemFrameSize()
+	d.projBoxW = 15
+	d.projDelegMaxW = d.projBoxW - d.topBoxMaxFrameW
+	d.listsH = d.topBoxLayout.ContentHeight - d.topBoxMaxFrameH
+
+	// the detail pane is nested in the top box, so it's only laid out when that is rendered
+	tb.Render(d.topBoxLayout.ContentWidth, d.topBoxLayout.ContentHeight)
+	d.detailLayout = tb.LayoutsHandler.GetLayout(detailName)
+	return func() tea.Msg { return dimensionsCalculatedMsg{d} }
 }
 
 func (m *model) logDimensions() {
@@ -68,6 +77,9 @@ func (m *model) logDimensions() {
 		layoutLogGrp(d.topBoxLayout),
 		layoutLogGrp(d.taskEntryLayout),
 		layoutLogGrp(d.projEntryLayout),
+		layoutLogGrp(d.messagesLayout),
+		layoutLogGrp(d.detailLayout),
+		layoutLogGrp(d.statusLayout),
 		layoutLogGrp(d.helpLayout),
 	)
 }
@@ -0,0 +1,137 @@
+package tui
+
+import (
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dsrosen6/yata/tui/render/titlebox"
+)
+
+const (
+	statusTimeout    = 4 * time.Second
+	maxStatusEntries = 200
+)
+
+type (
+	statusLevel int
+	statusEntry struct {
+		level statusLevel
+		text  string
+		at    time.Time
+	}
+
+	// statusMsg shows a transient message in the status bar and adds it to the history.
+	statusMsg      struct{ statusEntry }
+	clearStatusMsg struct{ id int }
+)
+
+const (
+	statusInfo statusLevel = iota
+	statusError
+)
+
+func infoStatus(text string) tea.Cmd {
+	return func() tea.Msg {
+		return statusMsg{statusEntry{level: statusInfo, text: text, at: time.Now()}}
+	}
+}
+
+func errorStatus(text string) tea.Cmd {
+	return func() tea.Msg {
+		return statusMsg{statusEntry{level: statusError, text: text, at: time.Now()}}
+	}
+}
+
+// handleStoreError logs a failed store operation and shows it in the status bar.
+func (m *model) handleStoreError(err error) tea.Cmd {
+	slog.Error("store operation failed", "error", err, "focus", m.currentFocus.toString())
+	return m.pushStatus(statusEntry{level: statusError, text: err.Error(), at: time.Now()})
+}
+
+// pushStatus shows a message in the status bar, records it in the message history, and
+// schedules it to be cleared. Each message gets an ID so an older timer doesn't clear a
+// newer message.
+func (m *model) pushStatus(e statusEntry) tea.Cmd {
+	m.status = &e
+	m.statusID++
+	m.messages = append(m.messages, e)
+	if len(m.messages) > maxStatusEntries {
+		m.messages = m.messages[len(m.messages)-maxStatusEntries:]
+	}
+	m.updateMessageView()
+
+	id := m.statusID
+	return tea.Batch(
+		tea.Tick(statusTimeout, func(time.Time) tea.Msg { return clearStatusMsg{id: id} }),
+		m.calculateDimensions(m.windowW, m.windowH),
+	)
+}
+
+func (m *model) clearStatus(id int) tea.Cmd {
+	if id != m.statusID || m.status == nil {
+		return nil
+	}
+
+	m.status = nil
+	return m.calculateDimensions(m.windowW, m.windowH)
+}
+
+func (m *model) statusView() string {
+	if m.status == nil {
+		return ""
+	}
+
+	return renderStatusEntry(*m.status, false)
+}
+
+func renderStatusEntry(e statusEntry, withTime bool) string {
+	text := e.text
+	if withTime {
+		text = e.at.Format("15:04:05") + " " + text
+	}
+
+	if e.level == statusError {
+		return allStyles.errorTextStyle.Render(text)
+	}
+	return allStyles.unfocusedTextStyle.Render(text)
+}
+
+// updateMessageView refreshes the message history pane, newest message first.
+func (m *model) updateMessageView() {
+	lines := make([]string, 0, len(m.messages))
+	for i := len(m.messages) - 1; i >= 0; i-- {
+		lines = append(lines, renderStatusEntry(m.messages[i], true))
+	}
+
+	if len(lines) == 0 {
+		lines = append(lines, allStyles.unfocusedTextStyle.Render("No messages."))
+	}
+
+	m.messageView.SetContent(strings.Join(lines, "\n"))
+}
+
+func newMessageView() viewport.Model {
+	return viewport.New(0, 0)
+}
+
+func (m *model) createMessagesBox() titlebox.Box {
+	boxStyle := allStyles.unfocusedBoxStyle
+	titleStyle := allStyles.unfocusedBoxTitleStyle
+	if m.currentFocus == focusMessages {
+		boxStyle = allStyles.focusedBoxStyle
+		titleStyle = allStyles.focusedBoxTitleStyle
+	}
+
+	border := boxStyle.GetBorderStyle().Top
+	title := "[3]" + border + "messages"
+
+	return titlebox.New().
+		SetTitle(title).
+		SetBody(m.messageView.View()).
+		SetTitleAlignment(titlebox.AlignLeft).
+		SetBoxStyle(boxStyle.Padding(0, 1)).
+		SetTitleStyle(titleStyle)
+}
@@ -13,6 +13,10 @@ type styles struct {
 	unfocusedBoxTitleStyle lipgloss.Style
 	unfocusedTextStyle     lipgloss.Style
 	errorTextStyle         lipgloss.Style
+	dueOverdueStyle        lipgloss.Style
+	dueTodayStyle          lipgloss.Style
+	dueUpcomingStyle       lipgloss.Style
+	tagStyle               lipgloss.Style
 }
 
 func generateStyles(cfg *config.Config) styles {
@@ -24,5 +28,9 @@ func generateStyles(cfg *config.Config) styles {
 		unfocusedBoxTitleStyle: lipgloss.NewStyle().Foreground(cfg.Unfocused.BoxTitleColor),
 		unfocusedTextStyle:     lipgloss.NewStyle().Foreground(cfg.Unfocused.TextColor),
 		errorTextStyle:         lipgloss.NewStyle().Foreground(cfg.ErrorTextColor),
+		dueOverdueStyle:        lipgloss.NewStyle().Foreground(cfg.Due.OverdueColor),
+		dueTodayStyle:          lipgloss.NewStyle().Foreground(cfg.Due.TodayColor),
+		dueUpcomingStyle:       lipgloss.NewStyle().Foreground(cfg.Due.UpcomingColor),
+		tagStyle:               lipgloss.NewStyle().Foreground(cfg.TagColor),
 	}
 }
@@ -0,0 +1,151 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dsrosen6/yata/models"
+	"github.com/dsrosen6/yata/tui/models/form"
+)
+
+const tagSigil = "+"
+
+// tagFilter limits the task list to tasks with all (or, if any is true, at least one)
+// of the tag names.
+type tagFilter struct {
+	names []string
+	any   bool
+}
+
+func (f tagFilter) active() bool {
+	return len(f.names) > 0
+}
+
+func (f tagFilter) matches(tags []string) bool {
+	for _, n := range f.names {
+		has := slices.ContainsFunc(tags, func(t string) bool { return strings.EqualFold(t, n) })
+		if has && f.any {
+			return true
+		}
+		if !has && !f.any {
+			return false
+		}
+	}
+
+	return !f.any
+}
+
+func (f tagFilter) String() string {
+	sep := " "
+	if f.any {
+		sep = "|"
+	}
+
+	return tagSigil + strings.Join(f.names, sep+tagSigil)
+}
+
+// tagFilterInput is the current filter as it would be typed, for seeding the filter form.
+func (m *model) tagFilterInput() string {
+	sep := " "
+	if m.tagFilter.any {
+		sep = "|"
+	}
+
+	return strings.Join(m.tagFilter.names, sep)
+}
+
+// parseTagFilter parses filter input like "work phone" (tasks with both tags) or
+// "work|phone" (tasks with either tag).
+func parseTagFilter(s string) tagFilter {
+	return tagFilter{
+		names: parseTagList(s),
+		any:   strings.Contains(s, "|"),
+	}
+}
+
+// parseTagList splits tag input on spaces, commas and pipes, dropping any leading +
+// and duplicates.
+func parseTagList(s string) []string {
+	fields := strings.FieldsFunc(s, func(r rune) bool {
+		return r == ' ' || r == ',' || r == '|'
+	})
+
+	var names []string
+	for _, f := range fields {
+		f = strings.TrimPrefix(f, tagSigil)
+		if f == "" || slices.ContainsFunc(names, func(n string) bool { return strings.EqualFold(n, f) }) {
+			continue
+		}
+		names = append(names, f)
+	}
+
+	return names
+}
+
+func (m *model) setTaskTags(t *taskItem, newTags []string) tea.Cmd {
+	c := taskTags{id: t.ID, title: t.Title, before: t.tags, after: newTags}
+	return func() tea.Msg {
+		return m.applyChange(context.Background(), c, refreshTasksMsg{selectTaskID: t.ID, info: "tags saved"})
+	}
+}
+
+// setTags adds and removes tags so the task ends up with exactly the new set.
+func setTags(ctx context.Context, s *models.AllRepos, taskID int64, oldTags, newTags []string) error {
+	for _, t := range newTags {
+		if !containsFold(oldTags, t) {
+			if err := s.Tags.AddToTask(ctx, taskID, t); err != nil {
+				return fmt.Errorf("adding tag %q to task %d: %w", t, taskID, err)
+			}
+		}
+	}
+
+	for _, t := range oldTags {
+		if !containsFold(newTags, t) {
+			if err := s.Tags.RemoveFromTask(ctx, taskID, t); err != nil {
+				return fmt.Errorf("removing tag %q from task %d: %w", t, taskID, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func tagNamesByTaskID(tags map[int64][]*models.Tag) map[int64][]string {
+	names := make(map[int64][]string, len(tags))
+	for id, ts := range tags {
+		for _, t := range ts {
+			names[id] = append(names[id], t.Name)
+		}
+	}
+
+	return names
+}
+
+func containsFold(s []string, v string) bool {
+	return slices.ContainsFunc(s, func(e string) bool { return strings.EqualFold(e, v) })
+}
+
+func newTagEntryForm() (*form.Model, error) {
+	fields := []form.Field{
+		{
+			Key: "tags",
+		},
+	}
+	o := &form.Opts{
+		Fields:           fields,
+		PromptIfOneField: true,
+		FocusedStyle:     allStyles.focusedTextStyle,
+		UnfocusedStyle:   allStyles.unfocusedTextStyle,
+		ErrorStyle:       allStyles.errorTextStyle,
+	}
+
+	f, err := form.InitialInputModel(o)
+	if err != nil {
+		return nil, fmt.Errorf("creating model: %w", err)
+	}
+
+	return f, nil
+}
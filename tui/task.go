@@ -2,22 +2,47 @@ package tui
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"maps"
+	"slices"
+	"strings"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/dsrosen6/yata/models"
+	"github.com/dsrosen6/yata/quickadd"
 	"github.com/dsrosen6/yata/tui/models/form"
+	"github.com/dsrosen6/yata/tui/models/modal"
 )
 
 type (
+	// taskInput is a new task from the entry form, along with quick-add metadata that
+	// still needs to be resolved against the store.
+	taskInput struct {
+		taskItem
+		project    string
+		tags       []string
+		unresolved []string
+	}
+
+	// taskMeta holds what's known about tasks beyond their own fields, keyed by task ID.
+	taskMeta struct {
+		tags      map[int64][]string
+		blockedBy map[int64][]int64
+		blocked   map[int64]bool
+	}
+
 	refreshTasksMsg struct {
 		projectID    int64
 		selectTaskID int64
+		info         string
 	}
 	gotUpdatedTasksMsg struct {
-		tasks        []list.Item
-		selectTaskID int64
+		tasks         []list.Item
+		selectTaskID  int64
+		keepSelection bool
 	}
 )
 
@@ -52,6 +77,23 @@ func (m *model) adjustTaskListIndex() tea.Cmd {
 // which will later be used to update the visible list. It takes a project ID to filter by project,
 // and a task ID to be later used to select the proper task once refreshed.
 func (m *model) getUpdatedTasks(projectID, selectTaskID int64) tea.Cmd {
+	// the command runs on its own goroutine while Update carries on changing the model,
+	// so it only gets copies of what it needs
+	var (
+		stores             = m.stores
+		taskFilter         = m.taskFilter
+		tagFilter          = m.tagFilter
+		includeSubprojects = m.includeSubprojects
+		nextActions        = m.nextActions
+		sortParams         = *m.sortParams
+		collapsed          = maps.Clone(m.collapsedTasks)
+		currentView        *view
+	)
+	if m.currentView != nil {
+		v := *m.currentView
+		currentView = &v
+	}
+
 	return func() tea.Msg {
 		var (
 			tasks []*models.Task
@@ -60,18 +102,61 @@ func (m *model) getUpdatedTasks(projectID, selectTaskID int64) tea.Cmd {
 
 		ctx := context.Background()
 
-		// if no project ID is provided, assume the "all" view in the projects list is selected
-		if m.currentProjectID == 0 {
-			tasks, err = m.stores.Tasks.ListAll(ctx)
-		} else {
-			tasks, err = m.stores.Tasks.ListByProjectID(ctx, projectID)
+		// a filter can name its own projects, so it looks at every task
+		switch {
+		case taskFilter != nil:
+			tasks, err = stores.Tasks.Query(ctx, taskFilter)
+		case currentView != nil:
+			tasks, err = currentView.tasks(ctx, stores)
+		case includeSubprojects:
+			tasks, err = stores.Tasks.ListByProjectTree(ctx, projectID)
+		default:
+			tasks, err = stores.Tasks.ListByProjectID(ctx, projectID)
+		}
+
+		if err != nil {
+			return storeErrorMsg{fmt.Errorf("listing tasks: %w", err)}
+		}
+
+		tags, err := stores.Tags.ListAllByTaskID(ctx)
+		if err != nil {
+			return storeErrorMsg{fmt.Errorf("listing tags: %w", err)}
 		}
 
+		blockedBy, err := stores.Tasks.ListDependencies(ctx)
 		if err != nil {
-			return storeErrorMsg{err}
+			return storeErrorMsg{fmt.Errorf("listing dependencies: %w", err)}
 		}
 
-		items := append([]list.Item{}, tasksToItems(tasks)...)
+		blockedIDs, err := stores.Tasks.ListBlockedIDs(ctx)
+		if err != nil {
+			return storeErrorMsg{fmt.Errorf("listing blocked tasks: %w", err)}
+		}
+
+		meta := taskMeta{
+			tags:      tagNamesByTaskID(tags),
+			blockedBy: blockedBy,
+			blocked:   make(map[int64]bool, len(blockedIDs)),
+		}
+		for _, id := range blockedIDs {
+			meta.blocked[id] = true
+		}
+
+		tasks = slices.DeleteFunc(tasks, func(t *models.Task) bool {
+			if tagFilter.active() && !tagFilter.matches(meta.tags[t.ID]) {
+				return true
+			}
+			return nextActions && (t.Complete || meta.blocked[t.ID])
+		})
+
+		var items []list.Item
+		if taskFilter == nil && currentView != nil && currentView.history {
+			// completed tasks are already in the order they were finished
+			items = completedToItems(tasks, meta)
+		} else {
+			models.SortTasks(tasks, sortParams)
+			items = append(items, tasksToItems(tasks, collapsed, meta)...)
+		}
 		return gotUpdatedTasksMsg{
 			tasks:        items,
 			selectTaskID: selectTaskID,
@@ -79,42 +164,423 @@ func (m *model) getUpdatedTasks(projectID, selectTaskID int64) tea.Cmd {
 	}
 }
 
-func (m *model) insertTask(t taskItem, projectID int64) tea.Cmd {
+// insertTask creates a task in the provided project, unless the entry names a project
+// with quick-add syntax, in which case that one is used.
+func (m *model) insertTask(t taskInput, projectID int64) tea.Cmd {
+	stores, createProjects, currentProjectID := m.stores, m.cfg.QuickAddCreateProjects, m.currentProjectID
 	return func() tea.Msg {
-		if projectID != 0 {
-			t.ProjectID = &projectID
-		}
+		ctx := context.Background()
+		var (
+			c               *taskCreate
+			projectsChanged bool
+		)
+		// any projects are created with the task, so a failed create doesn't leave them behind
+		err := stores.InTx(ctx, func(s *models.AllRepos) error {
+			projectsChanged = false
+			if t.project != "" {
+				var (
+					id  *int64
+					err error
+				)
+				if createProjects {
+					id, projectsChanged, err = s.EnsureProjectPath(ctx, t.project)
+				} else {
+					id, err = s.ResolveProjectPath(ctx, t.project)
+				}
 
-		created, err := m.stores.Tasks.Create(context.Background(), t.Task)
+				if err != nil {
+					return err
+				}
+				projectID = *id
+			}
+
+			task := *t.Task
+			if projectID != 0 {
+				task.ProjectID = &projectID
+			}
+
+			c = &taskCreate{task: &task, tags: t.tags}
+			return c.apply(ctx, s)
+		})
 		if err != nil {
-			return storeErrorMsg{err}
+			return storeErrorMsg{fmt.Errorf("creating task: %w", err)}
+		}
+		created := c.task
+
+		info := "task created"
+		if len(t.unresolved) > 0 {
+			info += ", ignored " + strings.Join(t.unresolved, " ")
 		}
 
-		return refreshTasksMsg{
+		refresh := refreshTasksMsg{
 			projectID:    projectID,
 			selectTaskID: created.ID,
+			info:         info,
 		}
+
+		if projectsChanged {
+			return appliedMsg{change: c, msg: tea.BatchMsg{
+				func() tea.Msg { return refreshProjectsMsg{selectProjectID: currentProjectID} },
+				func() tea.Msg { return refresh },
+			}}
+		}
+		return appliedMsg{change: c, msg: refresh}
 	}
 }
 
-func (m *model) deleteTask(id int64) tea.Cmd {
+func (m *model) updateTask(before, after *models.Task) tea.Cmd {
+	c := taskUpdate{before: before, after: after}
 	return func() tea.Msg {
-		if err := m.stores.Tasks.Delete(context.Background(), id); err != nil {
-			return storeErrorMsg{err}
+		return m.applyChange(context.Background(), c, refreshTasksMsg{selectTaskID: after.ID, info: "task saved"})
+	}
+}
+
+// updateTaskEditForm passes a message to the edit form, saving the task when it's submitted.
+func (m *model) updateTaskEditForm(msg tea.Msg) tea.Cmd {
+	f, cmd := m.taskEditForm.Update(msg)
+	m.taskEditForm = f.(*form.Model)
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if key.Matches(msg, m.keys.cancelEntry) {
+			m.editingTask, m.editingTaskValues = nil, nil
+			return tea.Batch(m.taskEditForm.Reset(), changeFocus(focusTasks))
+		}
+
+	case form.ResultMsg:
+		orig, seeded := m.editingTask, m.editingTaskValues
+		m.editingTask, m.editingTaskValues = nil, nil
+		return tea.Batch(
+			m.editTask(orig, seeded, msg.Result),
+			m.taskEditForm.Reset(),
+			changeFocus(focusTasks),
+		)
+	}
+
+	return cmd
+}
+
+// editTask saves the edit form. A new project moves the task there with its subtasks, and
+// a new parent, given by title, makes it a subtask in the parent's project. Fields left
+// as they were seeded aren't looked up again.
+func (m *model) editTask(orig *models.Task, seeded, r form.Result) tea.Cmd {
+	stores := m.stores
+	after := editedTaskFromInputResult(orig, r)
+	projectPath, projectSet := strings.TrimSpace(r["project"]), r["project"] != seeded["project"]
+	parentTitle, parentSet := strings.TrimSpace(r["parent"]), r["parent"] != seeded["parent"]
+
+	return func() tea.Msg {
+		ctx := context.Background()
+		var changes changeSet
+		err := stores.InTx(ctx, func(s *models.AllRepos) error {
+			changes = nil
+			projectID, parentID := orig.ProjectID, orig.ParentTaskID
+			if projectSet {
+				id, err := s.ResolveProjectPath(ctx, projectPath)
+				if err != nil {
+					return err
+				}
+				projectID = id
+			}
+
+			if parentSet {
+				parentID = nil
+				if parentTitle != "" {
+					parent, err := findParentTask(ctx, s, orig, parentTitle)
+					if err != nil {
+						return err
+					}
+					// subtasks always live in their parent's project
+					if projectSet && !models.SameID(projectID, parent.ProjectID) {
+						return errors.New("subtasks are always in their parent's project")
+					}
+					parentID, projectID = &parent.ID, parent.ProjectID
+				}
+			}
+
+			cur := orig
+			if !models.SameID(orig.ProjectID, projectID) {
+				moved, subtasks, err := s.MoveTask(ctx, orig, projectID)
+				if err != nil {
+					return err
+				}
+				for _, st := range subtasks {
+					a := *st
+					a.ProjectID = projectID
+					changes = append(changes, taskUpdate{before: st, after: &a})
+				}
+				cur = moved
+			}
+
+			t := *after
+			t.ProjectID, t.ParentTaskID, t.Position = projectID, parentID, cur.Position
+			updated, err := s.Tasks.Update(ctx, &t)
+			if err != nil {
+				return err
+			}
+			changes = append(changeSet{taskUpdate{before: orig, after: updated}}, changes...)
+			return nil
+		})
+		if err != nil {
+			return storeErrorMsg{fmt.Errorf("edit task %q: %w", orig.Title, err)}
+		}
+
+		return appliedMsg{change: changes, msg: refreshTasksMsg{selectTaskID: orig.ID, info: "task saved"}}
+	}
+}
+
+// findParentTask finds the task titled title to be t's parent. Like a new subtask's parent,
+// it must be a task that isn't in the trash, and it can't be t or one of t's subtasks.
+func findParentTask(ctx context.Context, s *models.AllRepos, t *models.Task, title string) (*models.Task, error) {
+	tasks, err := s.Tasks.ListAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing tasks: %w", err)
+	}
+
+	var found []*models.Task
+	for _, c := range tasks {
+		if strings.EqualFold(c.Title, title) && c.DeletedAt == nil {
+			found = append(found, c)
 		}
+	}
+
+	switch {
+	case len(found) == 0:
+		return nil, fmt.Errorf("no task titled %q", title)
+	case len(found) > 1:
+		return nil, fmt.Errorf("%d tasks are titled %q", len(found), title)
+	}
+	parent := found[0]
 
-		return refreshTasksMsg{selectTaskID: 0}
+	subtasks, err := s.ListSubtasks(ctx, t.ID)
+	if err != nil {
+		return nil, err
 	}
+	if parent.ID == t.ID || slices.ContainsFunc(subtasks, func(st *models.Task) bool { return st.ID == parent.ID }) {
+		return nil, fmt.Errorf("%q can't be a subtask of itself or one of its subtasks", t.Title)
+	}
+
+	return parent, nil
 }
 
+func (m *model) trashTask(t *models.Task) tea.Cmd {
+	c := taskTrash{id: t.ID, title: t.Title}
+	return func() tea.Msg {
+		return m.applyChange(context.Background(), c, refreshAllMsg{info: "task moved to trash"})
+	}
+}
+
+// toggleTaskComplete completes or uncompletes a task as a single change, along with the
+// next occurrence of a repeating task and, if configured, the task's subtasks.
 func (m *model) toggleTaskComplete(t taskItem) tea.Cmd {
+	stores, cascade := m.stores, m.cfg.CascadeComplete
+	return func() tea.Msg {
+		ctx := context.Background()
+		blockedBefore, err := stores.Tasks.ListBlockedIDs(ctx)
+		if err != nil {
+			return storeErrorMsg{fmt.Errorf("listing blocked tasks: %w", err)}
+		}
+
+		var (
+			changes changeSet
+			next    *models.Task
+		)
+		if t.Complete {
+			after := *t.Task
+			after.Complete = false
+			changes = changeSet{taskUpdate{before: t.Task, after: &after}}
+			if err := changes.apply(ctx, stores); err != nil {
+				return storeErrorMsg{fmt.Errorf("%s: %w", changes, err)}
+			}
+		} else {
+			c, err := stores.CompleteTask(ctx, t.Task, cascade)
+			if err != nil {
+				return storeErrorMsg{fmt.Errorf("complete task %q: %w", t.Title, err)}
+			}
+			changes, next = completionChanges(t.Task, c), c.Next
+		}
+
+		blockedAfter, err := stores.Tasks.ListBlockedIDs(ctx)
+		if err != nil {
+			return storeErrorMsg{fmt.Errorf("listing blocked tasks: %w", err)}
+		}
+
+		var info []string
+		if n := countUnblocked(blockedBefore, blockedAfter); n > 0 {
+			info = append(info, fmt.Sprintf("unblocked %d %s", n, plural(n, "task")))
+		}
+
+		if next != nil {
+			info = append(info, "next due "+previewDueInput(formatDueInput(next.DueAt)))
+			return appliedMsg{change: changes, msg: refreshTasksMsg{selectTaskID: next.ID, info: strings.Join(info, ", ")}}
+		}
+
+		return appliedMsg{change: changes, msg: refreshTasksMsg{selectTaskID: t.ID, info: strings.Join(info, ", ")}}
+	}
+}
+
+func (m *model) setTaskPriority(orig *models.Task, p models.Priority) tea.Cmd {
+	if orig.Priority == p {
+		return nil
+	}
+
+	t := *orig
+	t.Priority = p
+	c := taskUpdate{before: orig, after: &t}
+	return func() tea.Msg {
+		return m.applyChange(context.Background(), c, refreshTasksMsg{selectTaskID: t.ID, info: "priority " + p.String()})
+	}
+}
+
+// cycleSort switches to the next sort option, or flips the order if reverse is true.
+func (m *model) cycleSort(reverse bool) tea.Cmd {
+	if reverse {
+		m.sortParams.SortOrder = (m.sortParams.SortOrder + 1) % 2
+	} else {
+		next := 0
+		for i, sb := range models.SortBys {
+			if sb == m.sortParams.SortBy {
+				next = (i + 1) % len(models.SortBys)
+				break
+			}
+		}
+		m.sortParams.SortBy = models.SortBys[next]
+	}
+
+	return tea.Batch(
+		m.getUpdatedTasks(m.currentProjectID, m.selectedTaskID()),
+		infoStatus(fmt.Sprintf("sorted by %s, %s", m.sortParams.SortBy, m.sortParams.SortOrder)),
+	)
+}
+
+// completionChanges records a completion made by the store as the changes to undo and redo
+// it. Undoing it trashes the next occurrence of a repeating task, and redoing restores it.
+func completionChanges(before *models.Task, c *models.Completion) changeSet {
+	changes := changeSet{taskUpdate{before: before, after: c.Task}}
+	if c.Next != nil {
+		changes = append(changes, &taskCreate{task: c.Next})
+	}
+
+	for _, s := range c.Subtasks {
+		done := *s
+		done.Complete = true
+		changes = append(changes, taskUpdate{before: s, after: &done})
+	}
+
+	return changes
+}
+
+// pickTaskProject asks which project to move the selected task to.
+func (m *model) pickTaskProject() {
+	sel := m.selectedTask()
+	if sel.Task == nil {
+		return
+	}
+
+	type choice struct {
+		id   *int64
+		path string
+	}
+
+	choices := make([]choice, 0, len(m.projects))
+	for _, p := range m.projects {
+		choices = append(choices, choice{id: &p.ID, path: models.ProjectPath(m.projects, p.ID)})
+	}
+	slices.SortFunc(choices, func(a, b choice) int { return strings.Compare(a.path, b.path) })
+	choices = append([]choice{{path: noProjectOption}}, choices...)
+
+	options := make([]string, len(choices))
+	selected := 0
+	for i, c := range choices {
+		options[i] = c.path
+		if models.SameID(c.id, sel.ProjectID) {
+			selected = i
+		}
+	}
+
+	md := modal.NewPicker("moveTask", "move "+sel.Title+" to", options, selected, m.modalOpts())
+	m.openModal(md, func(r modal.ResultMsg) tea.Cmd {
+		return m.moveTask(sel.Task, choices[r.Index].id, r.Value)
+	})
+}
+
+// moveTask moves a task and its subtasks to another project. Subtasks live in their
+// parent's project, so a subtask being moved is taken out of its parent.
+func (m *model) moveTask(t *models.Task, projectID *int64, name string) tea.Cmd {
+	if models.SameID(t.ProjectID, projectID) {
+		return infoStatus("already in " + name)
+	}
+
+	stores := m.stores
+	return func() tea.Msg {
+		moved, subtasks, err := stores.MoveTask(context.Background(), t, projectID)
+		if err != nil {
+			return storeErrorMsg{fmt.Errorf("move task %q: %w", t.Title, err)}
+		}
+
+		changes := changeSet{taskUpdate{before: t, after: moved}}
+		for _, s := range subtasks {
+			after := *s
+			after.ProjectID = projectID
+			changes = append(changes, taskUpdate{before: s, after: &after})
+		}
+
+		return appliedMsg{change: changes, msg: refreshTasksMsg{selectTaskID: t.ID, info: "moved to " + name}}
+	}
+}
+
+// toggleTaskCollapsed expands or collapses the subtasks of the selected task.
+func (m *model) toggleTaskCollapsed() tea.Cmd {
+	sel := m.selectedTask()
+	if sel.Task == nil || sel.childCount == 0 {
+		return nil
+	}
+
+	m.collapsedTasks[sel.ID] = !m.collapsedTasks[sel.ID]
+	return m.getUpdatedTasks(m.currentProjectID, sel.ID)
+}
+
+// indentTask makes the selected task a subtask of the sibling directly above it.
+func (m *model) indentTask() tea.Cmd {
+	sel := m.selectedTask()
+	if sel.Task == nil {
+		return nil
+	}
+
+	items := m.taskList.Items()
+	for i := m.taskList.Index() - 1; i >= 0; i-- {
+		prev, ok := items[i].(taskItem)
+		if !ok || prev.depth < sel.depth {
+			return nil
+		}
+
+		if prev.depth == sel.depth {
+			t := *sel.Task
+			t.ParentTaskID = &prev.ID
+			t.ProjectID = prev.ProjectID
+			return m.updateTask(sel.Task, &t)
+		}
+	}
+
+	return nil
+}
+
+// outdentTask moves the selected task up one level, making it a sibling of its parent.
+func (m *model) outdentTask() tea.Cmd {
+	sel := m.selectedTask()
+	if sel.Task == nil || sel.ParentTaskID == nil {
+		return nil
+	}
+
 	return func() tea.Msg {
-		t.Complete = !t.Complete
-		if _, err := m.stores.Tasks.Update(context.Background(), t.Task); err != nil {
-			return storeErrorMsg{err}
+		parent, err := m.stores.Tasks.Get(context.Background(), *sel.ParentTaskID)
+		if err != nil {
+			return storeErrorMsg{fmt.Errorf("getting parent of task %d: %w", sel.ID, err)}
 		}
 
-		return refreshTasksMsg{selectTaskID: t.ID}
+		t := *sel.Task
+		t.ParentTaskID = parent.ParentTaskID
+		return m.updateTask(sel.Task, &t)()
 	}
 }
 
@@ -145,6 +611,57 @@ func newTaskEntryForm() (*form.Model, error) {
 			Key:      "title",
 			Required: true,
 		},
+		{
+			Key:      "due",
+			Validate: validateDueInput,
+			Preview:  previewDueInput,
+		},
+		{
+			Key:      "repeat",
+			Validate: validateRepeatInput,
+			Preview:  previewRepeatInput,
+		},
+	}
+	o := &form.Opts{
+		Fields:           fields,
+		PromptIfOneField: true,
+		FocusedStyle:     allStyles.focusedTextStyle,
+		UnfocusedStyle:   allStyles.unfocusedTextStyle,
+		ErrorStyle:       allStyles.errorTextStyle,
+	}
+
+	f, err := form.InitialInputModel(o)
+	if err != nil {
+		return nil, fmt.Errorf("creating model: %w", err)
+	}
+
+	return f, nil
+}
+
+// newTaskEditForm is the entry form with the fields only an existing task has. The project
+// and parent are set with quick-add syntax and the selection when a task is created.
+func newTaskEditForm() (*form.Model, error) {
+	fields := []form.Field{
+		{
+			Key:      "title",
+			Required: true,
+		},
+		{
+			Key: "project",
+		},
+		{
+			Key: "parent",
+		},
+		{
+			Key:      "due",
+			Validate: validateDueInput,
+			Preview:  previewDueInput,
+		},
+		{
+			Key:      "repeat",
+			Validate: validateRepeatInput,
+			Preview:  previewRepeatInput,
+		},
 	}
 	o := &form.Opts{
 		Fields:           fields,
@@ -162,23 +679,126 @@ func newTaskEntryForm() (*form.Model, error) {
 	return f, nil
 }
 
-func taskFromInputResult(r form.Result) taskItem {
-	t, ok := r["title"]
+// taskFromInputResult builds a new task from the entry form. The title may use quick-add
+// syntax to set metadata inline; a date in the due field takes priority over one in the title.
+func taskFromInputResult(r form.Result) taskInput {
+	title, ok := r["title"]
 	if !ok {
-		return taskItem{}
+		return taskInput{}
 	}
 
-	return taskItem{
-		Task: &models.Task{
-			Title: t,
-		},
+	qa := quickadd.Parse(title, dueParser)
+	in := taskInput{
+		taskItem:   taskItem{Task: qa.Task},
+		project:    qa.Project,
+		tags:       qa.Tags,
+		unresolved: qa.Unresolved,
+	}
+
+	if qa.Priority != "" {
+		p, err := models.ParsePriority(qa.Priority)
+		if err != nil {
+			in.unresolved = append(in.unresolved, "!"+qa.Priority)
+		}
+		in.Priority = p
+	}
+
+	// the due field is validated by the form, so it's safe to ignore the error
+	if due, _ := parseDueInput(r["due"]); due != nil {
+		in.DueAt = due
 	}
+
+	applyRepeatInput(in.Task, r["repeat"])
+	return in
 }
 
-func tasksToItems(tasks []*models.Task) []list.Item {
-	var items []list.Item
+// editedTaskFromInputResult returns a copy of the original task with the values
+// from the edit form applied.
+func editedTaskFromInputResult(orig *models.Task, r form.Result) *models.Task {
+	t := *orig
+	if title, ok := r["title"]; ok {
+		t.Title = title
+	}
+
+	if due, ok := r["due"]; ok {
+		t.DueAt, _ = parseDueInput(due)
+	}
+
+	if repeat, ok := r["repeat"]; ok {
+		applyRepeatInput(&t, repeat)
+	}
+
+	return &t
+}
+
+// taskToInputValues returns a task's current values for seeding the edit form.
+func (m *model) taskToInputValues(t *models.Task) form.Result {
+	project, parent := "", ""
+	if t.ProjectID != nil {
+		project = models.ProjectPath(m.projects, *t.ProjectID)
+	}
+	if t.ParentTaskID != nil {
+		parent = m.taskTitle(*t.ParentTaskID)
+	}
+
+	return form.Result{
+		"title":   t.Title,
+		"project": project,
+		"parent":  parent,
+		"due":     formatDueInput(t.DueAt),
+		"repeat":  formatRepeatInput(t.Recurrence),
+	}
+}
+
+// tasksToItems flattens the task tree into list items in display order, with each
+// subtask directly after its parent. Subtasks of collapsed tasks are left out.
+func tasksToItems(tasks []*models.Task, collapsed map[int64]bool, meta taskMeta) []list.Item {
+	ids := make(map[int64]bool, len(tasks))
 	for _, t := range tasks {
-		items = append(items, taskItem{t})
+		ids[t.ID] = true
 	}
+
+	// tasks whose parent isn't in the list (for example, it's in another project)
+	// are treated as roots so they're never hidden
+	var roots []*models.Task
+	children := make(map[int64][]*models.Task)
+	for _, t := range tasks {
+		if t.ParentTaskID == nil || !ids[*t.ParentTaskID] {
+			roots = append(roots, t)
+			continue
+		}
+		children[*t.ParentTaskID] = append(children[*t.ParentTaskID], t)
+	}
+
+	var items []list.Item
+	var walk func(ts []*models.Task, depth int)
+	walk = func(ts []*models.Task, depth int) {
+		for _, t := range ts {
+			kids := children[t.ID]
+			done := 0
+			for _, k := range kids {
+				if k.Complete {
+					done++
+				}
+			}
+
+			items = append(items, taskItem{
+				Task:           t,
+				depth:          depth,
+				collapsed:      collapsed[t.ID],
+				childCount:     len(kids),
+				childDoneCount: done,
+				tags:           meta.tags[t.ID],
+				blockedBy:      meta.blockedBy[t.ID],
+				blocked:        meta.blocked[t.ID],
+			})
+
+			if !collapsed[t.ID] {
+				walk(kids, depth+1)
+			}
+		}
+	}
+	walk(roots, 0)
+
 	return items
 }
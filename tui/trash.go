@@ -0,0 +1,265 @@
+package tui
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dsrosen6/yata/models"
+	"github.com/dsrosen6/yata/tui/render/titlebox"
+)
+
+type (
+	// trashItem is a trashed task or project; exactly one of the two is set.
+	trashItem struct {
+		task    *models.Task
+		project *models.Project
+	}
+	trashItemDelegate struct{}
+	gotTrashMsg       struct{ items []list.Item }
+)
+
+func (t trashItem) title() string {
+	if t.project != nil {
+		return t.project.Title
+	}
+	return t.task.Title
+}
+
+func (t trashItem) deletedAt() time.Time {
+	var d *time.Time
+	if t.project != nil {
+		d = t.project.DeletedAt
+	} else {
+		d = t.task.DeletedAt
+	}
+
+	if d == nil {
+		return time.Time{}
+	}
+	return *d
+}
+
+func (t trashItem) FilterValue() string {
+	return t.title()
+}
+
+func (d trashItemDelegate) Height() int {
+	return 1
+}
+
+func (d trashItemDelegate) Spacing() int {
+	return 0
+}
+
+func (d trashItemDelegate) Update(_ tea.Msg, _ *list.Model) tea.Cmd {
+	return nil
+}
+
+func (d trashItemDelegate) Render(w io.Writer, m list.Model, index int, listItem list.Item) {
+	i, ok := listItem.(trashItem)
+	if !ok {
+		return
+	}
+
+	kind := "task   "
+	if i.project != nil {
+		kind = "project"
+	}
+
+	style := allStyles.unfocusedTextStyle
+	if index == m.Index() {
+		style = allStyles.focusedTextStyle
+	}
+
+	deleted := i.deletedAt().In(time.Local).Format(detailTimeLayout)
+	str := style.Render(fmt.Sprintf("%s %s", kind, i.title()))
+	_, _ = fmt.Fprint(w, str+" "+allStyles.unfocusedTextStyle.Faint(true).Render("deleted "+deleted))
+}
+
+func initialTrashList() list.Model {
+	ls := list.New(nil, trashItemDelegate{}, 10, 10)
+	ls.SetShowStatusBar(false)
+	ls.SetShowTitle(false)
+	ls.SetShowHelp(false)
+	ls.SetFilteringEnabled(false)
+	return ls
+}
+
+func (m *model) createTrashBox() titlebox.Box {
+	boxStyle := allStyles.unfocusedBoxStyle
+	titleStyle := allStyles.unfocusedBoxTitleStyle
+	if m.currentFocus == focusTrash {
+		boxStyle = allStyles.focusedBoxStyle
+		titleStyle = allStyles.focusedBoxTitleStyle
+	}
+
+	border := boxStyle.GetBorderStyle().Top
+	body := m.trashList.View()
+	if len(m.trashList.Items()) == 0 {
+		body = allStyles.unfocusedTextStyle.Render("The trash is empty.")
+	}
+
+	return titlebox.New().
+		SetTitle("[2]" + border + "trash").
+		SetBody(body).
+		SetTitleAlignment(titlebox.AlignLeft).
+		SetBoxStyle(boxStyle.Padding(0, 1)).
+		SetTitleStyle(titleStyle)
+}
+
+// toggleTrash swaps the task list for the trash view, which takes over its focus key.
+func (m *model) toggleTrash() tea.Cmd {
+	m.showTrash = !m.showTrash
+	if m.showTrash {
+		return tea.Batch(m.getTrash(), changeFocus(focusTrash))
+	}
+
+	return changeFocus(focusTasks)
+}
+
+// getTrash lists everything that was trashed on its own, newest first. Items trashed
+// along with a parent are left out, since they come back when it is restored.
+func (m *model) getTrash() tea.Cmd {
+	return func() tea.Msg {
+		ctx := context.Background()
+		projects, err := m.stores.Projects.ListTrashed(ctx)
+		if err != nil {
+			return storeErrorMsg{fmt.Errorf("listing trashed projects: %w", err)}
+		}
+
+		tasks, err := m.stores.Tasks.ListTrashed(ctx)
+		if err != nil {
+			return storeErrorMsg{fmt.Errorf("listing trashed tasks: %w", err)}
+		}
+
+		var items []trashItem
+		for _, p := range projects {
+			items = append(items, trashItem{project: p})
+		}
+		for _, t := range tasks {
+			items = append(items, trashItem{task: t})
+		}
+
+		slices.SortStableFunc(items, func(a, b trashItem) int {
+			return cmp.Compare(b.deletedAt().UnixNano(), a.deletedAt().UnixNano())
+		})
+
+		li := make([]list.Item, len(items))
+		for i, item := range items {
+			li[i] = item
+		}
+		return gotTrashMsg{items: li}
+	}
+}
+
+func (m *model) selectedTrashItem() (trashItem, bool) {
+	t, ok := m.trashList.SelectedItem().(trashItem)
+	return t, ok
+}
+
+// restoreTrashItem brings back the selected item. Anything it belongs to has to be
+// restored first, or it would come back somewhere it can't be seen.
+func (m *model) restoreTrashItem() tea.Cmd {
+	item, ok := m.selectedTrashItem()
+	if !ok {
+		return nil
+	}
+
+	return func() tea.Msg {
+		ctx := context.Background()
+		if p := item.project; p != nil {
+			if p.ParentID != nil {
+				parent, err := m.stores.Projects.Get(ctx, *p.ParentID)
+				if err != nil {
+					return storeErrorMsg{fmt.Errorf("getting project %d: %w", *p.ParentID, err)}
+				}
+				if parent.DeletedAt != nil {
+					return storeErrorMsg{fmt.Errorf("can't restore %q: its parent project %q is in the trash", p.Title, parent.Title)}
+				}
+			}
+
+			if err := m.stores.Projects.Restore(ctx, p); err != nil {
+				return storeErrorMsg{fmt.Errorf("restoring project %d: %w", p.ID, err)}
+			}
+			return refreshAllMsg{info: fmt.Sprintf("restored project %q", p.Title)}
+		}
+
+		t := item.task
+		if t.ProjectID != nil {
+			project, err := m.stores.Projects.Get(ctx, *t.ProjectID)
+			if err != nil {
+				return storeErrorMsg{fmt.Errorf("getting project %d: %w", *t.ProjectID, err)}
+			}
+			if project.DeletedAt != nil {
+				return storeErrorMsg{fmt.Errorf("can't restore %q: its project %q is in the trash", t.Title, project.Title)}
+			}
+		}
+
+		if t.ParentTaskID != nil {
+			parent, err := m.stores.Tasks.Get(ctx, *t.ParentTaskID)
+			if err != nil {
+				return storeErrorMsg{fmt.Errorf("getting task %d: %w", *t.ParentTaskID, err)}
+			}
+			if parent.DeletedAt != nil {
+				return storeErrorMsg{fmt.Errorf("can't restore %q: its parent task %q is in the trash", t.Title, parent.Title)}
+			}
+		}
+
+		if err := m.stores.Tasks.Restore(ctx, t); err != nil {
+			return storeErrorMsg{fmt.Errorf("restoring task %d: %w", t.ID, err)}
+		}
+		return refreshAllMsg{info: fmt.Sprintf("restored task %q", t.Title)}
+	}
+}
+
+// purgeTrashItem permanently deletes the selected item, along with everything that was
+// trashed with it, once confirmed.
+func (m *model) purgeTrashItem() {
+	item, ok := m.selectedTrashItem()
+	if !ok {
+		return
+	}
+
+	kind := "task"
+	if item.project != nil {
+		kind = "project"
+	}
+
+	body := fmt.Sprintf("Permanently delete %s %q? This can't be undone.", kind, item.title())
+	m.confirm("purgeTrashItem", "delete forever", body, "delete", func() tea.Msg {
+		ctx := context.Background()
+		if p := item.project; p != nil {
+			if err := m.stores.Projects.Delete(ctx, p.ID); err != nil {
+				return storeErrorMsg{fmt.Errorf("deleting project %d: %w", p.ID, err)}
+			}
+			return refreshAllMsg{info: fmt.Sprintf("permanently deleted project %q", p.Title)}
+		}
+
+		if err := m.stores.Tasks.Delete(ctx, item.task.ID); err != nil {
+			return storeErrorMsg{fmt.Errorf("deleting task %d: %w", item.task.ID, err)}
+		}
+		return refreshAllMsg{info: fmt.Sprintf("permanently deleted task %q", item.task.Title)}
+	})
+}
+
+func (m *model) emptyTrash() {
+	n := len(m.trashList.Items())
+	if n == 0 {
+		return
+	}
+
+	body := fmt.Sprintf("Permanently delete %d %s in the trash? This can't be undone.", n, plural(n, "item"))
+	m.confirm("emptyTrash", "empty trash", body, "empty", func() tea.Msg {
+		if _, err := m.stores.PurgeTrash(context.Background(), time.Now()); err != nil {
+			return storeErrorMsg{fmt.Errorf("emptying trash: %w", err)}
+		}
+
+		return refreshAllMsg{info: "trash emptied"}
+	})
+}
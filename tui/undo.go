@@ -0,0 +1,371 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dsrosen6/yata/models"
+)
+
+const maxHistory = 100
+
+type (
+	// change is a reversible store mutation. apply makes the change, both the first time
+	// and on redo, and revert undoes it.
+	change interface {
+		apply(ctx context.Context, s *models.AllRepos) error
+		revert(ctx context.Context, s *models.AllRepos) error
+		String() string
+	}
+
+	// history holds the changes that can be undone and, after an undo, redone.
+	history struct {
+		undo []change
+		redo []change
+	}
+
+	// appliedMsg records a change made for the first time, then hands msg on as usual.
+	appliedMsg struct {
+		change change
+		msg    tea.Msg
+	}
+	undoneMsg struct{ change change }
+	redoneMsg struct{ change change }
+
+	// taskCreate creates a task with tags. Undoing it moves the task to the trash, and
+	// redoing restores it, so it keeps its ID for the changes recorded after it.
+	taskCreate struct {
+		task *models.Task
+		tags []string
+	}
+	taskUpdate struct{ before, after *models.Task }
+	taskTrash  struct {
+		id    int64
+		title string
+	}
+	taskTags struct {
+		id            int64
+		title         string
+		before, after []string
+	}
+	// taskArchive archives the tasks completed before a time. They're stamped with the
+	// same time, so undoing it only brings back the ones it archived.
+	taskArchive struct {
+		completedBefore, at time.Time
+		count               int64
+	}
+	// taskReorder moves a task among its siblings. It holds the positions of every task
+	// that changed, which is usually just the one that moved.
+	taskReorder struct {
+		title         string
+		before, after map[int64]float64
+	}
+
+	projectCreate struct{ project *models.Project }
+	projectUpdate struct{ before, after *models.Project }
+	projectTrash  struct {
+		id    int64
+		title string
+	}
+	projectReorder struct {
+		title         string
+		before, after map[int64]float64
+	}
+
+	// changeSet is several changes made as one, like completing a task along with its
+	// subtasks, in a single transaction. It's described by its first change.
+	changeSet []change
+)
+
+func (h *history) push(c change) {
+	h.undo = append(h.undo, c)
+	if len(h.undo) > maxHistory {
+		h.undo = h.undo[len(h.undo)-maxHistory:]
+	}
+	h.redo = nil
+}
+
+// applyChange makes a change and records it, returning msg once it's on the undo stack.
+func (m *model) applyChange(ctx context.Context, c change, msg tea.Msg) tea.Msg {
+	if err := c.apply(ctx, m.stores); err != nil {
+		return storeErrorMsg{fmt.Errorf("%s: %w", c, err)}
+	}
+
+	return appliedMsg{change: c, msg: msg}
+}
+
+func (m *model) undo() tea.Cmd {
+	if len(m.history.undo) == 0 {
+		return infoStatus("nothing to undo")
+	}
+
+	c := m.history.undo[len(m.history.undo)-1]
+	m.history.undo = m.history.undo[:len(m.history.undo)-1]
+	return func() tea.Msg {
+		if err := c.revert(context.Background(), m.stores); err != nil {
+			return storeErrorMsg{fmt.Errorf("undoing %s: %w", c, err)}
+		}
+
+		return undoneMsg{c}
+	}
+}
+
+func (m *model) redo() tea.Cmd {
+	if len(m.history.redo) == 0 {
+		return infoStatus("nothing to redo")
+	}
+
+	c := m.history.redo[len(m.history.redo)-1]
+	m.history.redo = m.history.redo[:len(m.history.redo)-1]
+	return func() tea.Msg {
+		if err := c.apply(context.Background(), m.stores); err != nil {
+			return storeErrorMsg{fmt.Errorf("redoing %s: %w", c, err)}
+		}
+
+		return redoneMsg{c}
+	}
+}
+
+func (c *taskCreate) apply(ctx context.Context, s *models.AllRepos) error {
+	if c.task.ID != 0 {
+		return restoreTask(ctx, s, c.task.ID)
+	}
+
+	created, err := s.Tasks.Create(ctx, c.task)
+	if err != nil {
+		return err
+	}
+	c.task = created
+
+	for _, t := range c.tags {
+		if err := s.Tags.AddToTask(ctx, created.ID, t); err != nil {
+			return fmt.Errorf("adding tag %q: %w", t, err)
+		}
+	}
+
+	return nil
+}
+
+func (c *taskCreate) revert(ctx context.Context, s *models.AllRepos) error {
+	return s.Tasks.Trash(ctx, c.task.ID)
+}
+
+func (c *taskCreate) String() string {
+	return fmt.Sprintf("create task %q", c.task.Title)
+}
+
+func (c taskUpdate) apply(ctx context.Context, s *models.AllRepos) error {
+	_, err := s.Tasks.Update(ctx, c.after)
+	return err
+}
+
+func (c taskUpdate) revert(ctx context.Context, s *models.AllRepos) error {
+	_, err := s.Tasks.Update(ctx, c.before)
+	return err
+}
+
+func (c taskUpdate) String() string {
+	verb := "edit"
+	switch {
+	case c.before.Complete != c.after.Complete && c.after.Complete:
+		verb = "complete"
+	case c.before.Complete != c.after.Complete:
+		verb = "uncomplete"
+	case !models.SameID(c.before.ParentTaskID, c.after.ParentTaskID), !models.SameID(c.before.ProjectID, c.after.ProjectID):
+		verb = "move"
+	}
+
+	return fmt.Sprintf("%s task %q", verb, c.after.Title)
+}
+
+func (c taskTrash) apply(ctx context.Context, s *models.AllRepos) error {
+	return s.Tasks.Trash(ctx, c.id)
+}
+
+func (c taskTrash) revert(ctx context.Context, s *models.AllRepos) error {
+	return restoreTask(ctx, s, c.id)
+}
+
+func (c taskTrash) String() string {
+	return fmt.Sprintf("trash task %q", c.title)
+}
+
+func (c taskTags) apply(ctx context.Context, s *models.AllRepos) error {
+	return setTags(ctx, s, c.id, c.before, c.after)
+}
+
+func (c taskTags) revert(ctx context.Context, s *models.AllRepos) error {
+	return setTags(ctx, s, c.id, c.after, c.before)
+}
+
+func (c taskTags) String() string {
+	return fmt.Sprintf("tag task %q", c.title)
+}
+
+func (c *taskArchive) apply(ctx context.Context, s *models.AllRepos) error {
+	n, err := s.Tasks.Archive(ctx, c.completedBefore, c.at)
+	if err != nil {
+		return err
+	}
+
+	c.count = n
+	return nil
+}
+
+func (c *taskArchive) revert(ctx context.Context, s *models.AllRepos) error {
+	_, err := s.Tasks.Unarchive(ctx, c.at)
+	return err
+}
+
+func (c *taskArchive) String() string {
+	return fmt.Sprintf("archive %d completed %s", c.count, plural(int(c.count), "task"))
+}
+
+func (c taskReorder) apply(ctx context.Context, s *models.AllRepos) error {
+	return setPositions(ctx, s, c.after, taskPositions)
+}
+
+func (c taskReorder) revert(ctx context.Context, s *models.AllRepos) error {
+	return setPositions(ctx, s, c.before, taskPositions)
+}
+
+func (c taskReorder) String() string {
+	return fmt.Sprintf("reorder task %q", c.title)
+}
+
+func (c *projectCreate) apply(ctx context.Context, s *models.AllRepos) error {
+	if c.project.ID != 0 {
+		return restoreProject(ctx, s, c.project.ID)
+	}
+
+	created, err := s.Projects.Create(ctx, c.project)
+	if err != nil {
+		return err
+	}
+
+	c.project = created
+	return nil
+}
+
+func (c *projectCreate) revert(ctx context.Context, s *models.AllRepos) error {
+	return s.Projects.Trash(ctx, c.project.ID)
+}
+
+func (c *projectCreate) String() string {
+	return fmt.Sprintf("create project %q", c.project.Title)
+}
+
+func (c projectUpdate) apply(ctx context.Context, s *models.AllRepos) error {
+	_, err := s.Projects.Update(ctx, c.after)
+	return err
+}
+
+func (c projectUpdate) revert(ctx context.Context, s *models.AllRepos) error {
+	_, err := s.Projects.Update(ctx, c.before)
+	return err
+}
+
+func (c projectUpdate) String() string {
+	verb := "edit"
+	if !models.SameID(c.before.ParentID, c.after.ParentID) {
+		verb = "move"
+	}
+
+	return fmt.Sprintf("%s project %q", verb, c.after.Title)
+}
+
+func (c projectTrash) apply(ctx context.Context, s *models.AllRepos) error {
+	return s.Projects.Trash(ctx, c.id)
+}
+
+func (c projectTrash) revert(ctx context.Context, s *models.AllRepos) error {
+	return restoreProject(ctx, s, c.id)
+}
+
+func (c projectTrash) String() string {
+	return fmt.Sprintf("trash project %q", c.title)
+}
+
+func (c projectReorder) apply(ctx context.Context, s *models.AllRepos) error {
+	return setPositions(ctx, s, c.after, projectPositions)
+}
+
+func (c projectReorder) revert(ctx context.Context, s *models.AllRepos) error {
+	return setPositions(ctx, s, c.before, projectPositions)
+}
+
+func (c projectReorder) String() string {
+	return fmt.Sprintf("reorder project %q", c.title)
+}
+
+func (cs changeSet) apply(ctx context.Context, s *models.AllRepos) error {
+	return s.InTx(ctx, func(s *models.AllRepos) error {
+		for _, c := range cs {
+			if err := c.apply(ctx, s); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (cs changeSet) revert(ctx context.Context, s *models.AllRepos) error {
+	return s.InTx(ctx, func(s *models.AllRepos) error {
+		for i := len(cs) - 1; i >= 0; i-- {
+			if err := cs[i].revert(ctx, s); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (cs changeSet) String() string {
+	if len(cs) == 0 {
+		return "no changes"
+	}
+	return cs[0].String()
+}
+
+// restoreTask restores a task by ID, which needs its current deletion time.
+func restoreTask(ctx context.Context, s *models.AllRepos, id int64) error {
+	t, err := s.Tasks.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return s.Tasks.Restore(ctx, t)
+}
+
+func restoreProject(ctx context.Context, s *models.AllRepos, id int64) error {
+	p, err := s.Projects.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return s.Projects.Restore(ctx, p)
+}
+
+type positionSetter func(ctx context.Context, id int64, position float64) error
+
+// setPositions sets the positions of the tasks or projects whose setter repo picks, in one
+// transaction, so a renumbering is never left half done with positions that tie.
+func setPositions(ctx context.Context, s *models.AllRepos, positions map[int64]float64, repo func(s *models.AllRepos) positionSetter) error {
+	return s.InTx(ctx, func(s *models.AllRepos) error {
+		set := repo(s)
+		for id, p := range positions {
+			if err := set(ctx, id, p); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func taskPositions(s *models.AllRepos) positionSetter    { return s.Tasks.SetPosition }
+func projectPositions(s *models.AllRepos) positionSetter { return s.Projects.SetPosition }
@@ -0,0 +1,219 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dsrosen6/yata/filter"
+	"github.com/dsrosen6/yata/models"
+	"github.com/dsrosen6/yata/tui/models/form"
+	"github.com/dsrosen6/yata/tui/render/titlebox"
+)
+
+const viewMarker = "•"
+
+// view is an entry in the project list that isn't a project, like today. It lists the
+// tasks matching its filter from every project, or every task if it has no filter.
+// Built-in views have no ID.
+type view struct {
+	id     int64
+	name   string
+	filter string
+
+	// history lists completed tasks by the day they were finished, archived ones included.
+	history bool
+}
+
+// viewSavedMsg selects a view that was just created or edited.
+type viewSavedMsg struct {
+	view view
+	info string
+}
+
+var (
+	allView   = view{name: "all"}
+	inboxView = view{name: "inbox", filter: "project:none"}
+
+	builtinViews = []view{
+		allView,
+		inboxView,
+		{name: "today", filter: "due:today"},
+		{name: "upcoming", filter: "due>today and due<=+7d"},
+		{name: "overdue", filter: "due<today and not done"},
+		{name: "completed", history: true},
+	}
+)
+
+// allViews is the built-in views followed by the saved ones.
+func allViews(saved []*models.View) []view {
+	views := append([]view{}, builtinViews...)
+	for _, v := range saved {
+		views = append(views, view{id: v.ID, name: v.Name, filter: v.Filter})
+	}
+
+	return views
+}
+
+func (v view) saved() bool {
+	return v.id != 0
+}
+
+func (v view) tasks(ctx context.Context, s *models.AllRepos) ([]*models.Task, error) {
+	if v.history {
+		return s.Tasks.ListCompleted(ctx)
+	}
+
+	if v.filter == "" {
+		return s.Tasks.ListAll(ctx)
+	}
+
+	f, err := filter.Parse(v.filter)
+	if err != nil {
+		return nil, fmt.Errorf("view %q: %w", v.name, err)
+	}
+
+	return s.Tasks.Query(ctx, f)
+}
+
+// selectView selects a view in the project list. Saved views are found by ID, so one
+// that was just renamed is still found.
+func (m *model) selectView(v view) {
+	for i, item := range m.projectList.Items() {
+		p, ok := item.(taskProjectItem)
+		if !ok || p.view == nil {
+			continue
+		}
+
+		if (v.saved() && p.view.id == v.id) || (!v.saved() && *p.view == v) {
+			m.projectList.Select(i)
+			return
+		}
+	}
+}
+
+// selectedView returns the view selected in the project list, if a view is selected.
+func (m *model) selectedView() *view {
+	sel, ok := m.projectList.SelectedItem().(taskProjectItem)
+	if !ok {
+		return nil
+	}
+
+	return sel.view
+}
+
+func newViewEntryForm() (*form.Model, error) {
+	validateName := func(s string) error {
+		for _, v := range builtinViews {
+			if strings.EqualFold(strings.TrimSpace(s), v.name) {
+				return fmt.Errorf("%q is a built-in view", v.name)
+			}
+		}
+		return nil
+	}
+
+	validateFilter := func(s string) error {
+		if strings.TrimSpace(s) == "" {
+			return nil
+		}
+		_, err := filter.Parse(s)
+		return err
+	}
+
+	fields := []form.Field{
+		{
+			Key:      "name",
+			Required: true,
+			Validate: validateName,
+		},
+		{
+			Key:      "filter",
+			Required: true,
+			Validate: validateFilter,
+		},
+	}
+	o := &form.Opts{
+		Fields:           fields,
+		PromptIfOneField: true,
+		FocusedStyle:     allStyles.focusedTextStyle,
+		UnfocusedStyle:   allStyles.unfocusedTextStyle,
+		ErrorStyle:       allStyles.errorTextStyle,
+	}
+
+	f, err := form.InitialInputModel(o)
+	if err != nil {
+		return nil, fmt.Errorf("creating model: %w", err)
+	}
+
+	return f, nil
+}
+
+func (m *model) createViewEntryBox() titlebox.Box {
+	title := "new view"
+	if m.editingView != nil {
+		title = "edit view " + m.editingView.name
+	}
+
+	return titlebox.New().
+		SetTitle(title).
+		SetBody(m.viewEntryForm.View()).
+		SetTitleAlignment(titlebox.AlignLeft).
+		SetBoxStyle(allStyles.focusedBoxStyle).
+		SetTitleStyle(allStyles.focusedBoxTitleStyle)
+}
+
+// newView opens the view entry, starting from the task filter if one is set, so a filter
+// that's been tried out can be kept.
+func (m *model) newView() tea.Cmd {
+	if m.taskFilter != nil {
+		m.viewEntryForm.SetValues(form.Result{"filter": m.taskFilter.String()})
+	}
+
+	return tea.Batch(m.viewEntryForm.Init(), changeFocus(focusViewEntry))
+}
+
+func (m *model) editView(v view) tea.Cmd {
+	m.editingView = &v
+	m.viewEntryForm.SetValues(form.Result{"name": v.name, "filter": v.filter})
+	return tea.Batch(m.viewEntryForm.Init(), changeFocus(focusViewEntry))
+}
+
+func (m *model) saveView(r form.Result) tea.Cmd {
+	editing := m.editingView
+	return func() tea.Msg {
+		ctx := context.Background()
+		v := &models.View{Name: r["name"], Filter: r["filter"]}
+
+		var (
+			saved *models.View
+			err   error
+			info  = fmt.Sprintf("view %q created", v.Name)
+		)
+		if editing != nil {
+			v.ID = editing.id
+			saved, err = m.stores.Views.Update(ctx, v)
+			info = fmt.Sprintf("view %q saved", v.Name)
+		} else {
+			saved, err = m.stores.Views.Create(ctx, v)
+		}
+
+		if err != nil {
+			return storeErrorMsg{fmt.Errorf("saving view %q: %w", v.Name, err)}
+		}
+
+		return viewSavedMsg{view: view{id: saved.ID, name: saved.Name, filter: saved.Filter}, info: info}
+	}
+}
+
+// deleteView deletes a saved view, once confirmed. The tasks it lists aren't affected.
+func (m *model) deleteView(v view) {
+	body := fmt.Sprintf("Delete the view %q? Its tasks won't be changed.", v.name)
+	m.confirm("deleteView", "delete view", body, "delete", func() tea.Msg {
+		if err := m.stores.Views.Delete(context.Background(), v.id); err != nil {
+			return storeErrorMsg{fmt.Errorf("deleting view %q: %w", v.name, err)}
+		}
+
+		return refreshProjectsMsg{info: fmt.Sprintf("view %q deleted", v.name)}
+	})
+}
@@ -0,0 +1,81 @@
+package tui
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// changePollInterval is how often the database is checked for changes made elsewhere,
+// like by the CLI or another yata window.
+const changePollInterval = time.Second
+
+type (
+	checkChangesMsg struct{}
+
+	dataVersionMsg struct {
+		version int64
+		err     error
+	}
+)
+
+// watchChanges starts polling the database for outside changes. It does nothing if the
+// store can't report them.
+func (m *model) watchChanges() tea.Cmd {
+	if m.stores.Changes == nil {
+		return nil
+	}
+
+	return tea.Tick(changePollInterval, func(time.Time) tea.Msg { return checkChangesMsg{} })
+}
+
+func (m *model) checkDataVersion() tea.Cmd {
+	return func() tea.Msg {
+		v, err := m.stores.Changes.DataVersion(context.Background())
+		return dataVersionMsg{version: v, err: err}
+	}
+}
+
+// handleDataVersion reloads everything if another process has changed the database since
+// the last check, and schedules the next one.
+func (m *model) handleDataVersion(msg dataVersionMsg) tea.Cmd {
+	if msg.err != nil {
+		// not worth interrupting anyone over, the next check tries again
+		slog.Warn("checking for database changes", "error", msg.err)
+		return m.watchChanges()
+	}
+
+	changed := m.seenDataVersion && msg.version != m.dataVersion
+	m.dataVersion, m.seenDataVersion = msg.version, true
+	if !changed {
+		return m.watchChanges()
+	}
+
+	slog.Debug("database changed, reloading", "data_version", msg.version)
+	return tea.Batch(
+		m.getTrash(),
+		keepSelection(m.refreshProjects(0)),
+		keepSelection(m.getUpdatedTasks(m.currentProjectID, 0)),
+		m.watchChanges(),
+	)
+}
+
+// keepSelection marks the lists a refresh returns to keep whatever is selected when they
+// arrive, rather than what was selected when the refresh started, since a reload nobody
+// asked for shouldn't undo moving around in the meantime.
+func keepSelection(cmd tea.Cmd) tea.Cmd {
+	return func() tea.Msg {
+		switch msg := cmd().(type) {
+		case gotUpdatedTasksMsg:
+			msg.keepSelection = true
+			return msg
+		case gotUpdatedProjectsMsg:
+			msg.keepSelection = true
+			return msg
+		default:
+			return msg
+		}
+	}
+}